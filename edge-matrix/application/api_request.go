@@ -0,0 +1,141 @@
+package application
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DefaultHeaderAllowlist is the list of headers passed between caller and app
+// when no allowlist is configured
+var DefaultHeaderAllowlist = []string{"Content-Type", "Accept"}
+
+// supportedApiMethods are the http methods the endpoint /api proxies to the app
+var supportedApiMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// ApiRequest is the edge call input handled by the endpoint /api
+type ApiRequest struct {
+	Method string `json:"method"`
+	// request headers as "Key: Value" strings
+	Headers []string          `json:"headers"`
+	Path    string            `json:"path"`
+	Query   map[string]string `json:"query,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+	Stream  bool              `json:"stream,omitempty"`
+	// hash of the request body pushed to the provider blob store, instead of Body
+	BodyBlob string `json:"bodyBlob,omitempty"`
+	// return a large response body in the provider blob store, referenced by the response
+	BlobResponse bool `json:"blobResponse,omitempty"`
+}
+
+// method returns the upper cased http method, defaulting
+// to POST for requests with body and GET otherwise
+func (r *ApiRequest) method() string {
+	if r.Method != "" {
+		return strings.ToUpper(r.Method)
+	}
+
+	if len(r.Body) > 0 {
+		return http.MethodPost
+	}
+
+	return http.MethodGet
+}
+
+// url returns the app url of the request, merging the query parameters
+// into the ones already present in the path
+func (r *ApiRequest) url(appUrl string) (string, error) {
+	u, err := url.Parse(appUrl + r.Path)
+	if err != nil {
+		return "", err
+	}
+
+	if len(r.Query) > 0 {
+		query := u.Query()
+		for key, value := range r.Query {
+			query.Set(key, value)
+		}
+
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String(), nil
+}
+
+// newHeaderAllowlist returns the set of canonical header keys
+func newHeaderAllowlist(headers []string) map[string]bool {
+	allowlist := make(map[string]bool, len(headers))
+	for _, header := range headers {
+		allowlist[http.CanonicalHeaderKey(strings.TrimSpace(header))] = true
+	}
+
+	return allowlist
+}
+
+// filterRequestHeaders parses "Key: Value" strings and keeps the allowed ones
+func filterRequestHeaders(headers []string, allowlist map[string]bool) map[string]string {
+	filtered := make(map[string]string)
+
+	for _, header := range headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key := http.CanonicalHeaderKey(strings.TrimSpace(parts[0]))
+		if allowlist[key] {
+			filtered[key] = strings.TrimSpace(parts[1])
+		}
+	}
+
+	return filtered
+}
+
+// formatResponseHeaders returns the allowed headers as "Key: Value" strings sorted by key
+func formatResponseHeaders(headers map[string]string, allowlist map[string]bool) []string {
+	formatted := make([]string, 0, len(headers))
+
+	for key, value := range headers {
+		key = http.CanonicalHeaderKey(key)
+		if allowlist[key] {
+			formatted = append(formatted, fmt.Sprintf("%s: %s", key, value))
+		}
+	}
+
+	sort.Strings(formatted)
+
+	return formatted
+}
+
+// errorEdgeResponse returns an edge response for a request the app could not answer.
+// Schema errors are answered with the JSON encoded SchemaError.
+func errorEdgeResponse(statusCode int, err error) *EdgeResponse {
+	var schemaErr *SchemaError
+	if errors.As(err, &schemaErr) {
+		if body, jsonErr := json.Marshal(schemaErr); jsonErr == nil {
+			return &EdgeResponse{
+				RespString: base64.StdEncoding.EncodeToString(body),
+				StatusCode: uint64(statusCode),
+				Headers:    []string{"Content-Type: application/json"},
+			}
+		}
+	}
+
+	return &EdgeResponse{
+		RespString: base64.StdEncoding.EncodeToString([]byte("endpoint err: " + err.Error())),
+		StatusCode: uint64(statusCode),
+	}
+}
@@ -0,0 +1,79 @@
+package application
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/emc-protocol/edge-matrix/chain"
+	"github.com/emc-protocol/edge-matrix/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiRequest_Method(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, http.MethodGet, (&ApiRequest{}).method())
+	assert.Equal(t, http.MethodPost, (&ApiRequest{Body: json.RawMessage(`{}`)}).method())
+	assert.Equal(t, http.MethodDelete, (&ApiRequest{Method: "delete"}).method())
+}
+
+func TestApiRequest_Url(t *testing.T) {
+	t.Parallel()
+
+	req := &ApiRequest{
+		Path:  "/v1/items?page=1",
+		Query: map[string]string{"size": "10"},
+	}
+
+	u, err := req.url("http://127.0.0.1:9527")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:9527/v1/items?page=1&size=10", u)
+}
+
+func TestHeaderAllowlist(t *testing.T) {
+	t.Parallel()
+
+	allowlist := newHeaderAllowlist([]string{"content-type", "X-Request-Id"})
+
+	assert.Equal(t,
+		map[string]string{"Content-Type": "application/json", "X-Request-Id": "1"},
+		filterRequestHeaders([]string{"content-type: application/json", "x-request-id:1", "Cookie: a=b", "invalid"}, allowlist),
+	)
+	assert.Equal(t,
+		[]string{"Content-Type: text/plain", "X-Request-Id: 1"},
+		formatResponseHeaders(map[string]string{"x-request-id": "1", "Content-Type": "text/plain", "Set-Cookie": "a=b"}, allowlist),
+	)
+}
+
+func TestEdgeResponse_StatusCodeSigned(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	signer := NewEIP155Signer(chain.AllForksEnabled.At(0), 2)
+	signedResp, err := signer.SignEdgeResp(&EdgeResponse{
+		RespString: "bm90IGZvdW5k",
+		StatusCode: http.StatusNotFound,
+		Headers:    []string{"Content-Type: text/plain"},
+	}, key)
+	assert.NoError(t, err)
+
+	resp := &EdgeResponse{}
+	assert.NoError(t, resp.UnmarshalRLP(signedResp.MarshalRLP()))
+	assert.Equal(t, uint64(http.StatusNotFound), resp.StatusCode)
+	assert.Equal(t, []string{"Content-Type: text/plain"}, resp.Headers)
+	assert.True(t, resp.IsAppError())
+
+	provider, err := signer.Provider(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubKeyToAddress(&key.PublicKey), provider)
+
+	// the status code is covered by the signature
+	resp.StatusCode = http.StatusOK
+	provider, err = signer.Provider(resp)
+	if err == nil {
+		assert.NotEqual(t, crypto.PubKeyToAddress(&key.PublicKey), provider)
+	}
+}
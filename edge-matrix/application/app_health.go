@@ -0,0 +1,133 @@
+package application
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// AppHealth is the state of the app served by the endpoint
+type AppHealth string
+
+const (
+	// the app has not been checked yet
+	AppHealthUnknown AppHealth = "unknown"
+	// the app is bound to this node and serves requests
+	AppHealthHealthy AppHealth = "healthy"
+	// the app is reachable but bound to another node
+	AppHealthUnbound AppHealth = "unbound"
+	// the app is unreachable
+	AppHealthDown AppHealth = "down"
+)
+
+const (
+	// appHealthTTL is how long a health check is trusted. Checks are refreshed by the
+	// app status loop, requests only check the app themselves if the loop fell behind.
+	appHealthTTL = 2 * DefaultAppStatusSyncDuration
+)
+
+var (
+	ErrAppUnbound = errors.New("app is not bound to this node")
+	ErrAppDown    = errors.New("app is unreachable")
+)
+
+// AppHealthStatus is the result of the last health check of the app
+type AppHealthStatus struct {
+	Health AppHealth `json:"health"`
+	// unix time of the check, in seconds
+	CheckedAt int64 `json:"checked_at"`
+	// check error of an app down
+	Error string `json:"error,omitempty"`
+}
+
+// Err returns the error requests are answered with, nil if the app is healthy
+func (s AppHealthStatus) Err() error {
+	switch s.Health {
+	case AppHealthHealthy:
+		return nil
+	case AppHealthUnbound:
+		return ErrAppUnbound
+	default:
+		return ErrAppDown
+	}
+}
+
+// appHealthCache keeps the last health check of the app
+type appHealthCache struct {
+	sync.RWMutex
+	status AppHealthStatus
+
+	// serializes the checks, so that requests do not check the app concurrently
+	checkLock sync.Mutex
+}
+
+func (c *appHealthCache) get() AppHealthStatus {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.status
+}
+
+func (c *appHealthCache) set(status AppHealthStatus) AppHealthStatus {
+	c.Lock()
+	defer c.Unlock()
+
+	previous := c.status
+	c.status = status
+
+	return previous
+}
+
+// isFresh returns true if the status was checked less than appHealthTTL before now
+func (s AppHealthStatus) isFresh(now time.Time) bool {
+	return s.Health != "" && s.Health != AppHealthUnknown && now.Sub(time.Unix(s.CheckedAt, 0)) < appHealthTTL
+}
+
+// AppHealth returns the health of the app, checking it if the cached state expired
+func (e *Endpoint) AppHealth() AppHealthStatus {
+	if status := e.health.get(); status.isFresh(time.Now()) {
+		return status
+	}
+
+	e.health.checkLock.Lock()
+	defer e.health.checkLock.Unlock()
+
+	// checked by a concurrent request meanwhile
+	if status := e.health.get(); status.isFresh(time.Now()) {
+		return status
+	}
+
+	return e.checkAppHealth()
+}
+
+// refreshAppHealth checks the app and caches its health
+func (e *Endpoint) refreshAppHealth() AppHealthStatus {
+	e.health.checkLock.Lock()
+	defer e.health.checkLock.Unlock()
+
+	return e.checkAppHealth()
+}
+
+// checkAppHealth checks the binding of the app to this node [NOT Thread Safe]
+func (e *Endpoint) checkAppHealth() AppHealthStatus {
+	status := AppHealthStatus{
+		Health:    AppHealthHealthy,
+		CheckedAt: time.Now().Unix(),
+	}
+
+	err, valid := e.validAppNode()
+
+	switch {
+	case err != nil:
+		status.Health = AppHealthDown
+		status.Error = err.Error()
+	case !valid:
+		status.Health = AppHealthUnbound
+	}
+
+	if previous := e.health.set(status); previous.Health != status.Health {
+		e.logger.Info("app health changed", "from", previous.Health, "to", status.Health, "err", status.Error)
+	}
+
+	return status
+}
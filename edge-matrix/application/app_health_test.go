@@ -0,0 +1,56 @@
+package application
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpoint_AppHealth(t *testing.T) {
+	t.Parallel()
+
+	h, err := libp2p.New(libp2p.NoListenAddrs)
+	assert.NoError(t, err)
+
+	defer h.Close()
+
+	var (
+		boundNode atomic.Value
+		checks    int32
+	)
+
+	boundNode.Store(h.ID().String())
+
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&checks, 1)
+		fmt.Fprintf(w, `{"data":%q}`, boundNode.Load())
+	}))
+	defer app.Close()
+
+	e := &Endpoint{logger: hclog.NewNullLogger(), h: h, appUrl: app.URL}
+
+	// the first request checks the app, the next ones use the cached health
+	assert.Equal(t, AppHealthHealthy, e.AppHealth().Health)
+	assert.NoError(t, e.AppHealth().Err())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&checks))
+
+	// the app got bound to another node
+	boundNode.Store("other")
+	assert.Equal(t, AppHealthHealthy, e.AppHealth().Health)
+	assert.ErrorIs(t, e.refreshAppHealth().Err(), ErrAppUnbound)
+	assert.Equal(t, AppHealthUnbound, e.AppHealth().Health)
+
+	// the app is down
+	app.Close()
+
+	status := e.refreshAppHealth()
+	assert.Equal(t, AppHealthDown, status.Health)
+	assert.ErrorIs(t, status.Err(), ErrAppDown)
+	assert.NotEmpty(t, status.Error)
+}
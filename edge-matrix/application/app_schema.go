@@ -0,0 +1,398 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// schema value types, as in JSON schema
+const (
+	SchemaTypeObject  = "object"
+	SchemaTypeArray   = "array"
+	SchemaTypeString  = "string"
+	SchemaTypeNumber  = "number"
+	SchemaTypeInteger = "integer"
+	SchemaTypeBoolean = "boolean"
+	SchemaTypeNull    = "null"
+)
+
+var (
+	ErrAppSchemaInvalid     = errors.New("invalid app idl")
+	ErrApiOperationNotFound = errors.New("request not described by the app idl")
+	ErrAppResponseInvalid   = errors.New("app response does not conform to the app idl")
+)
+
+var (
+	schemaTypes = map[string]bool{
+		SchemaTypeObject:  true,
+		SchemaTypeArray:   true,
+		SchemaTypeString:  true,
+		SchemaTypeNumber:  true,
+		SchemaTypeInteger: true,
+		SchemaTypeBoolean: true,
+		SchemaTypeNull:    true,
+	}
+)
+
+// AppSchema is the typed IDL of an app: the requests it serves and their shapes.
+// An app without operations accepts any request.
+type AppSchema struct {
+	Operations []*ApiOperation `json:"operations"`
+}
+
+// ApiOperation describes an app request
+type ApiOperation struct {
+	// request path, segments like {id} match any value
+	Path string `json:"path"`
+	// http method, empty for any
+	Method      string `json:"method,omitempty"`
+	Description string `json:"description,omitempty"`
+	// shape of the request body, nil for any
+	Request *TypeSchema `json:"request,omitempty"`
+	// shape of the successful response body, nil for any
+	Response *TypeSchema `json:"response,omitempty"`
+}
+
+// TypeSchema is the shape of a JSON value, a subset of JSON schema
+type TypeSchema struct {
+	// value type, empty for any
+	Type        string                 `json:"type,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Properties  map[string]*TypeSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	// false to reject the object properties not described
+	AdditionalProperties *bool             `json:"additionalProperties,omitempty"`
+	Items                *TypeSchema       `json:"items,omitempty"`
+	Enum                 []json.RawMessage `json:"enum,omitempty"`
+}
+
+// SchemaError is a value not conforming to the app schema
+type SchemaError struct {
+	Path   string `json:"path"`
+	Method string `json:"method"`
+	// location of the value, like body.messages[0].role
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func (e *SchemaError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s %s: %s", e.Method, e.Path, e.Reason)
+	}
+
+	return fmt.Sprintf("%s %s: %s %s", e.Method, e.Path, e.Field, e.Reason)
+}
+
+// ParseAppSchema parses the IDL of an app, either the list of its operations or an object
+// with its operations. An empty IDL is an app schema without operations.
+func ParseAppSchema(idl []byte) (*AppSchema, error) {
+	schema := &AppSchema{}
+
+	idl = bytes.TrimSpace(idl)
+	if len(idl) == 0 {
+		return schema, nil
+	}
+
+	if idl[0] == '[' {
+		if err := json.Unmarshal(idl, &schema.Operations); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrAppSchemaInvalid, err.Error())
+		}
+	} else if err := json.Unmarshal(idl, schema); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAppSchemaInvalid, err.Error())
+	}
+
+	for _, op := range schema.Operations {
+		if op == nil || !strings.HasPrefix(op.Path, "/") {
+			return nil, fmt.Errorf("%w: operation path must start with /", ErrAppSchemaInvalid)
+		}
+
+		op.Method = strings.ToUpper(op.Method)
+
+		if err := op.Request.check(); err != nil {
+			return nil, fmt.Errorf("%w: %s request %s", ErrAppSchemaInvalid, op.Path, err.Error())
+		}
+
+		if err := op.Response.check(); err != nil {
+			return nil, fmt.Errorf("%w: %s response %s", ErrAppSchemaInvalid, op.Path, err.Error())
+		}
+	}
+
+	return schema, nil
+}
+
+// Operation returns the operation describing the request, nil if none
+func (s *AppSchema) Operation(req *ApiRequest) *ApiOperation {
+	path := req.Path
+	if u, err := url.Parse(req.Path); err == nil {
+		path = u.Path
+	}
+
+	method := req.method()
+
+	for _, op := range s.Operations {
+		if (op.Method == "" || op.Method == method) && matchPath(op.Path, path) {
+			return op
+		}
+	}
+
+	return nil
+}
+
+// ValidateRequest returns a SchemaError if the request does not conform to the schema
+func (s *AppSchema) ValidateRequest(req *ApiRequest) error {
+	if len(s.Operations) == 0 {
+		return nil
+	}
+
+	op := s.Operation(req)
+	if op == nil {
+		return &SchemaError{Path: req.Path, Method: req.method(), Reason: ErrApiOperationNotFound.Error()}
+	}
+
+	return op.Request.validateBody(req, req.Body)
+}
+
+// ValidateResponse returns a SchemaError if the successful response body of the request
+// does not conform to the schema
+func (s *AppSchema) ValidateResponse(req *ApiRequest, body []byte) error {
+	op := s.Operation(req)
+	if op == nil {
+		return nil
+	}
+
+	if err := op.Response.validateBody(req, body); err != nil {
+		return fmt.Errorf("%w: %s", ErrAppResponseInvalid, err.Error())
+	}
+
+	return nil
+}
+
+// SetResponseValidation sets whether the app responses are validated against the app schema.
+// Streamed responses are not validated.
+func (e *Endpoint) SetResponseValidation(enabled bool) {
+	e.validateResponses = enabled
+}
+
+// AppSchema returns the parsed idl of the app, nil until loaded
+func (e *Endpoint) AppSchema() *AppSchema {
+	e.Lock()
+	defer e.Unlock()
+
+	return e.schema
+}
+
+// appIdl returns the idl served by the app, falling back to the local idl.json
+func (e *Endpoint) appIdl() []byte {
+	err, appIdl := e.getAppIdl()
+	if err == nil && len(appIdl) > 0 {
+		return []byte(appIdl)
+	}
+
+	if err != nil {
+		e.logger.Debug(fmt.Sprintf("/getAppIdl =>resp: %s", err.Error()))
+	}
+
+	idlData, err := os.ReadFile("idl.json")
+	if err != nil {
+		return []byte("[]")
+	}
+
+	return idlData
+}
+
+// loadAppSchema parses the idl of the app, keeping the previous schema if the idl is invalid
+func (e *Endpoint) loadAppSchema() {
+	schema, err := ParseAppSchema(e.appIdl())
+	if err != nil {
+		e.logger.Warn("app idl not loaded", "err", err.Error())
+
+		return
+	}
+
+	e.Lock()
+	e.schema = schema
+	e.Unlock()
+}
+
+// validateResponse validates the successful app responses, if enabled
+func (e *Endpoint) validateResponse(req *ApiRequest, statusCode int, body []byte) error {
+	if !e.validateResponses || statusCode < 200 || statusCode >= 300 {
+		return nil
+	}
+
+	schema := e.AppSchema()
+	if schema == nil {
+		return nil
+	}
+
+	return schema.ValidateResponse(req, body)
+}
+
+// matchPath returns true if the path matches the operation path template
+func matchPath(template, path string) bool {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(templateSegments) != len(pathSegments) {
+		return false
+	}
+
+	for i, segment := range templateSegments {
+		isParam := strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+		if isParam && pathSegments[i] != "" {
+			continue
+		}
+
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// check returns an error if the schema uses unknown types
+func (t *TypeSchema) check() error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Type != "" && !schemaTypes[t.Type] {
+		return fmt.Errorf("unknown type %s", t.Type)
+	}
+
+	for name, property := range t.Properties {
+		if err := property.check(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return t.Items.check()
+}
+
+// validateBody validates the JSON body of the request or its response
+func (t *TypeSchema) validateBody(req *ApiRequest, body []byte) error {
+	if t == nil {
+		return nil
+	}
+
+	var value interface{}
+
+	if len(bytes.TrimSpace(body)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+
+		if err := decoder.Decode(&value); err != nil {
+			return &SchemaError{Path: req.Path, Method: req.method(), Field: "body", Reason: "is not valid json"}
+		}
+	}
+
+	if reason, field := t.validate(value, "body"); reason != "" {
+		return &SchemaError{Path: req.Path, Method: req.method(), Field: field, Reason: reason}
+	}
+
+	return nil
+}
+
+// validate returns why the decoded value does not conform to the schema and where, if it does not
+func (t *TypeSchema) validate(value interface{}, field string) (string, string) {
+	if t == nil {
+		return "", ""
+	}
+
+	if t.Type != "" && !t.hasType(value) {
+		return fmt.Sprintf("must be %s", t.Type), field
+	}
+
+	if len(t.Enum) > 0 && !t.inEnum(value) {
+		return "is not an allowed value", field
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, name := range t.Required {
+			if _, ok := value[name]; !ok {
+				return "is required", field + "." + name
+			}
+		}
+
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			property := value[name]
+
+			schema, ok := t.Properties[name]
+			if !ok {
+				if t.AdditionalProperties != nil && !*t.AdditionalProperties {
+					return "is not allowed", field + "." + name
+				}
+
+				continue
+			}
+
+			if reason, at := schema.validate(property, field+"."+name); reason != "" {
+				return reason, at
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			if reason, at := t.Items.validate(item, fmt.Sprintf("%s[%d]", field, i)); reason != "" {
+				return reason, at
+			}
+		}
+	}
+
+	return "", ""
+}
+
+func (t *TypeSchema) hasType(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return t.Type == SchemaTypeNull
+	case map[string]interface{}:
+		return t.Type == SchemaTypeObject
+	case []interface{}:
+		return t.Type == SchemaTypeArray
+	case string:
+		return t.Type == SchemaTypeString
+	case bool:
+		return t.Type == SchemaTypeBoolean
+	case json.Number:
+		if t.Type == SchemaTypeInteger {
+			_, err := value.Int64()
+
+			return err == nil
+		}
+
+		return t.Type == SchemaTypeNumber
+	default:
+		return false
+	}
+}
+
+func (t *TypeSchema) inEnum(value interface{}) bool {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range t.Enum {
+		compacted := new(bytes.Buffer)
+		if err := json.Compact(compacted, allowed); err == nil && bytes.Equal(compacted.Bytes(), raw) {
+			return true
+		}
+	}
+
+	return false
+}
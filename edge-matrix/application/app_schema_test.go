@@ -0,0 +1,130 @@
+package application
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testIdl = `[
+	{
+		"path": "/v1/chat",
+		"method": "post",
+		"request": {
+			"type": "object",
+			"required": ["prompt"],
+			"additionalProperties": false,
+			"properties": {
+				"prompt": {"type": "string"},
+				"max_tokens": {"type": "integer"},
+				"mode": {"enum": ["fast", "best"]},
+				"stop": {"type": "array", "items": {"type": "string"}}
+			}
+		},
+		"response": {"type": "object", "required": ["text"]}
+	},
+	{"path": "/v1/models/{id}", "method": "GET"}
+]`
+
+func TestParseAppSchema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := ParseAppSchema([]byte(testIdl))
+	assert.NoError(t, err)
+	assert.Len(t, schema.Operations, 2)
+	assert.Equal(t, http.MethodPost, schema.Operations[0].Method)
+
+	for _, idl := range []string{"", "[]", "{}"} {
+		schema, err := ParseAppSchema([]byte(idl))
+		assert.NoError(t, err)
+		assert.Empty(t, schema.Operations)
+		assert.NoError(t, schema.ValidateRequest(&ApiRequest{Path: "/any"}))
+	}
+
+	_, err = ParseAppSchema([]byte(`[{"path": "/v1/chat", "request": {"type": "text"}}]`))
+	assert.ErrorIs(t, err, ErrAppSchemaInvalid)
+
+	_, err = ParseAppSchema([]byte(`[{"path": "v1/chat"}]`))
+	assert.ErrorIs(t, err, ErrAppSchemaInvalid)
+}
+
+func TestAppSchema_ValidateRequest(t *testing.T) {
+	t.Parallel()
+
+	schema, err := ParseAppSchema([]byte(testIdl))
+	assert.NoError(t, err)
+
+	testTable := []struct {
+		name  string
+		req   *ApiRequest
+		field string
+	}{
+		{"valid", &ApiRequest{Path: "/v1/chat", Body: json.RawMessage(`{"prompt":"hi","max_tokens":16,"mode":"fast","stop":["\n"]}`)}, ""},
+		{"path parameter", &ApiRequest{Path: "/v1/models/sdxl?full=true"}, ""},
+		{"unknown path", &ApiRequest{Path: "/v1/embed", Body: json.RawMessage(`{}`)}, "-"},
+		{"unknown method", &ApiRequest{Method: "DELETE", Path: "/v1/chat"}, "-"},
+		{"missing body", &ApiRequest{Method: "POST", Path: "/v1/chat"}, "body"},
+		{"invalid json", &ApiRequest{Path: "/v1/chat", Body: json.RawMessage(`{"prompt":`)}, "body"},
+		{"missing required", &ApiRequest{Path: "/v1/chat", Body: json.RawMessage(`{"max_tokens":16}`)}, "body.prompt"},
+		{"wrong type", &ApiRequest{Path: "/v1/chat", Body: json.RawMessage(`{"prompt":"hi","max_tokens":1.5}`)}, "body.max_tokens"},
+		{"not in enum", &ApiRequest{Path: "/v1/chat", Body: json.RawMessage(`{"prompt":"hi","mode":"slow"}`)}, "body.mode"},
+		{"wrong item", &ApiRequest{Path: "/v1/chat", Body: json.RawMessage(`{"prompt":"hi","stop":[1]}`)}, "body.stop[0]"},
+		{"additional property", &ApiRequest{Path: "/v1/chat", Body: json.RawMessage(`{"prompt":"hi","seed":1}`)}, "body.seed"},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := schema.ValidateRequest(testCase.req)
+			if testCase.field == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var schemaErr *SchemaError
+			assert.ErrorAs(t, err, &schemaErr)
+
+			if testCase.field != "-" {
+				assert.Equal(t, testCase.field, schemaErr.Field)
+			}
+		})
+	}
+}
+
+func TestAppSchema_ValidateResponse(t *testing.T) {
+	t.Parallel()
+
+	schema, err := ParseAppSchema([]byte(testIdl))
+	assert.NoError(t, err)
+
+	req := &ApiRequest{Path: "/v1/chat", Body: json.RawMessage(`{"prompt":"hi"}`)}
+
+	assert.NoError(t, schema.ValidateResponse(req, []byte(`{"text":"hello"}`)))
+	assert.ErrorIs(t, schema.ValidateResponse(req, []byte(`{"error":"oops"}`)), ErrAppResponseInvalid)
+}
+
+func TestErrorEdgeResponse_SchemaError(t *testing.T) {
+	t.Parallel()
+
+	resp := errorEdgeResponse(http.StatusBadRequest, &SchemaError{
+		Path:   "/v1/chat",
+		Method: http.MethodPost,
+		Field:  "body.prompt",
+		Reason: "is required",
+	})
+	assert.Equal(t, uint64(http.StatusBadRequest), resp.StatusCode)
+
+	body, err := base64.StdEncoding.DecodeString(resp.RespString)
+	assert.NoError(t, err)
+
+	schemaErr := &SchemaError{}
+	assert.NoError(t, json.Unmarshal(body, schemaErr))
+	assert.Equal(t, "body.prompt", schemaErr.Field)
+}
@@ -0,0 +1,145 @@
+package application
+
+import (
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	gostream "github.com/libp2p/go-libp2p-gostream"
+	"github.com/libp2p/go-libp2p/core/host"
+)
+
+const (
+	// HeaderEmcApp is the header selecting the app of the node serving the request.
+	// Requests without it are served by the default app, the first one added.
+	HeaderEmcApp = "Emc-App"
+)
+
+var (
+	ErrAppNotFound  = errors.New("app not found")
+	ErrAppDuplicate = errors.New("app already served")
+)
+
+// AppConfig configures an app served by the node
+type AppConfig struct {
+	// name the app is addressed by
+	Name string
+	// url of the app
+	Url string
+	// max number of in-flight app requests, 0 for DefaultMaxConcurrency
+	MaxConcurrency uint64
+	// max number of app requests waiting for a free slot
+	MaxQueue uint64
+	// price of an edge call to the app, in wei, nil if free
+	Price *big.Int
+}
+
+// AppServer serves the apps of the node over the ProtoTagEcApp protocol,
+// routing each request to the endpoint of the app it is addressed to
+type AppServer struct {
+	logger   hclog.Logger
+	h        host.Host
+	listener net.Listener
+
+	sync.RWMutex
+	endpoints map[string]*Endpoint
+	// name of the app serving the requests without app name
+	defaultApp string
+}
+
+// NewAppServer starts serving the apps of the node on the host
+func NewAppServer(logger hclog.Logger, srvHost host.Host) (*AppServer, error) {
+	listener, err := gostream.Listen(srvHost, ProtoTagEcApp)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &AppServer{
+		logger:    logger.Named("app_server"),
+		h:         srvHost,
+		listener:  listener,
+		endpoints: make(map[string]*Endpoint),
+	}
+
+	go func() {
+		server := &http.Server{Handler: s}
+		server.Serve(listener)
+	}()
+
+	return s, nil
+}
+
+// AddEndpoint serves the app of the endpoint, the first app added is the default one
+func (s *AppServer) AddEndpoint(endpoint *Endpoint) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.endpoints[endpoint.Name()]; ok {
+		return ErrAppDuplicate
+	}
+
+	s.endpoints[endpoint.Name()] = endpoint
+
+	if len(s.endpoints) == 1 {
+		s.defaultApp = endpoint.Name()
+	}
+
+	s.logger.Info("serving app", "name", endpoint.Name(), "url", endpoint.appUrl)
+
+	return nil
+}
+
+// Endpoint returns the endpoint of the app, or of the default app if name is empty
+func (s *AppServer) Endpoint(name string) *Endpoint {
+	s.RLock()
+	defer s.RUnlock()
+
+	if name == "" {
+		name = s.defaultApp
+	}
+
+	return s.endpoints[name]
+}
+
+// Endpoints returns the endpoints of all the apps served
+func (s *AppServer) Endpoints() []*Endpoint {
+	s.RLock()
+	defer s.RUnlock()
+
+	endpoints := make([]*Endpoint, 0, len(s.endpoints))
+	for _, endpoint := range s.endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints
+}
+
+// GetEndpointApplication returns the application of the default app
+func (s *AppServer) GetEndpointApplication() *Application {
+	endpoint := s.Endpoint("")
+	if endpoint == nil {
+		return nil
+	}
+
+	return endpoint.GetEndpointApplication()
+}
+
+// ServeHTTP routes the request to the endpoint of its app
+func (s *AppServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := s.Endpoint(r.Header.Get(HeaderEmcApp))
+	if endpoint == nil {
+		http.Error(w, ErrAppNotFound.Error(), http.StatusNotFound)
+
+		return
+	}
+
+	endpoint.ServeHTTP(w, r)
+}
+
+func (s *AppServer) Close() {
+	s.listener.Close()
+	s.h.Close()
+}
@@ -0,0 +1,56 @@
+package application
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppServer_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	newEndpoint := func(name string) *Endpoint {
+		e := &Endpoint{name: name, mux: http.NewServeMux()}
+		e.mux.HandleFunc("/alive", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name))
+		})
+
+		return e
+	}
+
+	s := &AppServer{logger: hclog.NewNullLogger(), endpoints: make(map[string]*Endpoint)}
+	assert.NoError(t, s.AddEndpoint(newEndpoint("sd")))
+	assert.NoError(t, s.AddEndpoint(newEndpoint("llm")))
+	assert.ErrorIs(t, s.AddEndpoint(newEndpoint("llm")), ErrAppDuplicate)
+	assert.Len(t, s.Endpoints(), 2)
+
+	serve := func(app string) (int, string) {
+		r := httptest.NewRequest(http.MethodPost, "/alive", nil)
+		if app != "" {
+			r.Header.Set(HeaderEmcApp, app)
+		}
+
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, r)
+
+		body, _ := io.ReadAll(w.Result().Body)
+
+		return w.Code, string(body)
+	}
+
+	status, body := serve("llm")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "llm", body)
+
+	// requests without app name are served by the first app
+	status, body = serve("")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "sd", body)
+
+	status, _ = serve("embedding")
+	assert.Equal(t, http.StatusNotFound, status)
+}
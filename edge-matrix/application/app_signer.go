@@ -34,8 +34,14 @@ var (
 	big35 = big.NewInt(35)
 )
 
-// calcCallHash calculates the rtc hash (keccak256 hash of the RLP value)
+// calcCallHash calculates the rtc hash (keccak256 hash of the RLP value).
+// Bound responses are signed over their binding, so that the EdgeCall precompile
+// can recompute their hash on chain from the telegram.
 func calcResponseHash(resp *EdgeResponse, chainID uint64) types.Hash {
+	if resp.IsBound() {
+		return resp.Binding().Hash(chainID)
+	}
+
 	a := signerPool.Get()
 
 	v := a.NewArray()
@@ -46,6 +52,19 @@ func calcResponseHash(resp *EdgeResponse, chainID uint64) types.Hash {
 		v.Set(a.NewString(resp.RespString))
 	}
 
+	// status code and headers are only committed for proxied app responses,
+	// so that legacy responses keep their hash
+	if resp.StatusCode != 0 {
+		v.Set(a.NewUint(resp.StatusCode))
+		v.Set(marshalHeadersWith(a, resp.Headers))
+	}
+
+	// blob reference, the blob content is committed by its hash
+	if resp.IsBlob() {
+		v.Set(a.NewBytes(resp.BlobHash.Bytes()))
+		v.Set(a.NewUint(resp.BlobSize))
+	}
+
 	// EIP155
 	if chainID != 0 {
 		v.Set(a.NewUint(chainID))
@@ -60,6 +79,33 @@ func calcResponseHash(resp *EdgeResponse, chainID uint64) types.Hash {
 	return types.BytesToHash(hash)
 }
 
+// calcResponseDigest calculates the digest of the content of a bound response,
+// its body, headers and blob reference
+func calcResponseDigest(resp *EdgeResponse) types.Hash {
+	a := signerPool.Get()
+
+	v := a.NewArray()
+
+	if len(resp.RespString) < 1 {
+		v.Set(a.NewNull())
+	} else {
+		v.Set(a.NewString(resp.RespString))
+	}
+
+	v.Set(marshalHeadersWith(a, resp.Headers))
+
+	if resp.IsBlob() {
+		v.Set(a.NewBytes(resp.BlobHash.Bytes()))
+		v.Set(a.NewUint(resp.BlobSize))
+	}
+
+	hash := keccak.Keccak256Rlp(nil, v)
+
+	signerPool.Put(a)
+
+	return types.BytesToHash(hash)
+}
+
 // NewEIP155Signer returns a new EIP155Signer object
 func NewEIP155Signer(forks chain.ForksInTime, chainID uint64) *EIP155Signer {
 	return &EIP155Signer{chainID: chainID, isHomestead: forks.Homestead}
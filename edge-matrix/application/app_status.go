@@ -0,0 +1,187 @@
+package application
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/application/proto"
+	"github.com/emc-protocol/edge-matrix/helper/keccak"
+	"github.com/emc-protocol/edge-matrix/types"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/umbracle/fastrlp"
+)
+
+const (
+	// appStatusMaxAge is how old an app status may be, by its sequence number,
+	// and how long the last sequence number of an app peer is remembered
+	appStatusMaxAge = DefaultAppPeerTTL
+)
+
+var (
+	ErrAppStatusUnsigned  = errors.New("app status not signed")
+	ErrAppStatusSignature = errors.New("invalid app status signature")
+	ErrAppStatusReplayed  = errors.New("app status replayed")
+	ErrAppStatusExpired   = errors.New("app status expired")
+)
+
+var appStatusPool fastrlp.ArenaPool
+
+// AppStatusHash returns the hash of the app status signed by its node.
+// The addr is observed by the relay publishing the status, and is not signed:
+// it is only a hint, replaced by the address of the connection to the node once connected.
+func AppStatusHash(status *proto.AppStatus) types.Hash {
+	a := appStatusPool.Get()
+	defer appStatusPool.Put(a)
+
+	v := a.NewArray()
+	v.Set(a.NewString(status.NodeId))
+	v.Set(a.NewString(status.Name))
+	v.Set(a.NewUint(status.StartupTime))
+	v.Set(a.NewUint(status.Uptime))
+	v.Set(a.NewUint(status.GuageHeight))
+	v.Set(a.NewUint(status.GuageMax))
+	v.Set(a.NewString(status.Relay))
+	v.Set(a.NewString(status.AppOrigin))
+	v.Set(a.NewString(status.ModelHash))
+	v.Set(a.NewString(status.Mac))
+	v.Set(a.NewString(status.MemInfo))
+	v.Set(a.NewString(status.CpuInfo))
+	v.Set(a.NewUint(uint64(math.Float32bits(status.AveragePower))))
+	v.Set(a.NewString(status.GpuInfo))
+	v.Set(a.NewString(status.Version))
+	v.Set(a.NewString(status.PubKey))
+	v.Set(a.NewUint(status.Seq))
+
+	// the hardware profile is only committed by the nodes sending one
+	if status.Hardware != nil {
+		v.Set(marshalHardwareWith(a, status.Hardware))
+	}
+
+	// the price is only committed by the nodes charging for their edge calls
+	if status.Price != "" {
+		v.Set(a.NewString(status.Price))
+	}
+
+	return types.BytesToHash(keccak.Keccak256Rlp(nil, v))
+}
+
+// SignAppStatus signs the app status with the key of its node
+func SignAppStatus(key libp2pCrypto.PrivKey, status *proto.AppStatus) error {
+	hash := AppStatusHash(status)
+
+	signature, err := key.Sign(hash.Bytes())
+	if err != nil {
+		return err
+	}
+
+	status.Signature = signature
+
+	return nil
+}
+
+// VerifyAppStatusSignature returns an error if the app status is not signed by the key of its node
+func VerifyAppStatusSignature(status *proto.AppStatus) error {
+	if len(status.Signature) == 0 {
+		return ErrAppStatusUnsigned
+	}
+
+	peerId, err := peer.Decode(status.NodeId)
+	if err != nil {
+		return ErrAppStatusSignature
+	}
+
+	pubKey, err := peerId.ExtractPublicKey()
+	if err != nil {
+		return ErrAppStatusSignature
+	}
+
+	hash := AppStatusHash(status)
+	if ok, err := pubKey.Verify(hash.Bytes(), status.Signature); err != nil || !ok {
+		return ErrAppStatusSignature
+	}
+
+	return nil
+}
+
+// AppStatusSeq generates the sequence numbers of the app statuses of a node.
+// They are millisecond timestamps, increasing across restarts of the node.
+type AppStatusSeq struct {
+	sync.Mutex
+
+	last uint64
+}
+
+// Next returns the next sequence number
+func (s *AppStatusSeq) Next() uint64 {
+	s.Lock()
+	defer s.Unlock()
+
+	seq := uint64(time.Now().UnixMilli())
+	if seq <= s.last {
+		seq = s.last + 1
+	}
+
+	s.last = seq
+
+	return seq
+}
+
+// AppStatusVerifier verifies the app statuses received, rejecting the statuses not signed
+// by their node, the ones with a sequence number too far from now, and the replayed ones
+type AppStatusVerifier struct {
+	sync.Mutex
+
+	// last sequence number of each app peer
+	seqs      map[string]uint64
+	lastPrune time.Time
+}
+
+func NewAppStatusVerifier() *AppStatusVerifier {
+	return &AppStatusVerifier{
+		seqs: make(map[string]uint64),
+	}
+}
+
+// Verify returns an error if the app status is invalid or replayed
+func (v *AppStatusVerifier) Verify(status *proto.AppStatus, now time.Time) error {
+	if err := VerifyAppStatusSignature(status); err != nil {
+		return err
+	}
+
+	minSeq := uint64(now.Add(-appStatusMaxAge).UnixMilli())
+	if status.Seq < minSeq || status.Seq > uint64(now.Add(appStatusMaxAge).UnixMilli()) {
+		return ErrAppStatusExpired
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	v.prune(now, minSeq)
+
+	key := status.NodeId + "/" + status.Name
+	if status.Seq <= v.seqs[key] {
+		return ErrAppStatusReplayed
+	}
+
+	v.seqs[key] = status.Seq
+
+	return nil
+}
+
+// prune forgets the sequence numbers too old to be accepted anyway
+func (v *AppStatusVerifier) prune(now time.Time, minSeq uint64) {
+	if now.Sub(v.lastPrune) < appStatusMaxAge {
+		return
+	}
+
+	for key, seq := range v.seqs {
+		if seq < minSeq {
+			delete(v.seqs, key)
+		}
+	}
+
+	v.lastPrune = now
+}
@@ -0,0 +1,181 @@
+package application
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/application/proof/helper"
+	"github.com/emc-protocol/edge-matrix/application/proto"
+	"github.com/hashicorp/go-hclog"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+func newSignedAppStatus(t *testing.T, key libp2pCrypto.PrivKey, seq uint64) *proto.AppStatus {
+	t.Helper()
+
+	id, err := peer.IDFromPrivateKey(key)
+	assert.NoError(t, err)
+
+	status := &proto.AppStatus{NodeId: id.String(), Name: "sd", GpuInfo: "RTX 4090", AveragePower: 30, Seq: seq}
+	assert.NoError(t, SignAppStatus(key, status))
+
+	return status
+}
+
+func TestVerifyAppStatusSignature(t *testing.T) {
+	t.Parallel()
+
+	key, _, err := libp2pCrypto.GenerateSecp256k1Key(rand.Reader)
+	assert.NoError(t, err)
+
+	otherKey, _, err := libp2pCrypto.GenerateSecp256k1Key(rand.Reader)
+	assert.NoError(t, err)
+
+	status := newSignedAppStatus(t, key, 1)
+	assert.NoError(t, VerifyAppStatusSignature(status))
+
+	// the addr is observed by the relay, not signed
+	status.Addr = "/ip4/1.2.3.4/tcp/50001"
+	assert.NoError(t, VerifyAppStatusSignature(status))
+
+	status.AveragePower = 100
+	assert.ErrorIs(t, VerifyAppStatusSignature(status), ErrAppStatusSignature)
+
+	// a status forged for another node
+	forged := newSignedAppStatus(t, otherKey, 1)
+	forged.NodeId = status.NodeId
+	assert.ErrorIs(t, VerifyAppStatusSignature(forged), ErrAppStatusSignature)
+
+	assert.ErrorIs(t, VerifyAppStatusSignature(&proto.AppStatus{NodeId: status.NodeId}), ErrAppStatusUnsigned)
+}
+
+func TestAppStatus_Hardware(t *testing.T) {
+	t.Parallel()
+
+	key, _, err := libp2pCrypto.GenerateSecp256k1Key(rand.Reader)
+	assert.NoError(t, err)
+
+	id, err := peer.IDFromPrivateKey(key)
+	assert.NoError(t, err)
+
+	hardware := &helper.HardwareProfile{
+		Version:  helper.HardwareProfileVersion,
+		Cpu:      helper.CpuProfile{Model: "AMD EPYC 7763", Cores: 64, Threads: 128},
+		Gpus:     []helper.GpuProfile{{Vendor: "NVIDIA Corporation", Model: "GA100", Vram: 81920, ComputeCapability: "8.0"}},
+		MemTotal: 515072,
+	}
+
+	status := &proto.AppStatus{NodeId: id.String(), Name: "llm", Seq: 1, Hardware: HardwareToProto(hardware)}
+	assert.NoError(t, SignAppStatus(key, status))
+
+	// the hardware profile goes through the wire and is covered by the signature
+	raw, err := protobuf.Marshal(status)
+	assert.NoError(t, err)
+
+	decoded := &proto.AppStatus{}
+	assert.NoError(t, protobuf.Unmarshal(raw, decoded))
+	assert.NoError(t, VerifyAppStatusSignature(decoded))
+	assert.Equal(t, hardware, HardwareFromProto(decoded.Hardware))
+
+	decoded.Hardware.Gpus[0].Vram = 163840
+	assert.ErrorIs(t, VerifyAppStatusSignature(decoded), ErrAppStatusSignature)
+}
+
+func TestAppStatusVerifier(t *testing.T) {
+	t.Parallel()
+
+	key, _, err := libp2pCrypto.GenerateSecp256k1Key(rand.Reader)
+	assert.NoError(t, err)
+
+	seq := &AppStatusSeq{}
+	verifier := NewAppStatusVerifier()
+	now := time.Now()
+
+	first := newSignedAppStatus(t, key, seq.Next())
+	second := newSignedAppStatus(t, key, seq.Next())
+	assert.Greater(t, second.Seq, first.Seq)
+
+	assert.NoError(t, verifier.Verify(first, now))
+	assert.NoError(t, verifier.Verify(second, now))
+
+	// replayed and out of order statuses are rejected
+	assert.ErrorIs(t, verifier.Verify(second, now), ErrAppStatusReplayed)
+	assert.ErrorIs(t, verifier.Verify(first, now), ErrAppStatusReplayed)
+
+	// statuses too old to be remembered are rejected by their sequence number
+	assert.ErrorIs(t, verifier.Verify(newSignedAppStatus(t, key, seq.Next()), now.Add(time.Hour)), ErrAppStatusExpired)
+}
+
+func TestAppStatus_Price(t *testing.T) {
+	t.Parallel()
+
+	key, _, err := libp2pCrypto.GenerateSecp256k1Key(rand.Reader)
+	assert.NoError(t, err)
+
+	id, err := peer.IDFromPrivateKey(key)
+	assert.NoError(t, err)
+
+	status := &proto.AppStatus{NodeId: id.String(), Name: "llm", Seq: 1, Price: EncodePrice(big.NewInt(1000))}
+	assert.NoError(t, SignAppStatus(key, status))
+	assert.NoError(t, VerifyAppStatusSignature(status))
+
+	// the price is signed by the node
+	status.Price = "1"
+	assert.ErrorIs(t, VerifyAppStatusSignature(status), ErrAppStatusSignature)
+
+	appPeer := &AppPeer{ID: id.String(), Name: "llm", Price: DecodePrice("1000")}
+	assert.NoError(t, appPeer.CheckPrice(big.NewInt(1000)))
+	assert.ErrorIs(t, appPeer.CheckPrice(big.NewInt(999)), ErrEdgeCallUnderpaid)
+	assert.ErrorIs(t, appPeer.CheckPrice(nil), ErrEdgeCallUnderpaid)
+
+	// free apps and invalid prices
+	assert.Equal(t, "", EncodePrice(nil))
+	assert.Nil(t, DecodePrice("-1"))
+	assert.NoError(t, (&AppPeer{Price: DecodePrice("free")}).CheckPrice(nil))
+}
+
+// disconnectRecorder is a network recording the disconnected peers
+type disconnectRecorder struct {
+	Network
+
+	disconnected []peer.ID
+}
+
+func (n *disconnectRecorder) AddrInfo() *peer.AddrInfo {
+	return &peer.AddrInfo{ID: peer.ID("self")}
+}
+
+func (n *disconnectRecorder) DisconnectFromPeer(peerID peer.ID, _ string) {
+	n.disconnected = append(n.disconnected, peerID)
+}
+
+func TestHandleGossipAppStatusUpdate_Disconnect(t *testing.T) {
+	t.Parallel()
+
+	key, _, err := libp2pCrypto.GenerateSecp256k1Key(rand.Reader)
+	assert.NoError(t, err)
+
+	network := &disconnectRecorder{}
+	client := &syncAppPeerClient{
+		logger:         hclog.NewNullLogger(),
+		network:        network,
+		statusVerifier: NewAppStatusVerifier(),
+	}
+	relay := peer.ID("relay")
+
+	// late gossip from an honest relay is only dropped
+	client.handleGossipAppStatusUpdate(newSignedAppStatus(t, key, 1), relay)
+	assert.Empty(t, network.disconnected)
+
+	// a relay publishing forged statuses is disconnected
+	forged := newSignedAppStatus(t, key, (&AppStatusSeq{}).Next())
+	forged.AveragePower = 100
+
+	client.handleGossipAppStatusUpdate(forged, relay)
+	assert.Equal(t, []peer.ID{relay}, network.disconnected)
+}
@@ -1,6 +1,10 @@
 package application
 
 import (
+	"math/big"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/application/proof/helper"
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
@@ -35,6 +39,16 @@ type Application struct {
 	GuageMax uint64
 	// average e power value
 	AveragePower float32
+	// hex encoded public key edge call inputs can be encrypted to
+	PubKey string
+	// true if the app went offline, its app peer being evicted
+	Offline bool
+	// time the last app status of the app was received
+	LastSeen time.Time
+	// hardware profile of the node
+	Hardware *helper.HardwareProfile
+	// price of an edge call to the app, in wei, nil if free
+	Price *big.Int
 }
 
 func (a *Application) Copy() *Application {
@@ -55,6 +69,11 @@ func (a *Application) Copy() *Application {
 		GpuInfo:      a.GpuInfo,
 		ModelHash:    a.ModelHash,
 		AveragePower: a.AveragePower,
+		PubKey:       a.PubKey,
+		Offline:      a.Offline,
+		LastSeen:     a.LastSeen,
+		Hardware:     a.Hardware,
+		Price:        a.Price,
 	}
 
 	return newApp
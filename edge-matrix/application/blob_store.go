@@ -0,0 +1,214 @@
+package application
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/helper/keccak"
+	"github.com/emc-protocol/edge-matrix/types"
+)
+
+const (
+	// MaxBlobSize is the max size of a blob
+	MaxBlobSize = 512 * 1024 * 1024
+
+	// DefaultBlobTTL is how long blobs are kept by the provider
+	DefaultBlobTTL = time.Hour
+
+	// blobPartSuffix is the file suffix of the blobs being uploaded
+	blobPartSuffix = ".part"
+)
+
+var (
+	ErrBlobNotFound     = errors.New("blob not found")
+	ErrBlobTooLarge     = errors.New("blob too large")
+	ErrBlobHashMismatch = errors.New("blob content does not match its hash")
+	ErrBlobOffset       = errors.New("blob chunk offset does not match the uploaded size")
+)
+
+// BlobStore is a content-addressed store of the large edge call inputs and outputs.
+// Blobs are files named by the keccak256 hash of their content, kept for a TTL.
+type BlobStore struct {
+	sync.Mutex
+
+	dir string
+	ttl time.Duration
+}
+
+// NewBlobStore returns a blob store keeping blobs in dir for ttl
+func NewBlobStore(dir string, ttl time.Duration) (*BlobStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	if ttl == 0 {
+		ttl = DefaultBlobTTL
+	}
+
+	return &BlobStore{dir: dir, ttl: ttl}, nil
+}
+
+func (s *BlobStore) path(hash types.Hash) string {
+	return filepath.Join(s.dir, strings.TrimPrefix(hash.String(), "0x"))
+}
+
+// BlobHash returns the content hash of the blob
+func BlobHash(data []byte) types.Hash {
+	return types.BytesToHash(keccak.Keccak256(nil, data))
+}
+
+// Put stores the blob and returns its hash
+func (s *BlobStore) Put(data []byte) (types.Hash, error) {
+	if len(data) > MaxBlobSize {
+		return types.ZeroHash, ErrBlobTooLarge
+	}
+
+	hash := BlobHash(data)
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, err := os.Stat(s.path(hash)); err == nil {
+		// refresh the ttl of the blob
+		now := time.Now()
+
+		return hash, os.Chtimes(s.path(hash), now, now)
+	}
+
+	tmp := s.path(hash) + blobPartSuffix + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return types.ZeroHash, err
+	}
+
+	return hash, os.Rename(tmp, s.path(hash))
+}
+
+// Get returns the content of the blob
+func (s *BlobStore) Get(hash types.Hash) ([]byte, error) {
+	data, err := os.ReadFile(s.path(hash))
+	if os.IsNotExist(err) {
+		return nil, ErrBlobNotFound
+	}
+
+	return data, err
+}
+
+// Open returns the blob file, to be closed by the caller
+func (s *BlobStore) Open(hash types.Hash) (*os.File, error) {
+	f, err := os.Open(s.path(hash))
+	if os.IsNotExist(err) {
+		return nil, ErrBlobNotFound
+	}
+
+	return f, err
+}
+
+// Size returns the size of the blob if stored, or the size uploaded so far
+// if the blob is being uploaded
+func (s *BlobStore) Size(hash types.Hash) (size uint64, complete bool) {
+	if info, err := os.Stat(s.path(hash)); err == nil {
+		return uint64(info.Size()), true
+	}
+
+	if info, err := os.Stat(s.path(hash) + blobPartSuffix); err == nil {
+		return uint64(info.Size()), false
+	}
+
+	return 0, false
+}
+
+// WriteChunk appends a chunk to the blob being uploaded. The offset must be the size
+// uploaded so far. Once size bytes are uploaded, the blob is checked against its hash
+// and stored. It returns the size uploaded so far.
+func (s *BlobStore) WriteChunk(hash types.Hash, size uint64, offset uint64, chunk []byte) (uint64, error) {
+	if size > MaxBlobSize || offset+uint64(len(chunk)) > size {
+		return 0, ErrBlobTooLarge
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if info, err := os.Stat(s.path(hash)); err == nil {
+		return uint64(info.Size()), nil
+	}
+
+	part := s.path(hash) + blobPartSuffix
+
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	uploaded := uint64(info.Size())
+	if offset != uploaded {
+		return uploaded, ErrBlobOffset
+	}
+
+	if _, err := f.WriteAt(chunk, int64(offset)); err != nil {
+		return uploaded, err
+	}
+
+	uploaded += uint64(len(chunk))
+	if uploaded < size {
+		return uploaded, nil
+	}
+
+	if err := checkBlobFile(part, hash); err != nil {
+		os.Remove(part)
+
+		return 0, err
+	}
+
+	return uploaded, os.Rename(part, s.path(hash))
+}
+
+// checkBlobFile returns an error if the content of the file does not match the hash
+func checkBlobFile(path string, hash types.Hash) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := keccak.NewKeccak256()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	if types.BytesToHash(h.Sum(nil)) != hash {
+		return ErrBlobHashMismatch
+	}
+
+	return nil
+}
+
+// Prune removes the blobs and uploads not used since the ttl
+func (s *BlobStore) Prune(now time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < s.ttl {
+			continue
+		}
+
+		_ = os.Remove(filepath.Join(s.dir, entry.Name()))
+	}
+}
@@ -0,0 +1,132 @@
+package application
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/chain"
+	"github.com/emc-protocol/edge-matrix/crypto"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobStore_PutGet(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewBlobStore(t.TempDir(), 0)
+	assert.NoError(t, err)
+
+	data := []byte("large model output")
+
+	hash, err := store.Put(data)
+	assert.NoError(t, err)
+	assert.Equal(t, BlobHash(data), hash)
+
+	stored, err := store.Get(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, data, stored)
+
+	size, complete := store.Size(hash)
+	assert.Equal(t, uint64(len(data)), size)
+	assert.True(t, complete)
+
+	_, err = store.Get(BlobHash([]byte("missing")))
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestBlobStore_WriteChunk(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewBlobStore(t.TempDir(), 0)
+	assert.NoError(t, err)
+
+	data := []byte("0123456789")
+	hash := BlobHash(data)
+	size := uint64(len(data))
+
+	uploaded, err := store.WriteChunk(hash, size, 0, data[:4])
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), uploaded)
+
+	// the upload resumes from the size uploaded so far
+	uploaded, err = store.WriteChunk(hash, size, 2, data[2:6])
+	assert.ErrorIs(t, err, ErrBlobOffset)
+	assert.Equal(t, uint64(4), uploaded)
+
+	uploaded, complete := store.Size(hash)
+	assert.Equal(t, uint64(4), uploaded)
+	assert.False(t, complete)
+
+	uploaded, err = store.WriteChunk(hash, size, 4, data[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, size, uploaded)
+
+	stored, err := store.Get(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, data, stored)
+
+	// chunks not matching the hash are dropped once the upload completes
+	_, err = store.WriteChunk(BlobHash([]byte("other")), 5, 0, []byte("12345"))
+	assert.ErrorIs(t, err, ErrBlobHashMismatch)
+
+	_, err = store.WriteChunk(hash, 4, 0, data)
+	assert.ErrorIs(t, err, ErrBlobTooLarge)
+}
+
+func TestBlobStore_Prune(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewBlobStore(t.TempDir(), time.Minute)
+	assert.NoError(t, err)
+
+	oldHash, err := store.Put([]byte("old"))
+	assert.NoError(t, err)
+
+	newHash, err := store.Put([]byte("new"))
+	assert.NoError(t, err)
+
+	old := time.Now().Add(-time.Hour)
+	assert.NoError(t, os.Chtimes(store.path(oldHash), old, old))
+
+	store.Prune(time.Now())
+
+	_, err = store.Get(oldHash)
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+
+	_, err = store.Get(newHash)
+	assert.NoError(t, err)
+}
+
+func TestEdgeResponse_Blob(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	signer := NewEIP155Signer(chain.AllForksEnabled.At(0), 2)
+	body := []byte("large model output")
+
+	resp, err := signer.SignEdgeResp(&EdgeResponse{BlobHash: BlobHash(body), BlobSize: uint64(len(body))}, key)
+	assert.NoError(t, err)
+
+	decoded := &EdgeResponse{}
+	assert.NoError(t, decoded.UnmarshalRLP(resp.MarshalRLP()))
+	assert.True(t, decoded.IsBlob())
+	assert.Equal(t, BlobHash(body), decoded.BlobHash)
+	assert.Equal(t, uint64(len(body)), decoded.BlobSize)
+
+	provider, err := signer.Provider(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubKeyToAddress(&key.PublicKey), provider)
+
+	// the blob reference is covered by the provider signature
+	decoded.BlobSize++
+	provider, err = signer.Provider(decoded)
+	if err == nil {
+		assert.NotEqual(t, crypto.PubKeyToAddress(&key.PublicKey), provider)
+	}
+
+	assert.False(t, (&EdgeResponse{}).IsBlob())
+	assert.NotEqual(t, types.ZeroHash, decoded.BlobHash)
+}
@@ -0,0 +1,416 @@
+package application
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/helper/keccak"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/hashicorp/go-hclog"
+	gostream "github.com/libp2p/go-libp2p-gostream"
+	p2phttp "github.com/libp2p/go-libp2p-http"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+const (
+	// proto tag of the blob transfer protocol
+	ProtoTagEcBlob = "/em-blob"
+
+	// BlobChunkSize is the size of the chunks blobs are transferred by
+	BlobChunkSize = 1024 * 1024
+
+	// HeaderEmcBlobOffset is the header carrying the size of a blob uploaded so far
+	HeaderEmcBlobOffset = "Emc-Blob-Offset"
+
+	// BlobResponseThreshold is the size from which response bodies are moved to the blob store,
+	// when the request asks for it
+	BlobResponseThreshold = txSlotSize
+
+	// blobChunkRetries is the number of attempts to transfer a blob chunk
+	blobChunkRetries = 3
+
+	// blobPruneInterval is the interval blobs past their ttl are removed at
+	blobPruneInterval = 5 * time.Minute
+)
+
+var ErrBlobChunkTooLarge = errors.New("blob chunk too large")
+
+// BlobServer serves the blob store of the node over the ProtoTagEcBlob protocol:
+//   - HEAD /blob/{hash} returns the blob size, or the size uploaded so far in HeaderEmcBlobOffset
+//   - GET /blob/{hash} returns the blob, ranges are supported to fetch it by chunks
+//   - PUT /blob/{hash}?size={size}&offset={offset} uploads a chunk of the blob
+type BlobServer struct {
+	logger   hclog.Logger
+	store    *BlobStore
+	listener net.Listener
+	closeCh  chan struct{}
+}
+
+// NewBlobServer starts serving the blob store on the host
+func NewBlobServer(logger hclog.Logger, srvHost host.Host, store *BlobStore) (*BlobServer, error) {
+	listener, err := gostream.Listen(srvHost, ProtoTagEcBlob)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &BlobServer{
+		logger:   logger.Named("blob_server"),
+		store:    store,
+		listener: listener,
+		closeCh:  make(chan struct{}),
+	}
+
+	go func() {
+		server := &http.Server{Handler: s}
+		server.Serve(listener)
+	}()
+
+	go s.pruneLoop()
+
+	return s, nil
+}
+
+func (s *BlobServer) Close() {
+	close(s.closeCh)
+	s.listener.Close()
+}
+
+func (s *BlobServer) pruneLoop() {
+	ticker := time.NewTicker(blobPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case now := <-ticker.C:
+			s.store.Prune(now)
+		}
+	}
+}
+
+// ServeHTTP serves the blob requests
+func (s *BlobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	hash := types.Hash{}
+	if err := hash.UnmarshalText([]byte(strings.TrimPrefix(r.URL.Path, "/blob/"))); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodHead:
+		size, complete := s.store.Size(hash)
+		w.Header().Set(HeaderEmcBlobOffset, strconv.FormatUint(size, 10))
+
+		if !complete {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Length", strconv.FormatUint(size, 10))
+	case http.MethodGet:
+		f, err := s.store.Open(hash)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+
+			return
+		}
+		defer f.Close()
+
+		http.ServeContent(w, r, "", time.Time{}, f)
+	case http.MethodPut:
+		s.writeChunk(w, r, hash)
+	default:
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *BlobServer) writeChunk(w http.ResponseWriter, r *http.Request, hash types.Hash) {
+	defer r.Body.Close()
+
+	size, err := strconv.ParseUint(r.URL.Query().Get("size"), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	offset, err := strconv.ParseUint(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	chunk, err := io.ReadAll(io.LimitReader(r.Body, BlobChunkSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if len(chunk) > BlobChunkSize {
+		http.Error(w, ErrBlobChunkTooLarge.Error(), http.StatusRequestEntityTooLarge)
+
+		return
+	}
+
+	uploaded, err := s.store.WriteChunk(hash, size, offset, chunk)
+	w.Header().Set(HeaderEmcBlobOffset, strconv.FormatUint(uploaded, 10))
+
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusOK)
+	case errors.Is(err, ErrBlobOffset):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrBlobTooLarge), errors.Is(err, ErrBlobHashMismatch):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		s.logger.Error("unable to write blob chunk", "hash", hash, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// blobClient returns a http client of the blob transfer protocol
+func blobClient(clientHost host.Host) *http.Client {
+	tr := &http.Transport{}
+	tr.RegisterProtocol("libp2p", p2phttp.NewTransport(clientHost, p2phttp.ProtocolOption(protocol.ID(ProtoTagEcBlob))))
+
+	return &http.Client{Transport: tr}
+}
+
+func blobUrl(peerId string, hash types.Hash) string {
+	return fmt.Sprintf("libp2p://%s/blob/%s", peerId, hash.String())
+}
+
+// ReadBlob reads length bytes of the blob of the peer from offset
+func ReadBlob(clientHost host.Host, peerId string, hash types.Hash, offset uint64, length uint64) ([]byte, error) {
+	if length > BlobChunkSize {
+		return nil, ErrBlobChunkTooLarge
+	}
+
+	req, err := http.NewRequest(http.MethodGet, blobUrl(peerId, hash), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+
+	res, err := blobClient(clientHost).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusPartialContent && res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("blob read failed, status %d", res.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(res.Body, int64(length)))
+}
+
+// FetchBlob fetches the blob of the peer by chunks into w, retrying the chunks that fail,
+// and checks it against its hash
+func FetchBlob(clientHost host.Host, peerId string, hash types.Hash, size uint64, w io.Writer) error {
+	if size > MaxBlobSize {
+		return ErrBlobTooLarge
+	}
+
+	h := keccak.NewKeccak256()
+	out := io.MultiWriter(w, h)
+
+	for offset := uint64(0); offset < size; {
+		length := size - offset
+		if length > BlobChunkSize {
+			length = BlobChunkSize
+		}
+
+		var (
+			chunk []byte
+			err   error
+		)
+
+		for attempt := 0; attempt < blobChunkRetries; attempt++ {
+			if chunk, err = ReadBlob(clientHost, peerId, hash, offset, length); err == nil && len(chunk) > 0 {
+				break
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if len(chunk) == 0 {
+			return io.ErrUnexpectedEOF
+		}
+
+		if _, err := out.Write(chunk); err != nil {
+			return err
+		}
+
+		offset += uint64(len(chunk))
+	}
+
+	if types.BytesToHash(h.Sum(nil)) != hash {
+		return ErrBlobHashMismatch
+	}
+
+	return nil
+}
+
+// BlobOffset returns the size of the blob of the peer uploaded so far, and whether it is complete
+func BlobOffset(clientHost host.Host, peerId string, hash types.Hash) (uint64, bool, error) {
+	res, err := blobClient(clientHost).Head(blobUrl(peerId, hash))
+	if err != nil {
+		return 0, false, err
+	}
+	defer res.Body.Close()
+
+	offset, err := strconv.ParseUint(res.Header.Get(HeaderEmcBlobOffset), 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return offset, res.StatusCode == http.StatusOK, nil
+}
+
+// WriteBlobChunk uploads a chunk of the blob to the peer, and returns
+// the size of the blob uploaded so far
+func WriteBlobChunk(clientHost host.Host, peerId string, hash types.Hash, size uint64, offset uint64, chunk []byte) (uint64, error) {
+	if len(chunk) > BlobChunkSize {
+		return 0, ErrBlobChunkTooLarge
+	}
+
+	url := fmt.Sprintf("%s?size=%d&offset=%d", blobUrl(peerId, hash), size, offset)
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(chunk))
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := blobClient(clientHost).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	uploaded, _ := strconv.ParseUint(res.Header.Get(HeaderEmcBlobOffset), 10, 64)
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return uploaded, nil
+	case http.StatusConflict:
+		return uploaded, ErrBlobOffset
+	default:
+		msg, _ := io.ReadAll(res.Body)
+
+		return uploaded, fmt.Errorf("blob write failed, status %d: %s", res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+}
+
+// PushBlob uploads the blob to the peer by chunks, resuming from the size already
+// uploaded, and returns its hash. Edge calls then refer to the blob by its hash.
+func PushBlob(clientHost host.Host, peerId string, data []byte) (types.Hash, error) {
+	if len(data) > MaxBlobSize {
+		return types.ZeroHash, ErrBlobTooLarge
+	}
+
+	hash := BlobHash(data)
+	size := uint64(len(data))
+
+	offset, complete, err := BlobOffset(clientHost, peerId, hash)
+	if err != nil {
+		return types.ZeroHash, err
+	}
+
+	failures := 0
+
+	for !complete && (offset < size || size == 0) {
+		end := offset + BlobChunkSize
+		if end > size {
+			end = size
+		}
+
+		uploaded, err := WriteBlobChunk(clientHost, peerId, hash, size, offset, data[offset:end])
+		if err != nil && !errors.Is(err, ErrBlobOffset) {
+			if failures++; failures >= blobChunkRetries {
+				return types.ZeroHash, err
+			}
+
+			continue
+		}
+
+		if uploaded > size {
+			return types.ZeroHash, ErrBlobTooLarge
+		}
+
+		offset = uploaded
+		complete = err == nil && uploaded == size
+	}
+
+	return hash, nil
+}
+
+// SetBlobStore sets the store of the large request and response bodies
+func (e *Endpoint) SetBlobStore(store *BlobStore) {
+	e.blobStore = store
+}
+
+// loadBodyBlob replaces the body of the request by the blob it refers to, if any
+func (e *Endpoint) loadBodyBlob(req *ApiRequest) error {
+	if req.BodyBlob == "" {
+		return nil
+	}
+
+	if e.blobStore == nil {
+		return ErrBlobNotFound
+	}
+
+	hash := types.Hash{}
+	if err := hash.UnmarshalText([]byte(req.BodyBlob)); err != nil {
+		return err
+	}
+
+	body, err := e.blobStore.Get(hash)
+	if err != nil {
+		return err
+	}
+
+	req.Body = body
+
+	return nil
+}
+
+// storeResponseBlob moves the response body to the blob store if larger than BlobResponseThreshold
+func (e *Endpoint) storeResponseBlob(edgeResp *EdgeResponse) error {
+	if e.blobStore == nil || base64.StdEncoding.DecodedLen(len(edgeResp.RespString)) < BlobResponseThreshold {
+		return nil
+	}
+
+	body, err := base64.StdEncoding.DecodeString(edgeResp.RespString)
+	if err != nil {
+		return err
+	}
+
+	hash, err := e.blobStore.Put(body)
+	if err != nil {
+		return err
+	}
+
+	edgeResp.RespString = ""
+	edgeResp.BlobHash = hash
+	edgeResp.BlobSize = uint64(len(body))
+
+	return nil
+}
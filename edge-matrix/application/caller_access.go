@@ -0,0 +1,287 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/crypto"
+	"github.com/emc-protocol/edge-matrix/helper/hex"
+	"github.com/emc-protocol/edge-matrix/types"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// HeaderEmcTelegram is the header carrying the signed edge call telegram (hex encoded RLP),
+	// which authenticates the caller to the provider
+	HeaderEmcTelegram = "Emc-Telegram"
+
+	// callerReplayWindow is how long the telegrams of authenticated calls are remembered
+	callerReplayWindow = 10 * time.Minute
+
+	secondsPerDay = 24 * 60 * 60
+)
+
+var (
+	ErrCallerNotAuthenticated = errors.New("caller not authenticated")
+	ErrCallerTelegramInvalid  = errors.New("caller telegram does not match the request")
+	ErrCallerTelegramReplayed = errors.New("caller telegram already used")
+	ErrCallerDenied           = errors.New("caller not allowed")
+	ErrCallerRateLimited      = errors.New("caller rate limit exceeded")
+	ErrCallerQuotaExceeded    = errors.New("caller daily quota exceeded")
+)
+
+// AccessConfig restricts the callers served by the endpoint /api.
+// Once any restriction is set, only authenticated callers are served.
+type AccessConfig struct {
+	// if not empty, only these callers are served
+	Allowlist []types.Address
+	// callers never served
+	Denylist []types.Address
+	// requests per second allowed per caller, 0 for unlimited
+	RateLimit float64
+	// max burst of requests per caller above the rate limit
+	RateBurst uint64
+	// requests per UTC day allowed per caller, 0 for unlimited
+	DailyQuota uint64
+}
+
+// isRestricted returns true if the config restricts the callers
+func (c *AccessConfig) isRestricted() bool {
+	return len(c.Allowlist) > 0 || len(c.Denylist) > 0 || c.RateLimit > 0 || c.DailyQuota > 0
+}
+
+// callerUsage is the usage of the endpoint by a caller
+type callerUsage struct {
+	limiter *rate.Limiter
+	// UTC day of the counted requests
+	day   int64
+	count uint64
+}
+
+// callerAccess enforces the access config of the endpoint.
+// Usage is kept in memory, daily quotas restart with the node.
+type callerAccess struct {
+	sync.Mutex
+
+	config AccessConfig
+	allow  map[types.Address]bool
+	deny   map[types.Address]bool
+
+	callers map[types.Address]*callerUsage
+	// telegrams of the authenticated calls in the replay window
+	seen map[types.Hash]time.Time
+	day  int64
+}
+
+func newCallerAccess(config *AccessConfig) *callerAccess {
+	a := &callerAccess{
+		config:  *config,
+		allow:   make(map[types.Address]bool, len(config.Allowlist)),
+		deny:    make(map[types.Address]bool, len(config.Denylist)),
+		callers: make(map[types.Address]*callerUsage),
+		seen:    make(map[types.Hash]time.Time),
+	}
+
+	for _, addr := range config.Allowlist {
+		a.allow[addr] = true
+	}
+
+	for _, addr := range config.Denylist {
+		a.deny[addr] = true
+	}
+
+	return a
+}
+
+// check returns nil if the caller is served. Caller is nil for unauthenticated calls,
+// teleHash identifies the telegram of authenticated calls.
+func (a *callerAccess) check(caller *types.Address, teleHash types.Hash, now time.Time) error {
+	if !a.config.isRestricted() {
+		return nil
+	}
+
+	if caller == nil {
+		return ErrCallerNotAuthenticated
+	}
+
+	if a.deny[*caller] || len(a.allow) > 0 && !a.allow[*caller] {
+		return ErrCallerDenied
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.prune(now)
+
+	if _, ok := a.seen[teleHash]; ok {
+		return ErrCallerTelegramReplayed
+	}
+
+	day := now.Unix() / secondsPerDay
+
+	usage, ok := a.callers[*caller]
+	if !ok {
+		usage = &callerUsage{day: day}
+
+		if a.config.RateLimit > 0 {
+			burst := int(a.config.RateBurst)
+			if burst == 0 {
+				burst = 1
+			}
+
+			usage.limiter = rate.NewLimiter(rate.Limit(a.config.RateLimit), burst)
+		}
+
+		a.callers[*caller] = usage
+	}
+
+	if usage.day != day {
+		usage.day = day
+		usage.count = 0
+	}
+
+	if a.config.DailyQuota > 0 && usage.count >= a.config.DailyQuota {
+		return ErrCallerQuotaExceeded
+	}
+
+	if usage.limiter != nil && !usage.limiter.AllowN(now, 1) {
+		return ErrCallerRateLimited
+	}
+
+	usage.count++
+	a.seen[teleHash] = now
+
+	return nil
+}
+
+// prune forgets the telegrams out of the replay window, and the callers
+// without usage today once the day changed [NOT Thread Safe]
+func (a *callerAccess) prune(now time.Time) {
+	for hash, seenAt := range a.seen {
+		if now.Sub(seenAt) > callerReplayWindow {
+			delete(a.seen, hash)
+		}
+	}
+
+	day := now.Unix() / secondsPerDay
+	if day == a.day {
+		return
+	}
+
+	a.day = day
+
+	for caller, usage := range a.callers {
+		if usage.day != day && (usage.limiter == nil || usage.limiter.TokensAt(now) >= float64(usage.limiter.Burst())) {
+			delete(a.callers, caller)
+		}
+	}
+}
+
+// SetAccessConfig restricts the callers served by the endpoint /api
+func (e *Endpoint) SetAccessConfig(config *AccessConfig) {
+	e.access = newCallerAccess(config)
+}
+
+// SetTeleSigner sets the signer used to authenticate the callers from their edge call telegram
+func (e *Endpoint) SetTeleSigner(signer crypto.TxSigner) {
+	e.teleSigner = signer
+}
+
+// authenticateCaller returns the sender of the edge call telegram forwarded by the router,
+// and the telegram hash. It returns a nil caller if the request carries no telegram.
+func (e *Endpoint) authenticateCaller(r *http.Request, body []byte) (*types.Address, types.Hash, error) {
+	raw := r.Header.Get(HeaderEmcTelegram)
+	if raw == "" || e.teleSigner == nil {
+		return nil, types.Hash{}, nil
+	}
+
+	buf, err := hex.DecodeHex(raw)
+	if err != nil {
+		return nil, types.Hash{}, ErrCallerTelegramInvalid
+	}
+
+	tele := &types.Telegram{}
+	if err := tele.UnmarshalRLP(buf); err != nil {
+		return nil, types.Hash{}, ErrCallerTelegramInvalid
+	}
+
+	if tele.To == nil || *tele.To != contracts.EdgeCallPrecompile {
+		return nil, types.Hash{}, ErrCallerTelegramInvalid
+	}
+
+	sender, err := e.teleSigner.Sender(tele)
+	if err != nil {
+		return nil, types.Hash{}, ErrCallerTelegramInvalid
+	}
+
+	// the binding headers must be the ones of the telegram
+	binding := e.requestBinding(r, body)
+	if binding == nil || binding.Caller != sender || binding.Nonce != tele.Nonce {
+		return nil, types.Hash{}, ErrCallerTelegramInvalid
+	}
+
+	if !e.telegramMatches(tele, r.URL.Path, body) {
+		return nil, types.Hash{}, ErrCallerTelegramInvalid
+	}
+
+	return &sender, e.teleSigner.Hash(tele), nil
+}
+
+// telegramMatches returns true if the edge call of the telegram is the request received,
+// as sent by the router to this provider
+func (e *Endpoint) telegramMatches(tele *types.Telegram, path string, body []byte) bool {
+	call := &EdgeCall{}
+	if err := json.Unmarshal(tele.Input, call); err != nil {
+		return false
+	}
+
+	if call.PeerId != "" && call.PeerId != e.h.ID().String() {
+		return false
+	}
+
+	if call.Endpoint != path {
+		return false
+	}
+
+	if app := call.appName(); app != "" && app != e.name {
+		return false
+	}
+
+	received := (&EdgeCall{Endpoint: path, Input: body}).Hash()
+	if call.Hash() == received {
+		return true
+	}
+
+	// streamed calls carry the stream flag added by the router
+	streamCall, err := NewStreamCall(call)
+
+	return err == nil && streamCall.Hash() == received
+}
+
+// checkCaller returns nil if the caller of the request is served,
+// or the http status code and error it is rejected with
+func (e *Endpoint) checkCaller(r *http.Request, body []byte) (int, error) {
+	if e.access == nil {
+		return http.StatusOK, nil
+	}
+
+	caller, teleHash, err := e.authenticateCaller(r, body)
+	if err != nil {
+		return http.StatusUnauthorized, err
+	}
+
+	switch err := e.access.check(caller, teleHash, time.Now()); {
+	case err == nil:
+		return http.StatusOK, nil
+	case errors.Is(err, ErrCallerNotAuthenticated):
+		return http.StatusUnauthorized, err
+	case errors.Is(err, ErrCallerRateLimited), errors.Is(err, ErrCallerQuotaExceeded):
+		return http.StatusTooManyRequests, err
+	default:
+		return http.StatusForbidden, err
+	}
+}
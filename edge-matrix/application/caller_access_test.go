@@ -0,0 +1,181 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/chain"
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/crypto"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/libp2p/go-libp2p"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallerAccess_Check(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = types.StringToAddress("0x1")
+		bob   = types.StringToAddress("0x2")
+		carol = types.StringToAddress("0x3")
+		now   = time.Unix(1700000000, 0)
+		nonce = byte(0)
+	)
+
+	// every call has its own telegram
+	teleHash := func() types.Hash {
+		nonce++
+
+		return types.BytesToHash([]byte{nonce})
+	}
+
+	t.Run("unrestricted", func(t *testing.T) {
+		t.Parallel()
+
+		access := newCallerAccess(&AccessConfig{})
+		assert.NoError(t, access.check(nil, types.Hash{}, now))
+	})
+
+	t.Run("allow and deny lists", func(t *testing.T) {
+		t.Parallel()
+
+		access := newCallerAccess(&AccessConfig{
+			Allowlist: []types.Address{alice, bob},
+			Denylist:  []types.Address{bob},
+		})
+
+		assert.ErrorIs(t, access.check(nil, types.Hash{}, now), ErrCallerNotAuthenticated)
+		assert.NoError(t, access.check(&alice, types.StringToHash("0xa1"), now))
+		assert.ErrorIs(t, access.check(&bob, types.StringToHash("0xb1"), now), ErrCallerDenied)
+		assert.ErrorIs(t, access.check(&carol, types.StringToHash("0xc1"), now), ErrCallerDenied)
+	})
+
+	t.Run("replayed telegram", func(t *testing.T) {
+		t.Parallel()
+
+		access := newCallerAccess(&AccessConfig{Denylist: []types.Address{bob}})
+		hash := types.StringToHash("0xa1")
+
+		assert.NoError(t, access.check(&alice, hash, now))
+		assert.ErrorIs(t, access.check(&alice, hash, now.Add(time.Minute)), ErrCallerTelegramReplayed)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		access := newCallerAccess(&AccessConfig{RateLimit: 1, RateBurst: 2})
+
+		assert.NoError(t, access.check(&alice, teleHash(), now))
+		assert.NoError(t, access.check(&alice, teleHash(), now))
+		assert.ErrorIs(t, access.check(&alice, teleHash(), now), ErrCallerRateLimited)
+
+		// callers are limited independently
+		assert.NoError(t, access.check(&bob, teleHash(), now))
+
+		assert.NoError(t, access.check(&alice, teleHash(), now.Add(time.Second)))
+	})
+
+	t.Run("daily quota", func(t *testing.T) {
+		access := newCallerAccess(&AccessConfig{DailyQuota: 2})
+
+		assert.NoError(t, access.check(&alice, teleHash(), now))
+		assert.NoError(t, access.check(&alice, teleHash(), now))
+		assert.ErrorIs(t, access.check(&alice, teleHash(), now), ErrCallerQuotaExceeded)
+
+		// the quota restarts the next UTC day
+		assert.NoError(t, access.check(&alice, teleHash(), now.Add(24*time.Hour)))
+	})
+}
+
+func TestEndpoint_AuthenticateCaller(t *testing.T) {
+	t.Parallel()
+
+	h, err := libp2p.New(libp2p.NoListenAddrs)
+	assert.NoError(t, err)
+
+	defer h.Close()
+
+	teleSigner := crypto.NewEIP155Signer(chain.AllForksEnabled.At(0), 2)
+	e := &Endpoint{h: h}
+	e.SetTeleSigner(teleSigner)
+
+	key, err := crypto.GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	caller := crypto.PubKeyToAddress(&key.PublicKey)
+
+	call := &EdgeCall{Endpoint: "/api", Input: json.RawMessage(`{"path":"/v1/chat","body":{"prompt":"hi"}}`)}
+	callInput, err := json.Marshal(call)
+	assert.NoError(t, err)
+
+	tele, err := teleSigner.SignTele(&types.Telegram{
+		Nonce:    7,
+		To:       &contracts.EdgeCallPrecompile,
+		Input:    callInput,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+	}, key)
+	assert.NoError(t, err)
+
+	newRequest := func(call *EdgeCall, tele *types.Telegram) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, call.Endpoint, bytes.NewReader(call.Input))
+		r.Header = callHeader(h, call, caller, tele)
+
+		return r
+	}
+
+	t.Run("authenticated call", func(t *testing.T) {
+		t.Parallel()
+
+		sender, teleHash, err := e.authenticateCaller(newRequest(call, tele), call.Input)
+		assert.NoError(t, err)
+		assert.Equal(t, caller, *sender)
+		assert.Equal(t, teleSigner.Hash(tele), teleHash)
+	})
+
+	t.Run("streamed call", func(t *testing.T) {
+		t.Parallel()
+
+		streamCall, err := NewStreamCall(call)
+		assert.NoError(t, err)
+
+		sender, _, err := e.authenticateCaller(newRequest(streamCall, tele), streamCall.Input)
+		assert.NoError(t, err)
+		assert.Equal(t, caller, *sender)
+	})
+
+	t.Run("input not signed by the caller", func(t *testing.T) {
+		t.Parallel()
+
+		other := call.Copy()
+		other.Input = json.RawMessage(`{"path":"/v1/chat","body":{"prompt":"free ride"}}`)
+
+		_, _, err := e.authenticateCaller(newRequest(other, tele), other.Input)
+		assert.ErrorIs(t, err, ErrCallerTelegramInvalid)
+	})
+
+	t.Run("spoofed sender", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRequest(call, tele)
+		r.Header.Set(HeaderEmcFrom, types.StringToAddress("0x1").String())
+
+		_, _, err := e.authenticateCaller(r, call.Input)
+		assert.ErrorIs(t, err, ErrCallerTelegramInvalid)
+	})
+
+	t.Run("call without telegram", func(t *testing.T) {
+		t.Parallel()
+
+		r := newRequest(call, tele)
+		r.Header.Del(HeaderEmcTelegram)
+
+		sender, _, err := e.authenticateCaller(r, call.Input)
+		assert.NoError(t, err)
+		assert.Nil(t, sender)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"github.com/emc-protocol/edge-matrix/helper/hex"
 	"github.com/emc-protocol/edge-matrix/types"
 	p2phttp "github.com/libp2p/go-libp2p-http"
 	"github.com/libp2p/go-libp2p/core/host"
@@ -11,18 +12,34 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 )
 
+// EdgeCallTimeout bounds an edge call, from sending the request to reading the whole response,
+// so that an app peer which never answers does not hold the caller forever
+const EdgeCallTimeout = 5 * time.Minute
+
 type EdgeCall struct {
 	PeerId   string          `json:"peerId"`
 	Endpoint string          `json:"endpoint"`
 	Input    json.RawMessage `json:"input"`
+	// Target selects the app peer serving the call when PeerId is empty
+	Target *EdgeCallTarget `json:"target,omitempty"`
+	// App is the name of the app of the peer serving the call, empty for its default app
+	App string `json:"app,omitempty"`
 }
 
 func (e *EdgeCall) Copy() *EdgeCall {
 	tt := &EdgeCall{
 		PeerId:   e.PeerId,
 		Endpoint: e.Endpoint,
+		App:      e.App,
+	}
+
+	if e.Target != nil {
+		target := *e.Target
+		tt.Target = &target
 	}
 
 	if len(e.Input) > 0 {
@@ -33,6 +50,15 @@ func (e *EdgeCall) Copy() *EdgeCall {
 	return tt
 }
 
+// appName returns the name of the app the call is addressed to, empty if any app can serve it
+func (e *EdgeCall) appName() string {
+	if e.App == "" && e.Target != nil {
+		return e.Target.AppName
+	}
+
+	return e.App
+}
+
 func DecodeEdgeCallFromInterface(i interface{}) (*EdgeCall, error) {
 	// once the rtc filter is decoded as map[string]interface we cannot use unmarshal json
 	raw, err := json.Marshal(i)
@@ -51,7 +77,7 @@ func DecodeEdgeCallFromInterface(i interface{}) (*EdgeCall, error) {
 func Call(clientHost host.Host, protoTag string, call *EdgeCall) ([]byte, error) {
 	tr := &http.Transport{}
 	tr.RegisterProtocol("libp2p", p2phttp.NewTransport(clientHost, p2phttp.ProtocolOption(protocol.ID(protoTag))))
-	client := &http.Client{Transport: tr}
+	client := &http.Client{Transport: tr, Timeout: EdgeCallTimeout}
 
 	if call.Input == nil {
 		return nil, nil
@@ -62,7 +88,19 @@ func Call(clientHost host.Host, protoTag string, call *EdgeCall) ([]byte, error)
 	}
 
 	buf := bytes.NewBuffer(raw)
-	res, err := client.Post(fmt.Sprintf("libp2p://%s%s", call.PeerId, call.Endpoint), "application/json", buf)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("libp2p://%s%s", call.PeerId, call.Endpoint), buf)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if call.App != "" {
+		req.Header.Set(HeaderEmcApp, call.App)
+	}
+
+	res, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -75,10 +113,12 @@ func Call(clientHost host.Host, protoTag string, call *EdgeCall) ([]byte, error)
 
 }
 
-func CallWithFrom(clientHost host.Host, protoTag string, call *EdgeCall, from types.Address) ([]byte, error) {
+// CallWithFrom sends the edge call along with its telegram and the telegram sender,
+// so that the provider authenticates the caller and binds its signed response to them
+func CallWithFrom(clientHost host.Host, protoTag string, call *EdgeCall, from types.Address, tele *types.Telegram) ([]byte, error) {
 	tr := &http.Transport{}
 	tr.RegisterProtocol("libp2p", p2phttp.NewTransport(clientHost, p2phttp.ProtocolOption(protocol.ID(protoTag))))
-	client := &http.Client{Transport: tr}
+	client := &http.Client{Transport: tr, Timeout: EdgeCallTimeout}
 
 	if call.Input == nil {
 		return nil, nil
@@ -98,7 +138,7 @@ func CallWithFrom(clientHost host.Host, protoTag string, call *EdgeCall, from ty
 	req := &http.Request{
 		URL:    URL,
 		Method: "POST",
-		Header: http.Header{"Content-Type": {"application/json"}, "Emc-From": {from.String()}, "Emc-Router": {clientHost.ID().String()}},
+		Header: callHeader(clientHost, call, from, tele),
 		Body:   io.NopCloser(buf),
 	}
 
@@ -114,3 +154,20 @@ func CallWithFrom(clientHost host.Host, protoTag string, call *EdgeCall, from ty
 	return all, nil
 
 }
+
+// callHeader returns the headers sent by the router along with an edge call
+func callHeader(clientHost host.Host, call *EdgeCall, from types.Address, tele *types.Telegram) http.Header {
+	header := http.Header{
+		"Content-Type":    {"application/json"},
+		HeaderEmcFrom:     {from.String()},
+		HeaderEmcNonce:    {strconv.FormatUint(tele.Nonce, 10)},
+		HeaderEmcRouter:   {clientHost.ID().String()},
+		HeaderEmcTelegram: {hex.EncodeToHex(tele.MarshalRLP())},
+	}
+
+	if call.App != "" {
+		header.Set(HeaderEmcApp, call.App)
+	}
+
+	return header
+}
@@ -0,0 +1,52 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrEdgeCallUnderpaid = errors.New("edge call value below the app price")
+
+// EncodePrice returns the app status encoding of the price, empty if free
+func EncodePrice(price *big.Int) string {
+	if price == nil || price.Sign() <= 0 {
+		return ""
+	}
+
+	return price.String()
+}
+
+// DecodePrice returns the price of its app status encoding, nil if free or invalid
+func DecodePrice(price string) *big.Int {
+	if price == "" {
+		return nil
+	}
+
+	value, ok := new(big.Int).SetString(price, 10)
+	if !ok || value.Sign() <= 0 {
+		return nil
+	}
+
+	return value
+}
+
+// CheckPrice returns an error if value does not pay the price of an edge call to the app peer.
+// The value of the edge call telegram is escrowed by the edge call precompile, and paid
+// to the provider once its attestation is verified.
+func (p *AppPeer) CheckPrice(value *big.Int) error {
+	if p.Price == nil || p.Price.Sign() <= 0 {
+		return nil
+	}
+
+	if value == nil || value.Cmp(p.Price) < 0 {
+		return fmt.Errorf("%w: %s < %s", ErrEdgeCallUnderpaid, value, p.Price)
+	}
+
+	return nil
+}
+
+// SetPrice sets the price of an edge call to the app, published in its app status
+func (e *Endpoint) SetPrice(price *big.Int) {
+	e.application.Price = price
+}
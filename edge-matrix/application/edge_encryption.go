@@ -0,0 +1,149 @@
+package application
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/emc-protocol/edge-matrix/crypto"
+	"github.com/emc-protocol/edge-matrix/helper/hex"
+)
+
+var (
+	ErrSealedInputInvalid = errors.New("invalid sealed edge call input")
+	ErrNoReplyKey         = errors.New("sealed edge call without reply key")
+)
+
+// SealedInput is the input of an end-to-end encrypted edge call. The api request is
+// encrypted to the provider key (the key signing its EdgeResponse), so that routers
+// and relays only see the routing metadata of the EdgeCall.
+// The response is encrypted back to the reply key of the caller.
+type SealedInput struct {
+	// hex encoded ECIES ciphertext of the sealed request
+	Sealed string `json:"sealed"`
+	// set by the router for streamed calls
+	Stream bool `json:"stream,omitempty"`
+}
+
+// sealedRequest is the plaintext of a sealed input
+type sealedRequest struct {
+	Request *ApiRequest `json:"request"`
+	// hex encoded public key the response is encrypted to
+	ReplyKey string `json:"replyKey"`
+}
+
+// EncodePubKey returns the hex encoded public key, as published in AppStatus
+func EncodePubKey(pub *ecdsa.PublicKey) string {
+	return hex.EncodeToHex(crypto.MarshalPublicKey(pub))
+}
+
+// DecodePubKey parses a hex encoded public key
+func DecodePubKey(pubKey string) (*ecdsa.PublicKey, error) {
+	buf, err := hex.DecodeHex(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.ParsePublicKey(buf)
+}
+
+// SealEdgeCallInput encrypts the api request to the provider key, returning the
+// input of the edge call. The provider encrypts its response to replyKey.
+func SealEdgeCallInput(providerKey *ecdsa.PublicKey, req *ApiRequest, replyKey *ecdsa.PublicKey) (json.RawMessage, error) {
+	plaintext, err := json.Marshal(&sealedRequest{
+		Request:  req,
+		ReplyKey: EncodePubKey(replyKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := crypto.Encrypt(providerKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&SealedInput{Sealed: hex.EncodeToHex(ciphertext)})
+}
+
+// IsSealedInput returns true if the edge call input is encrypted to its provider
+func IsSealedInput(input []byte) bool {
+	obj := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(input, &obj); err != nil {
+		return false
+	}
+
+	_, ok := obj["sealed"]
+
+	return ok
+}
+
+// openSealedInput decrypts the sealed input with the provider key,
+// returning the api request and the reply key of the caller
+func openSealedInput(priv *ecdsa.PrivateKey, input []byte) (*ApiRequest, *ecdsa.PublicKey, error) {
+	sealed := &SealedInput{}
+	if err := json.Unmarshal(input, sealed); err != nil {
+		return nil, nil, ErrSealedInputInvalid
+	}
+
+	ciphertext, err := hex.DecodeHex(sealed.Sealed)
+	if err != nil {
+		return nil, nil, ErrSealedInputInvalid
+	}
+
+	plaintext, err := crypto.Decrypt(priv, ciphertext)
+	if err != nil {
+		return nil, nil, ErrSealedInputInvalid
+	}
+
+	req := &sealedRequest{}
+	if err := json.Unmarshal(plaintext, req); err != nil || req.Request == nil {
+		return nil, nil, ErrSealedInputInvalid
+	}
+
+	if req.ReplyKey == "" {
+		return nil, nil, ErrNoReplyKey
+	}
+
+	replyKey, err := DecodePubKey(req.ReplyKey)
+	if err != nil {
+		return nil, nil, ErrNoReplyKey
+	}
+
+	req.Request.Stream = req.Request.Stream || sealed.Stream
+
+	return req.Request, replyKey, nil
+}
+
+// sealEdgeResponse encrypts the response body to the reply key
+func sealEdgeResponse(resp *EdgeResponse, replyKey *ecdsa.PublicKey) error {
+	body, err := base64.StdEncoding.DecodeString(resp.RespString)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := crypto.Encrypt(replyKey, body)
+	if err != nil {
+		return err
+	}
+
+	resp.RespString = base64.StdEncoding.EncodeToString(ciphertext)
+
+	return nil
+}
+
+// OpenEdgeResponse decrypts the body of the response to a sealed edge call with the reply key
+func OpenEdgeResponse(priv *ecdsa.PrivateKey, resp *EdgeResponse) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(resp.RespString)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Decrypt(priv, ciphertext)
+}
+
+// OpenStreamChunk decrypts a stream chunk of a sealed edge call with the reply key
+func OpenStreamChunk(priv *ecdsa.PrivateKey, chunk []byte) ([]byte, error) {
+	return crypto.Decrypt(priv, chunk)
+}
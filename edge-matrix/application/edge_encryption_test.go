@@ -0,0 +1,114 @@
+package application
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emc-protocol/edge-matrix/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSealedInput(t *testing.T) {
+	t.Parallel()
+
+	providerKey, err := crypto.GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	callerKey, err := crypto.GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	req := &ApiRequest{
+		Method: "POST",
+		Path:   "/v1/chat",
+		Body:   json.RawMessage(`{"prompt":"a secret prompt"}`),
+	}
+
+	input, err := SealEdgeCallInput(&providerKey.PublicKey, req, &callerKey.PublicKey)
+	assert.NoError(t, err)
+	assert.True(t, IsSealedInput(input))
+	assert.False(t, bytes.Contains(input, []byte("secret")))
+
+	t.Run("provider opens the input", func(t *testing.T) {
+		t.Parallel()
+
+		opened, replyKey, err := openSealedInput(providerKey, input)
+		assert.NoError(t, err)
+		assert.Equal(t, req.Path, opened.Path)
+		assert.JSONEq(t, string(req.Body), string(opened.Body))
+		assert.False(t, opened.Stream)
+		assert.True(t, callerKey.PublicKey.Equal(replyKey))
+	})
+
+	t.Run("other keys can not open the input", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := openSealedInput(callerKey, input)
+		assert.ErrorIs(t, err, ErrSealedInputInvalid)
+	})
+
+	t.Run("stream flag set by the router", func(t *testing.T) {
+		t.Parallel()
+
+		streamCall, err := NewStreamCall(&EdgeCall{Endpoint: "/api", Input: input})
+		assert.NoError(t, err)
+		assert.True(t, IsSealedInput(streamCall.Input))
+
+		opened, _, err := openSealedInput(providerKey, streamCall.Input)
+		assert.NoError(t, err)
+		assert.True(t, opened.Stream)
+	})
+
+	t.Run("plain inputs are not sealed", func(t *testing.T) {
+		t.Parallel()
+
+		raw, err := json.Marshal(req)
+		assert.NoError(t, err)
+		assert.False(t, IsSealedInput(raw))
+	})
+}
+
+func TestSealedResponse(t *testing.T) {
+	t.Parallel()
+
+	callerKey, err := crypto.GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	body := []byte(`{"answer":"a secret answer"}`)
+
+	t.Run("response body", func(t *testing.T) {
+		t.Parallel()
+
+		resp := &EdgeResponse{RespString: base64.StdEncoding.EncodeToString(body), StatusCode: 200}
+		assert.NoError(t, sealEdgeResponse(resp, &callerKey.PublicKey))
+
+		raw, err := base64.StdEncoding.DecodeString(resp.RespString)
+		assert.NoError(t, err)
+		assert.False(t, bytes.Contains(raw, body))
+
+		opened, err := OpenEdgeResponse(callerKey, resp)
+		assert.NoError(t, err)
+		assert.Equal(t, body, opened)
+	})
+
+	t.Run("stream chunks", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := httptest.NewRecorder()
+		sw := newStreamWriter(recorder)
+		sw.replyKey = &callerKey.PublicKey
+
+		assert.NoError(t, sw.writeChunk(body))
+
+		frameType, payload, err := readStreamFrame(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, StreamFrameChunk, frameType)
+		assert.Equal(t, StreamDigest(payload), sw.sum())
+
+		opened, err := OpenStreamChunk(callerKey, payload)
+		assert.NoError(t, err)
+		assert.Equal(t, body, opened)
+	})
+}
@@ -2,17 +2,40 @@ package application
 
 import (
 	"fmt"
+	"github.com/emc-protocol/edge-matrix/state/runtime/precompiled"
 	"github.com/emc-protocol/edge-matrix/types"
 	"github.com/umbracle/fastrlp"
 	"math/big"
 )
 
 type EdgeResponse struct {
+	// base64 encoded app response body
 	RespString string
-	V          *big.Int
-	R          *big.Int
-	S          *big.Int
-	From       types.Address
+	// app response status code, 0 for responses not proxied from the app
+	StatusCode uint64
+	// app response headers as "Key: Value" strings, sorted by key
+	Headers []string
+
+	// request binding, committed by the provider signature
+	// hash of the edge call answered
+	RequestHash types.Hash
+	// telegram sender and nonce of the edge call
+	Caller types.Address
+	Nonce  uint64
+	// peer id of the provider
+	ProviderID string
+	// unix time the response was signed at
+	Timestamp uint64
+
+	// hash and size of the app response body when it is kept in the provider blob store,
+	// RespString is empty then
+	BlobHash types.Hash
+	BlobSize uint64
+
+	V    *big.Int
+	R    *big.Int
+	S    *big.Int
+	From types.Address
 
 	Hash types.Hash
 }
@@ -27,6 +50,11 @@ func (r *EdgeResponse) Copy() *EdgeResponse {
 		tt.RespString = r.RespString
 	}
 
+	if r.Headers != nil {
+		tt.Headers = make([]string, len(r.Headers))
+		copy(tt.Headers, r.Headers)
+	}
+
 	if r.R != nil {
 		tt.R = new(big.Int)
 		tt.R = big.NewInt(0).SetBits(r.R.Bits())
@@ -66,6 +94,21 @@ func (r *EdgeResponse) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 	vv.Set(arena.NewBytes((r.From).Bytes()))
 	vv.Set(arena.NewBytes((r.Hash).Bytes()))
 
+	// status code and headers are appended to keep legacy decoders working
+	vv.Set(arena.NewUint(r.StatusCode))
+	vv.Set(marshalHeadersWith(arena, r.Headers))
+
+	// request binding
+	vv.Set(arena.NewBytes(r.RequestHash.Bytes()))
+	vv.Set(arena.NewBytes(r.Caller.Bytes()))
+	vv.Set(arena.NewUint(r.Nonce))
+	vv.Set(arena.NewString(r.ProviderID))
+	vv.Set(arena.NewUint(r.Timestamp))
+
+	// blob reference
+	vv.Set(arena.NewBytes(r.BlobHash.Bytes()))
+	vv.Set(arena.NewUint(r.BlobSize))
+
 	return vv
 }
 
@@ -122,5 +165,99 @@ func (r *EdgeResponse) unmarshalRLPFrom(_ *fastrlp.Parser, v *fastrlp.Value) err
 		}
 	}
 
+	// StatusCode and Headers
+	if len(elems) >= 8 {
+		if r.StatusCode, err = elems[6].GetUint64(); err != nil {
+			return err
+		}
+
+		headerElems, err := elems[7].GetElems()
+		if err != nil {
+			return err
+		}
+
+		r.Headers = make([]string, 0, len(headerElems))
+		for _, elem := range headerElems {
+			header, err := elem.GetString()
+			if err != nil {
+				return err
+			}
+
+			r.Headers = append(r.Headers, header)
+		}
+	}
+
+	// request binding
+	if len(elems) >= 13 {
+		if err = elems[8].GetHash(r.RequestHash[:]); err != nil {
+			return err
+		}
+
+		if err = elems[9].GetAddr(r.Caller[:]); err != nil {
+			return err
+		}
+
+		if r.Nonce, err = elems[10].GetUint64(); err != nil {
+			return err
+		}
+
+		if r.ProviderID, err = elems[11].GetString(); err != nil {
+			return err
+		}
+
+		if r.Timestamp, err = elems[12].GetUint64(); err != nil {
+			return err
+		}
+	}
+
+	// blob reference
+	if len(elems) >= 15 {
+		if err = elems[13].GetHash(r.BlobHash[:]); err != nil {
+			return err
+		}
+
+		if r.BlobSize, err = elems[14].GetUint64(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// IsBound returns true if the response is committed to the request it answers
+func (r *EdgeResponse) IsBound() bool {
+	return r.RequestHash != types.ZeroHash
+}
+
+// Binding returns the binding of the bound response, as checked by the EdgeCall precompile
+func (r *EdgeResponse) Binding() *precompiled.EdgeCallBinding {
+	return &precompiled.EdgeCallBinding{
+		RespDigest:  calcResponseDigest(r),
+		StatusCode:  r.StatusCode,
+		RequestHash: r.RequestHash,
+		Caller:      r.Caller,
+		Nonce:       r.Nonce,
+		ProviderID:  r.ProviderID,
+		Timestamp:   r.Timestamp,
+	}
+}
+
+// IsBlob returns true if the app response body is kept in the provider blob store
+func (r *EdgeResponse) IsBlob() bool {
+	return r.BlobHash != types.ZeroHash
+}
+
+// IsAppError returns true if the app answered the edge call with an error status
+func (r *EdgeResponse) IsAppError() bool {
+	return r.StatusCode >= 400
+}
+
+// marshalHeadersWith marshals the response headers to a RLP list
+func marshalHeadersWith(arena *fastrlp.Arena, headers []string) *fastrlp.Value {
+	vv := arena.NewArray()
+	for _, header := range headers {
+		vv.Set(arena.NewString(header))
+	}
+
+	return vv
+}
@@ -0,0 +1,304 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/emc-protocol/edge-matrix/crypto"
+	"github.com/emc-protocol/edge-matrix/helper/hex"
+	"github.com/emc-protocol/edge-matrix/helper/keccak"
+	"github.com/emc-protocol/edge-matrix/types"
+	p2phttp "github.com/libp2p/go-libp2p-http"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"io"
+	"net/http"
+	"time"
+)
+
+// stream frame types
+const (
+	StreamFrameChunk byte = 0x01 // raw chunk of the app response
+	StreamFrameFinal byte = 0x02 // signed EdgeResponse carrying the stream digest
+	StreamFrameError byte = 0x03 // app error, terminates the stream
+)
+
+const (
+	// streamChunkSize is the read buffer size used while proxying the app response
+	streamChunkSize = 4 * 1024
+
+	// maxStreamFrameSize is the max payload size of a single stream frame
+	maxStreamFrameSize = txSlotSize
+
+	// streamFrameHeaderSize is 1 byte frame type + 4 bytes payload length
+	streamFrameHeaderSize = 5
+)
+
+const (
+	// EdgeStreamTimeout bounds a streamed edge call, from sending the request to reading the final frame
+	EdgeStreamTimeout = 30 * time.Minute
+
+	// EdgeStreamIdleTimeout bounds the time a streamed edge call waits for its next bytes
+	EdgeStreamIdleTimeout = time.Minute
+)
+
+var (
+	ErrStreamFrameTooLarge = errors.New("stream frame too large")
+	ErrStreamNotFinalized  = errors.New("stream closed without final frame")
+	ErrStreamDigestInvalid = errors.New("stream digest mismatch")
+)
+
+// streamWriter writes framed stream data to a http response,
+// keeping track of the digest of all written chunks
+type streamWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+	digest  *keccak.Keccak
+	// key the chunks are encrypted to, nil for cleartext streams
+	replyKey *ecdsa.PublicKey
+}
+
+func newStreamWriter(w http.ResponseWriter) *streamWriter {
+	sw := &streamWriter{
+		w:      w,
+		digest: keccak.NewKeccak256(),
+	}
+
+	if flusher, ok := w.(http.Flusher); ok {
+		sw.flusher = flusher
+	}
+
+	return sw
+}
+
+// writeFrame writes a single frame and flushes it to the client
+func (s *streamWriter) writeFrame(frameType byte, payload []byte) error {
+	if len(payload) > maxStreamFrameSize {
+		return ErrStreamFrameTooLarge
+	}
+
+	header := make([]byte, streamFrameHeaderSize)
+	header[0] = frameType
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+
+	if _, err := s.w.Write(header); err != nil {
+		return err
+	}
+
+	if _, err := s.w.Write(payload); err != nil {
+		return err
+	}
+
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+
+	return nil
+}
+
+// writeChunk writes a chunk of the app response and adds it to the digest
+func (s *streamWriter) writeChunk(chunk []byte) error {
+	if s.replyKey != nil {
+		sealed, err := crypto.Encrypt(s.replyKey, chunk)
+		if err != nil {
+			return err
+		}
+
+		chunk = sealed
+	}
+
+	_, _ = s.digest.Write(chunk)
+
+	return s.writeFrame(StreamFrameChunk, chunk)
+}
+
+// writeError terminates the stream with an error message
+func (s *streamWriter) writeError(err error) error {
+	return s.writeFrame(StreamFrameError, []byte(err.Error()))
+}
+
+// sum returns the digest of all chunks written so far, hex encoded
+func (s *streamWriter) sum() string {
+	return hex.EncodeToHex(s.digest.Sum(nil))
+}
+
+// idleTimeout cancels a streamed request once nothing was read from it for its timeout
+type idleTimeout struct {
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+// withIdleTimeout returns the request with a context cancelled by the idle timeout,
+// which starts when the request is sent and restarts on every read of the response body
+func withIdleTimeout(req *http.Request, timeout time.Duration) (*http.Request, *idleTimeout) {
+	ctx, cancel := context.WithCancel(req.Context())
+
+	return req.WithContext(ctx), &idleTimeout{timeout: timeout, timer: time.AfterFunc(timeout, cancel)}
+}
+
+// reader returns the response body restarting the idle timeout on every read
+func (t *idleTimeout) reader(body io.Reader) io.Reader {
+	return &idleReader{r: body, idle: t}
+}
+
+// stop stops the idle timeout once the request is done
+func (t *idleTimeout) stop() {
+	t.timer.Stop()
+}
+
+type idleReader struct {
+	r    io.Reader
+	idle *idleTimeout
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.idle.timer.Reset(r.idle.timeout)
+	}
+
+	return n, err
+}
+
+// StreamDigest returns the digest string the provider signs for the given stream chunks
+func StreamDigest(chunks ...[]byte) string {
+	digest := keccak.NewKeccak256()
+	for _, chunk := range chunks {
+		_, _ = digest.Write(chunk)
+	}
+
+	return hex.EncodeToHex(digest.Sum(nil))
+}
+
+// readStreamFrame reads the next frame from the stream
+func readStreamFrame(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, streamFrameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxStreamFrameSize {
+		return 0, nil, ErrStreamFrameTooLarge
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return header[0], payload, nil
+}
+
+// ReadStream reads a framed edge call stream, passing every chunk to onChunk.
+// It returns the final signed EdgeResponse once its digest has been checked
+// against the received chunks.
+func ReadStream(r io.Reader, onChunk func(chunk []byte) error) (*EdgeResponse, error) {
+	reader := bufio.NewReader(r)
+	digest := keccak.NewKeccak256()
+
+	for {
+		frameType, payload, err := readStreamFrame(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, ErrStreamNotFinalized
+			}
+
+			return nil, err
+		}
+
+		switch frameType {
+		case StreamFrameChunk:
+			_, _ = digest.Write(payload)
+
+			if onChunk != nil {
+				if err := onChunk(payload); err != nil {
+					return nil, err
+				}
+			}
+		case StreamFrameError:
+			return nil, fmt.Errorf("endpoint err: %s", string(payload))
+		case StreamFrameFinal:
+			resp := &EdgeResponse{}
+			if err := resp.UnmarshalRLP(payload); err != nil {
+				return nil, err
+			}
+
+			if resp.RespString != hex.EncodeToHex(digest.Sum(nil)) {
+				return nil, ErrStreamDigestInvalid
+			}
+
+			return resp, nil
+		default:
+			return nil, fmt.Errorf("unknown stream frame type: %d", frameType)
+		}
+	}
+}
+
+// NewStreamCall returns a copy of the edge call with "stream": true set on its input object
+func NewStreamCall(call *EdgeCall) (*EdgeCall, error) {
+	obj := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(call.Input, &obj); err != nil {
+		return nil, err
+	}
+
+	obj["stream"] = json.RawMessage("true")
+
+	input, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	streamCall := call.Copy()
+	streamCall.Input = input
+
+	return streamCall, nil
+}
+
+// CallStream sends a streaming edge call (see NewStreamCall) along with its telegram and the
+// telegram sender, and passes the response chunks to onChunk as they arrive.
+// The returned EdgeResponse is signed over the digest of all chunks.
+func CallStream(
+	clientHost host.Host,
+	protoTag string,
+	call *EdgeCall,
+	from types.Address,
+	tele *types.Telegram,
+	onChunk func(chunk []byte) error,
+) (*EdgeResponse, error) {
+	tr := &http.Transport{}
+	tr.RegisterProtocol("libp2p", p2phttp.NewTransport(clientHost, p2phttp.ProtocolOption(protocol.ID(protoTag))))
+	client := &http.Client{Transport: tr, Timeout: EdgeStreamTimeout}
+
+	if call.Input == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(call.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("libp2p://%s%s", call.PeerId, call.Endpoint), bytes.NewBuffer(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header = callHeader(clientHost, call, from, tele)
+
+	req, idle := withIdleTimeout(req, EdgeStreamIdleTimeout)
+	defer idle.stop()
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	return ReadStream(idle.reader(res.Body), onChunk)
+}
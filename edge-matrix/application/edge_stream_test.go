@@ -0,0 +1,127 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/chain"
+	"github.com/emc-protocol/edge-matrix/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadStream(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	signer := NewEIP155Signer(chain.AllForksEnabled.At(0), 2)
+	chunks := [][]byte{[]byte(`{"token":"Hel`), []byte(`lo"}`), []byte(`{"token":"!"}`)}
+
+	writeStream := func(final *EdgeResponse) *bytes.Buffer {
+		recorder := httptest.NewRecorder()
+		sw := newStreamWriter(recorder)
+
+		for _, chunk := range chunks {
+			assert.NoError(t, sw.writeChunk(chunk))
+		}
+
+		if final == nil {
+			final = &EdgeResponse{RespString: sw.sum()}
+		}
+
+		signedResp, err := signer.SignEdgeResp(final, key)
+		assert.NoError(t, err)
+		assert.NoError(t, sw.writeFrame(StreamFrameFinal, signedResp.MarshalRLP()))
+
+		return recorder.Body
+	}
+
+	t.Run("valid stream", func(t *testing.T) {
+		t.Parallel()
+
+		received := make([][]byte, 0)
+		resp, err := ReadStream(writeStream(nil), func(chunk []byte) error {
+			received = append(received, chunk)
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, chunks, received)
+		assert.Equal(t, StreamDigest(chunks...), resp.RespString)
+
+		provider, err := signer.Provider(resp)
+		assert.NoError(t, err)
+		assert.Equal(t, crypto.PubKeyToAddress(&key.PublicKey), provider)
+	})
+
+	t.Run("digest mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ReadStream(writeStream(&EdgeResponse{RespString: StreamDigest(chunks[0])}), nil)
+		assert.ErrorIs(t, err, ErrStreamDigestInvalid)
+	})
+
+	t.Run("missing final frame", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := httptest.NewRecorder()
+		assert.NoError(t, newStreamWriter(recorder).writeChunk(chunks[0]))
+
+		_, err := ReadStream(recorder.Body, nil)
+		assert.ErrorIs(t, err, ErrStreamNotFinalized)
+	})
+
+	t.Run("app error", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := httptest.NewRecorder()
+		assert.NoError(t, newStreamWriter(recorder).writeError(errors.New("model crashed")))
+
+		_, err := ReadStream(recorder.Body, nil)
+		assert.EqualError(t, err, "endpoint err: model crashed")
+	})
+}
+
+func TestIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("chunk"))
+		w.(http.Flusher).Flush()
+
+		// the app stalls after its first chunk
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	req, idle := withIdleTimeout(req, 100*time.Millisecond)
+	defer idle.stop()
+
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	defer res.Body.Close()
+
+	start := time.Now()
+	body, err := io.ReadAll(idle.reader(res.Body))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, "chunk", string(body))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -1,10 +1,13 @@
 package application
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/emc-protocol/edge-matrix/application/proof"
 	appAgent "github.com/emc-protocol/edge-matrix/application/proof/agent"
 	"github.com/emc-protocol/edge-matrix/application/proof/helper"
 	"github.com/emc-protocol/edge-matrix/crypto"
@@ -13,16 +16,13 @@ import (
 	"github.com/emc-protocol/edge-matrix/types"
 	"github.com/emc-protocol/edge-matrix/versioning"
 	"github.com/hashicorp/go-hclog"
-	gostream "github.com/libp2p/go-libp2p-gostream"
 	p2phttp "github.com/libp2p/go-libp2p-http"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	"io"
 	"math/rand"
-	"net"
 	"net/http"
-	"os"
 	"sync"
 	"time"
 )
@@ -45,17 +45,43 @@ type Endpoint struct {
 
 	// gauge for measuring app capacity
 	gauge slotGauge
+	// slots of in-flight app requests, limited to the gauge max
+	slots chan struct{}
+	// number of app requests waiting for a free slot, limited to maxQueue
+	queued   uint64
+	maxQueue uint64
 	sync.Mutex
 	nextNonce        uint64
 	nonceCacheEnable bool
 
-	name       string
-	appUrl     string
-	appOrigin  string
-	h          host.Host
-	tag        string
-	listener   net.Listener
+	name      string
+	appUrl    string
+	appOrigin string
+	h         host.Host
+	tag       string
+	// handlers of the app requests
+	mux        *http.ServeMux
 	httpClient *rpc.FastHttpClient
+	// streamClient proxies streamed app responses chunk by chunk
+	streamClient *http.Client
+	// headers passed between caller and app
+	headerAllowlist map[string]bool
+	// callers served by /api, nil to serve any caller
+	access *callerAccess
+	// last health check of the app
+	health appHealthCache
+	// parsed idl of the app, nil until loaded
+	schema *AppSchema
+	// validate the app responses against the schema
+	validateResponses bool
+	// store of the large request and response bodies, nil if not enabled
+	blobStore *BlobStore
+	// data dir of the node, the disk of the hardware profile
+	dataDir string
+	// prover of the gpu challenges
+	matMulProver proof.MatMulProver
+	// signer authenticating callers from their edge call telegram
+	teleSigner crypto.TxSigner
 	signer     Signer
 	privateKey *ecdsa.PrivateKey
 	address    types.Address
@@ -87,9 +113,14 @@ func (e *Endpoint) getProtocolOption() p2phttp.Option {
 	return p2phttp.ProtocolOption(protocol.ID(e.tag))
 }
 
-func (e *Endpoint) Close() {
-	e.listener.Close()
-	e.h.Close()
+// ServeHTTP serves the requests addressed to the app
+func (e *Endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mux.ServeHTTP(w, r)
+}
+
+// Name returns the name of the app
+func (e *Endpoint) Name() string {
+	return e.name
 }
 
 // SetSigner sets the signer the endpint will use
@@ -98,6 +129,17 @@ func (e *Endpoint) SetSigner(s Signer) {
 	e.signer = s
 }
 
+// SetDataDir sets the data dir of the node, whose disk is reported in the hardware profile
+func (e *Endpoint) SetDataDir(dir string) {
+	e.dataDir = dir
+	e.application.Hardware = e.application.Hardware.WithUsage(dir)
+}
+
+// SetHeaderAllowlist sets the headers passed between caller and app
+func (e *Endpoint) SetHeaderAllowlist(headers []string) {
+	e.headerAllowlist = newHeaderAllowlist(headers)
+}
+
 func (e *Endpoint) GetEndpointApplication() *Application {
 	return e.application
 }
@@ -122,11 +164,11 @@ func (e *Endpoint) getAppOrigin() (error, string) {
 
 func (e *Endpoint) getAppIdl() (error, string) {
 	agent := appAgent.NewAppAgent(e.appUrl)
-	err, appOrigin := agent.GetAppOrigin()
+	err, appIdl := agent.GetAppIdl()
 	if err != nil {
 		return err, ""
 	}
-	return nil, appOrigin
+	return nil, appIdl
 }
 
 func (e *Endpoint) validAppNode() (error, bool) {
@@ -168,11 +210,10 @@ func NewApplicationEndpoint(
 	rand.Seed(time.Now().Unix())
 	endpoint.randomNum = rand.Intn(1000)
 	endpoint.httpClient = rpc.NewDefaultHttpClient()
-	listener, err := gostream.Listen(srvHost, ProtoTagEcApp)
-	if err != nil {
-		return nil, err
-	}
-	endpoint.listener = listener
+	endpoint.streamClient = &http.Client{Timeout: EdgeStreamTimeout}
+	endpoint.headerAllowlist = newHeaderAllowlist(DefaultHeaderAllowlist)
+	endpoint.SetCapacity(DefaultMaxConcurrency, DefaultMaxQueue)
+	endpoint.mux = http.NewServeMux()
 
 	address, err := crypto.GetAddressFromKey(privateKey)
 	if err != nil {
@@ -193,247 +234,482 @@ func NewApplicationEndpoint(
 		Uptime:      0,
 		AppOrigin:   "",
 		GuageHeight: 0,
-		GuageMax:    200,
+		GuageMax:    DefaultMaxConcurrency,
 		Mac:         mac,
 		CpuInfo:     helper.GetCpuInfo(),
 		GpuInfo:     helper.GetGpuInfo(),
 		MemInfo:     helper.GetMemInfo(),
+		Hardware:    helper.GetHardwareProfile(""),
 		Version:     versioning.Version + " Build" + versioning.Build,
+		PubKey:      EncodePubKey(&privateKey.PublicKey),
 	}
 
 	// check app status
-	if isEdgeMode {
-		go func() {
-			ticker := time.NewTicker(DefaultAppStatusSyncDuration)
-			for {
-				<-ticker.C
-				event := &Event{}
-				// bind app node
-				err := endpoint.doAppNodeBind()
-				if err != nil {
-					endpoint.logger.Error("doAppNodeBind", "err", err.Error())
-				}
-
-				err, appOrigin := endpoint.getAppOrigin()
-				if err != nil {
-					endpoint.logger.Error("getAppOrigin", "err", err.Error())
-				}
-
-				endpoint.application.AppOrigin = appOrigin
-				endpoint.application.Uptime = uint64(time.Now().UnixMilli()) - endpoint.application.StartupTime
-				endpoint.application.MemInfo = helper.GetMemInfo()
-				endpoint.application.GpuInfo = helper.GetGpuInfo()
-
-				event.AddNewApp(endpoint.application)
-				endpoint.stream.push(event)
-				endpoint.logger.Debug("endpoint----> status", "AppOrigin", endpoint.application.AppOrigin, "Mac", endpoint.application.Mac, "CpuInfo", endpoint.application.CpuInfo, "GpuInfo", endpoint.application.GpuInfo, "MemInfo", endpoint.application.MemInfo)
-			}
-			ticker.Stop()
-		}()
-	}
-
-	go func() {
-		http.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
-			err, validApp := endpoint.validAppNode()
-			if err != nil {
-				endpoint.logger.Error("validAppNode", "err", err.Error())
-			}
-			if !validApp {
-				return
-			}
-			defer r.Body.Close()
-
-			body, err := io.ReadAll(r.Body)
+	go endpoint.appStatusLoop()
+
+	endpoint.mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		endpoint.logger.Debug(fmt.Sprintf("/api =>request: %s", string(body)))
+
+		var (
+			req      = &ApiRequest{}
+			replyKey *ecdsa.PublicKey
+		)
+
+		if IsSealedInput(body) {
+			// end-to-end encrypted call, the response is encrypted back to the caller
+			req, replyKey, err = openSealedInput(endpoint.privateKey, body)
 			if err != nil {
 				http.Error(w, err.Error(), 400)
+
 				return
 			}
-			endpoint.logger.Debug(fmt.Sprintf("/api =>request: %s", string(body)))
-			_, err = json.Marshal(body)
-			if err != nil {
-				http.Error(w, err.Error(), 400)
-			}
-			var obj struct {
-				Method  string          `json:"method"`
-				Headers []string        `json:"headers"`
-				Path    string          `json:"path"`
-				Body    json.RawMessage `json:"body,omitempty"`
-			}
-			if err := json.Unmarshal(body, &obj); err != nil {
-				http.Error(w, err.Error(), 400)
-			}
-			if obj.Method == "GET" {
-				resp, err := endpoint.httpClient.SendGetRequest(endpoint.appUrl + obj.Path)
-				if err != nil {
-					resp = []byte("endpoint err: " + err.Error())
-				}
-				encodeString := base64.StdEncoding.EncodeToString(resp)
-				edgeResp := &EdgeResponse{
-					RespString: encodeString,
-				}
-				endpoint.logger.Debug(fmt.Sprintf("/api =>resp size: %d", len(edgeResp.RespString)))
-
-				signedResp, err := endpoint.signer.SignEdgeResp(edgeResp, endpoint.privateKey)
-				if err != nil {
-					w.Write([]byte(err.Error()))
-				}
-				provider, err := endpoint.signer.Provider(signedResp)
-				if err != nil {
-					return
-				}
-				signedResp.From = provider
-				signedResp.Hash = endpoint.signer.Hash(edgeResp)
-
-				w.Write(signedResp.MarshalRLP())
-			} else if obj.Method == "POST" {
-				resp, err := endpoint.httpClient.SendPostJsonRequest(
-					endpoint.appUrl+obj.Path, obj.Body)
-				if err != nil {
-					resp = []byte("endpoint err: " + err.Error())
-				}
-				encodeString := base64.StdEncoding.EncodeToString(resp)
-				edgeResp := &EdgeResponse{
-					RespString: encodeString,
-				}
-				endpoint.logger.Debug(fmt.Sprintf("/api =>resp size: %d", len(edgeResp.RespString)))
-				endpoint.logger.Debug(fmt.Sprintf("/api =>SignEdgeResp with hash: %s", endpoint.signer.Hash(edgeResp).String()))
-				signedResp, err := endpoint.signer.SignEdgeResp(edgeResp, endpoint.privateKey)
-				if err != nil {
-					w.Write([]byte(err.Error()))
-				}
-				provider, err := endpoint.signer.Provider(signedResp)
-				if err != nil {
-					return
-				}
-				signedResp.From = provider
-				signedResp.Hash = endpoint.signer.Hash(edgeResp)
-
-				w.Write(signedResp.MarshalRLP())
-			}
-		})
+		} else if err := json.Unmarshal(body, req); err != nil {
+			http.Error(w, err.Error(), 400)
+
+			return
+		}
+
+		binding := endpoint.requestBinding(r, body)
+
+		if err := endpoint.AppHealth().Err(); err != nil {
+			endpoint.rejectRequest(w, req, binding, replyKey, http.StatusServiceUnavailable, err)
+
+			return
+		}
+
+		if status, err := endpoint.checkCaller(r, body); err != nil {
+			endpoint.rejectRequest(w, req, binding, replyKey, status, err)
+
+			return
+		}
+
+		if err := endpoint.loadBodyBlob(req); err != nil {
+			endpoint.rejectRequest(w, req, binding, replyKey, http.StatusBadRequest, err)
+
+			return
+		}
+
+		if schema := endpoint.AppSchema(); schema != nil {
+			if err := schema.ValidateRequest(req); err != nil {
+				endpoint.rejectRequest(w, req, binding, replyKey, http.StatusBadRequest, err)
 
-		http.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
-			defer r.Body.Close()
-			body, err := io.ReadAll(r.Body)
-			if err != nil {
-				http.Error(w, err.Error(), 400)
 				return
 			}
-			resp := fmt.Sprintf("recieved data: %s", body)
-			encodeString := base64.StdEncoding.EncodeToString([]byte(resp))
-			edgeResp := &EdgeResponse{
-				RespString: encodeString,
-			}
-			endpoint.logger.Debug(fmt.Sprintf("/api =>resp size: %d", len(edgeResp.RespString)))
+		}
+
+		release, err := endpoint.acquireSlot(r.Context())
+		if err != nil {
+			endpoint.rejectRequest(w, req, binding, replyKey, http.StatusServiceUnavailable, err)
+
+			return
+		}
+		defer release()
+
+		if req.Stream {
+			endpoint.proxyStream(w, req, binding, replyKey)
+
+			return
+		}
+
+		endpoint.proxyRequest(w, req, binding, replyKey)
+	})
+
+	endpoint.mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		resp := fmt.Sprintf("recieved data: %s", body)
+		encodeString := base64.StdEncoding.EncodeToString([]byte(resp))
+		edgeResp := &EdgeResponse{
+			RespString: encodeString,
+		}
+		endpoint.logger.Debug(fmt.Sprintf("/api =>resp size: %d", len(edgeResp.RespString)))
+
+		signedResp, err := endpoint.signResponse(edgeResp, endpoint.requestBinding(r, body))
+		if err != nil {
+			w.Write([]byte(err.Error()))
+
+			return
+		}
+
+		w.Write(signedResp.MarshalRLP())
+	})
+
+	endpoint.mux.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		binding := endpoint.requestBinding(r, body)
+
+		var infoObj struct {
+			Name        string `json:"name"`
+			PeerID      string `json:"peerId"`
+			Uptime      uint64 `json:"uptime"`
+			StartupTime uint64 `json:"startupTime"`
+			Version     string `json:"version"`
+			Tag         string `json:"tag"`
+			// ai model hash string
+			ModelHash string `json:"model_hash"`
+			// mac addr
+			Mac string `json:"mac"`
+			// memory info
+			MemInfo string `json:"mem_info"`
+			// cpu info
+			CpuInfo string `json:"cpu_info"`
+			// average e power
+			AveragePower float32 `json:"average_power"`
+			// gpu info
+			GpuInfo string `json:"gpu_info"`
+			// public key edge call inputs can be encrypted to
+			PubKey string `json:"pub_key"`
+			// hardware profile of the node
+			Hardware *helper.HardwareProfile `json:"hardware"`
+			// price of an edge call to the app, in wei, empty if free
+			Price string `json:"price,omitempty"`
+
+			// health of the app behind the endpoint
+			AppHealth AppHealthStatus `json:"app_health"`
+		}
+		infoObj.PeerID = endpoint.application.PeerID.String()
+		infoObj.Version = endpoint.application.Version
+		infoObj.Tag = endpoint.application.AppOrigin
+		infoObj.Uptime = uint64(time.Now().UnixMilli()) - endpoint.application.StartupTime
+		infoObj.StartupTime = endpoint.application.StartupTime
+		infoObj.Name = endpoint.application.Name
+		infoObj.CpuInfo = endpoint.application.CpuInfo
+		infoObj.GpuInfo = endpoint.application.GpuInfo
+		infoObj.MemInfo = endpoint.application.MemInfo
+		infoObj.Mac = endpoint.application.Mac
+		infoObj.ModelHash = endpoint.application.ModelHash
+		infoObj.AveragePower = endpoint.application.AveragePower
+		infoObj.PubKey = endpoint.application.PubKey
+		infoObj.Hardware = endpoint.application.Hardware
+		infoObj.Price = EncodePrice(endpoint.application.Price)
+		infoObj.AppHealth = endpoint.health.get()
+
+		info, err := json.Marshal(infoObj)
+		if err != nil {
+			info = []byte("endpoint err: " + err.Error())
+		}
+
+		writeResponse(w, info, binding, endpoint)
+	})
+
+	endpoint.matMulProver = &appMatMulProver{endpoint: endpoint}
+	endpoint.mux.HandleFunc(PocMatMulPath, endpoint.handlePocMatMul)
+
+	endpoint.mux.HandleFunc("/alive", func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		resp := fmt.Sprintf("%s", time.Now().String())
+		w.Write([]byte(resp))
+	})
+
+	endpoint.mux.HandleFunc("/idl", func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		binding := endpoint.requestBinding(r, body)
+
+		writeResponse(w, endpoint.appIdl(), binding, endpoint)
+	})
 
-			signedResp, err := endpoint.signer.SignEdgeResp(edgeResp, endpoint.privateKey)
-			if err != nil {
-				w.Write([]byte(err.Error()))
+	return endpoint, nil
+}
+
+// appStatusLoop keeps the app status up to date: every DefaultAppStatusSyncDuration it
+// checks the app health and, in edge mode, binds the app and publishes its status
+func (e *Endpoint) appStatusLoop() {
+	ticker := time.NewTicker(DefaultAppStatusSyncDuration)
+	defer ticker.Stop()
+
+	for {
+		e.syncAppStatus()
+		<-ticker.C
+	}
+}
+
+func (e *Endpoint) syncAppStatus() {
+	if e.isEdgeMode {
+		// bind app node
+		if err := e.doAppNodeBind(); err != nil {
+			e.logger.Error("doAppNodeBind", "err", err.Error())
+		}
+
+		err, appOrigin := e.getAppOrigin()
+		if err != nil {
+			e.logger.Error("getAppOrigin", "err", err.Error())
+		}
+
+		e.application.AppOrigin = appOrigin
+	}
+
+	health := e.refreshAppHealth()
+	if health.Health == AppHealthHealthy {
+		e.loadAppSchema()
+	}
+
+	if !e.isEdgeMode {
+		return
+	}
+
+	e.application.Uptime = uint64(time.Now().UnixMilli()) - e.application.StartupTime
+	e.application.GuageHeight, e.application.GuageMax = e.Capacity()
+	e.application.MemInfo = helper.GetMemInfo()
+	e.application.GpuInfo = helper.GetGpuInfo()
+	e.application.Hardware = e.application.Hardware.WithUsage(e.dataDir)
+
+	event := &Event{}
+	event.AddNewApp(e.application)
+	e.stream.push(event)
+	e.logger.Debug("endpoint----> status", "AppOrigin", e.application.AppOrigin, "Health", health.Health, "Mac", e.application.Mac, "CpuInfo", e.application.CpuInfo, "GpuInfo", e.application.GpuInfo, "MemInfo", e.application.MemInfo)
+}
+
+// proxyRequest forwards the request to the app and writes the signed app response.
+// Failures to reach the app are answered with an error status code, so that callers
+// can tell them apart from a successful result. If replyKey is set, the response
+// body is encrypted to it.
+func (e *Endpoint) proxyRequest(w http.ResponseWriter, req *ApiRequest, binding *RequestBinding, replyKey *ecdsa.PublicKey) {
+	var edgeResp *EdgeResponse
+
+	method := req.method()
+	appUrl, err := req.url(e.appUrl)
+
+	switch {
+	case err != nil:
+		edgeResp = errorEdgeResponse(http.StatusBadRequest, err)
+	case !supportedApiMethods[method]:
+		edgeResp = errorEdgeResponse(http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", method))
+	default:
+		resp, err := e.httpClient.SendRequest(method, appUrl, e.appRequestHeaders(req), req.Body)
+		if err != nil {
+			edgeResp = errorEdgeResponse(http.StatusBadGateway, err)
+		} else if err := e.validateResponse(req, resp.StatusCode, resp.Body); err != nil {
+			edgeResp = errorEdgeResponse(http.StatusBadGateway, err)
+		} else {
+			edgeResp = &EdgeResponse{
+				RespString: base64.StdEncoding.EncodeToString(resp.Body),
+				StatusCode: uint64(resp.StatusCode),
+				Headers:    formatResponseHeaders(resp.Headers, e.headerAllowlist),
 			}
-			provider, err := endpoint.signer.Provider(signedResp)
-			if err != nil {
+		}
+	}
+
+	e.logger.Debug(fmt.Sprintf("/api =>resp status: %d, size: %d", edgeResp.StatusCode, len(edgeResp.RespString)))
+
+	e.writeEdgeResponse(w, edgeResp, binding, replyKey, req.BlobResponse)
+}
+
+// rejectRequest answers the /api request with the error, without forwarding it to the app
+func (e *Endpoint) rejectRequest(
+	w http.ResponseWriter,
+	req *ApiRequest,
+	binding *RequestBinding,
+	replyKey *ecdsa.PublicKey,
+	statusCode int,
+	err error,
+) {
+	e.logger.Debug("/api =>rejected", "status", statusCode, "err", err.Error())
+
+	if req.Stream {
+		_ = newStreamWriter(w).writeError(err)
+
+		return
+	}
+
+	e.writeEdgeResponse(w, errorEdgeResponse(statusCode, err), binding, replyKey, false)
+}
+
+// writeEdgeResponse signs and writes the edge response of an /api request.
+// If replyKey is set, the response body is encrypted to it. If blob is set,
+// a large response body is kept in the blob store and referenced by its hash.
+func (e *Endpoint) writeEdgeResponse(
+	w http.ResponseWriter,
+	edgeResp *EdgeResponse,
+	binding *RequestBinding,
+	replyKey *ecdsa.PublicKey,
+	blob bool,
+) {
+	if replyKey != nil {
+		if err := sealEdgeResponse(edgeResp, replyKey); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	if blob {
+		if err := e.storeResponseBlob(edgeResp); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	signedResp, err := e.signResponse(edgeResp, binding)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Write(signedResp.MarshalRLP())
+}
+
+// appRequestHeaders returns the allowed headers of the request,
+// defaulting the content type to json for requests with body
+func (e *Endpoint) appRequestHeaders(req *ApiRequest) map[string]string {
+	headers := filterRequestHeaders(req.Headers, e.headerAllowlist)
+	if _, ok := headers["Content-Type"]; !ok && len(req.Body) > 0 {
+		headers["Content-Type"] = "application/json"
+	}
+
+	return headers
+}
+
+// proxyStream forwards the request to the app and relays the response body
+// as chunk frames while it is produced. The stream ends with a final frame
+// holding an EdgeResponse signed over the digest of all chunks.
+// If replyKey is set, every chunk is encrypted to it.
+func (e *Endpoint) proxyStream(w http.ResponseWriter, apiReq *ApiRequest, binding *RequestBinding, replyKey *ecdsa.PublicKey) {
+	sw := newStreamWriter(w)
+	sw.replyKey = replyKey
+
+	method := apiReq.method()
+	if !supportedApiMethods[method] {
+		_ = sw.writeError(fmt.Errorf("method %s not allowed", method))
+
+		return
+	}
+
+	appUrl, err := apiReq.url(e.appUrl)
+	if err != nil {
+		_ = sw.writeError(err)
+
+		return
+	}
+
+	req, err := http.NewRequest(method, appUrl, bytes.NewReader(apiReq.Body))
+	if err != nil {
+		_ = sw.writeError(err)
+
+		return
+	}
+
+	for key, value := range e.appRequestHeaders(apiReq) {
+		req.Header.Set(key, value)
+	}
+
+	req, idle := withIdleTimeout(req, EdgeStreamIdleTimeout)
+	defer idle.stop()
+
+	resp, err := e.streamClient.Do(req)
+	if err != nil {
+		_ = sw.writeError(err)
+
+		return
+	}
+	defer resp.Body.Close()
+
+	body := idle.reader(resp.Body)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		_ = sw.writeError(fmt.Errorf("app responded with status %d", resp.StatusCode))
+
+		return
+	}
+
+	buf := make([]byte, streamChunkSize)
+	for {
+		n, readErr := body.Read(buf)
+		if n > 0 {
+			if err := sw.writeChunk(buf[:n]); err != nil {
+				e.logger.Debug("/api =>stream aborted", "err", err.Error())
+
 				return
 			}
-			signedResp.From = provider
-			signedResp.Hash = endpoint.signer.Hash(edgeResp)
-
-			w.Write(signedResp.MarshalRLP())
-		})
-
-		http.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
-			defer r.Body.Close()
-			var infoObj struct {
-				Name        string `json:"name"`
-				PeerID      string `json:"peerId"`
-				Uptime      uint64 `json:"uptime"`
-				StartupTime uint64 `json:"startupTime"`
-				Version     string `json:"version"`
-				Tag         string `json:"tag"`
-				// ai model hash string
-				ModelHash string `json:"model_hash"`
-				// mac addr
-				Mac string `json:"mac"`
-				// memory info
-				MemInfo string `json:"mem_info"`
-				// cpu info
-				CpuInfo string `json:"cpu_info"`
-				// average e power
-				AveragePower float32 `json:"average_power"`
-				// gpu info
-				GpuInfo string `json:"gpu_info"`
-			}
-			infoObj.PeerID = endpoint.application.PeerID.String()
-			infoObj.Version = endpoint.application.Version
-			infoObj.Tag = endpoint.application.AppOrigin
-			infoObj.Uptime = uint64(time.Now().UnixMilli()) - endpoint.application.StartupTime
-			infoObj.StartupTime = endpoint.application.StartupTime
-			infoObj.Name = endpoint.application.Name
-			infoObj.CpuInfo = endpoint.application.CpuInfo
-			infoObj.GpuInfo = endpoint.application.GpuInfo
-			infoObj.MemInfo = endpoint.application.MemInfo
-			infoObj.Mac = endpoint.application.Mac
-			infoObj.ModelHash = endpoint.application.ModelHash
-			infoObj.AveragePower = endpoint.application.AveragePower
-
-			info := make([]byte, 0)
-			info, err := json.Marshal(infoObj)
-			if err != nil {
-				info = []byte("endpoint err: " + err.Error())
-			}
+		}
 
-			writeResponse(w, info, endpoint)
-		})
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
 
-		http.HandleFunc("/alive", func(w http.ResponseWriter, r *http.Request) {
-			defer r.Body.Close()
-			resp := fmt.Sprintf("%s", time.Now().String())
-			w.Write([]byte(resp))
-		})
+		if readErr != nil {
+			_ = sw.writeError(readErr)
 
-		http.HandleFunc("/idl", func(w http.ResponseWriter, r *http.Request) {
-			defer r.Body.Close()
-			err, appIdl := endpoint.getAppIdl()
-			if err != nil {
-				// TODO Fetch idl json text through GET #{appUrl}/getAppIdl
-				endpoint.logger.Debug(fmt.Sprintf("/getAppIdl =>resp: %s", err.Error()))
-				idlData, err := os.ReadFile("idl.json")
-				if nil != err {
-					idlData = []byte("[]")
-				}
-				writeResponse(w, idlData, endpoint)
-			} else {
-				if len(appIdl) > 0 {
-					writeResponse(w, []byte(appIdl), endpoint)
-				} else {
-					writeResponse(w, []byte("[]"), endpoint)
-				}
-			}
-		})
+			return
+		}
+	}
 
-		server := &http.Server{}
-		server.Serve(listener)
-	}()
+	signedResp, err := e.signResponse(&EdgeResponse{RespString: sw.sum()}, binding)
+	if err != nil {
+		_ = sw.writeError(err)
 
-	return endpoint, nil
+		return
+	}
+
+	if err := sw.writeFrame(StreamFrameFinal, signedResp.MarshalRLP()); err != nil {
+		e.logger.Debug("/api =>stream aborted", "err", err.Error())
+	}
+}
+
+// requestBinding returns the binding of a request routed to this endpoint, nil if it has none
+func (e *Endpoint) requestBinding(r *http.Request, body []byte) *RequestBinding {
+	return requestBindingFromHttp(r, body, e.h.ID().String())
 }
 
-func writeResponse(w http.ResponseWriter, info []byte, endpoint *Endpoint) {
+// signResponse signs the edge response and fills in its provider and hash.
+// If the request is bound, the signature commits to it.
+func (e *Endpoint) signResponse(edgeResp *EdgeResponse, binding *RequestBinding) (*EdgeResponse, error) {
+	if binding != nil {
+		edgeResp = edgeResp.Copy()
+		binding.bind(edgeResp, uint64(time.Now().Unix()))
+	}
+
+	signedResp, err := e.signer.SignEdgeResp(edgeResp, e.privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := e.signer.Provider(signedResp)
+	if err != nil {
+		return nil, err
+	}
+
+	signedResp.From = provider
+	signedResp.Hash = e.signer.Hash(edgeResp)
+
+	return signedResp, nil
+}
+
+func writeResponse(w http.ResponseWriter, info []byte, binding *RequestBinding, endpoint *Endpoint) {
 	resp := base64.StdEncoding.EncodeToString(info)
 	edgeResp := &EdgeResponse{
 		RespString: resp,
 	}
 	endpoint.logger.Debug(fmt.Sprintf("/api =>resp size: %d", len(edgeResp.RespString)))
 
-	signedResp, err := endpoint.signer.SignEdgeResp(edgeResp, endpoint.privateKey)
+	signedResp, err := endpoint.signResponse(edgeResp, binding)
 	if err != nil {
 		w.Write([]byte(err.Error()))
-	}
-	provider, err := endpoint.signer.Provider(signedResp)
-	if err != nil {
+
 		return
 	}
-	signedResp.From = provider
-	signedResp.Hash = endpoint.signer.Hash(edgeResp)
 
 	w.Write(signedResp.MarshalRLP())
 }
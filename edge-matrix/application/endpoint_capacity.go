@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultMaxConcurrency is the default max number of in-flight app requests of an endpoint
+	DefaultMaxConcurrency = 200
+
+	// DefaultMaxQueue is the default max number of app requests waiting for a free slot
+	DefaultMaxQueue = 200
+
+	// slotQueueTimeout is how long an app request waits for a free slot
+	slotQueueTimeout = 30 * time.Second
+)
+
+var ErrEndpointBusy = errors.New("endpoint busy, too many in-flight app requests")
+
+// SetCapacity sets the max number of in-flight app requests, and the max number
+// of requests waiting for a free slot. Requests beyond are rejected as busy.
+func (e *Endpoint) SetCapacity(maxConcurrency, maxQueue uint64) {
+	if maxConcurrency == 0 {
+		maxConcurrency = DefaultMaxConcurrency
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	e.slots = make(chan struct{}, maxConcurrency)
+	e.maxQueue = maxQueue
+	atomic.StoreUint64(&e.gauge.max, maxConcurrency)
+}
+
+// acquireSlot waits for a free slot of the app, as long as the wait queue is not full.
+// The returned release func must be called once the app request is done.
+func (e *Endpoint) acquireSlot(ctx context.Context) (func(), error) {
+	e.Lock()
+	slots, maxQueue := e.slots, e.maxQueue
+	e.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+	default:
+		if atomic.AddUint64(&e.queued, 1) > maxQueue {
+			atomic.AddUint64(&e.queued, ^uint64(0))
+
+			return nil, ErrEndpointBusy
+		}
+		defer atomic.AddUint64(&e.queued, ^uint64(0))
+
+		timer := time.NewTimer(slotQueueTimeout)
+		defer timer.Stop()
+
+		select {
+		case slots <- struct{}{}:
+		case <-timer.C:
+			return nil, ErrEndpointBusy
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	e.gauge.increase(1)
+
+	return func() {
+		e.gauge.decrease(1)
+		<-slots
+	}, nil
+}
+
+// Capacity returns the number of in-flight app requests and their max limit
+func (e *Endpoint) Capacity() (height uint64, max uint64) {
+	return e.gauge.read(), atomic.LoadUint64(&e.gauge.max)
+}
@@ -0,0 +1,50 @@
+package application
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpoint_AcquireSlot(t *testing.T) {
+	t.Parallel()
+
+	e := &Endpoint{}
+	e.SetCapacity(1, 1)
+
+	release, err := e.acquireSlot(context.Background())
+	assert.NoError(t, err)
+
+	height, max := e.Capacity()
+	assert.Equal(t, uint64(1), height)
+	assert.Equal(t, uint64(1), max)
+
+	// the next request waits in the queue
+	queuedErr := make(chan error, 1)
+
+	go func() {
+		release, err := e.acquireSlot(context.Background())
+		if err == nil {
+			release()
+		}
+
+		queuedErr <- err
+	}()
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadUint64(&e.queued) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// the queue is full
+	_, err = e.acquireSlot(context.Background())
+	assert.ErrorIs(t, err, ErrEndpointBusy)
+
+	release()
+	assert.NoError(t, <-queuedErr)
+
+	height, _ = e.Capacity()
+	assert.Equal(t, uint64(0), height)
+}
@@ -0,0 +1,114 @@
+package application
+
+import (
+	"math"
+
+	"github.com/emc-protocol/edge-matrix/application/proof/helper"
+	"github.com/emc-protocol/edge-matrix/application/proto"
+	"github.com/umbracle/fastrlp"
+)
+
+// HardwareToProto returns the gossiped hardware profile, nil if the profile is nil
+func HardwareToProto(profile *helper.HardwareProfile) *proto.HardwareProfile {
+	if profile == nil {
+		return nil
+	}
+
+	gpus := make([]*proto.GpuProfile, len(profile.Gpus))
+	for i, gpu := range profile.Gpus {
+		gpus[i] = &proto.GpuProfile{
+			Vendor:            gpu.Vendor,
+			Model:             gpu.Model,
+			Vram:              gpu.Vram,
+			Driver:            gpu.Driver,
+			ComputeCapability: gpu.ComputeCapability,
+		}
+	}
+
+	return &proto.HardwareProfile{
+		Version: profile.Version,
+		Cpu: &proto.CpuProfile{
+			Vendor:  profile.Cpu.Vendor,
+			Model:   profile.Cpu.Model,
+			Cores:   profile.Cpu.Cores,
+			Threads: profile.Cpu.Threads,
+			Mhz:     profile.Cpu.Mhz,
+		},
+		Gpus:      gpus,
+		MemTotal:  profile.MemTotal,
+		MemFree:   profile.MemFree,
+		DiskTotal: profile.DiskTotal,
+		DiskFree:  profile.DiskFree,
+	}
+}
+
+// HardwareFromProto returns the hardware profile of a gossiped one, nil if the node did not send any
+func HardwareFromProto(profile *proto.HardwareProfile) *helper.HardwareProfile {
+	if profile == nil {
+		return nil
+	}
+
+	gpus := make([]helper.GpuProfile, len(profile.Gpus))
+	for i, gpu := range profile.Gpus {
+		gpus[i] = helper.GpuProfile{
+			Vendor:            gpu.GetVendor(),
+			Model:             gpu.GetModel(),
+			Vram:              gpu.GetVram(),
+			Driver:            gpu.GetDriver(),
+			ComputeCapability: gpu.GetComputeCapability(),
+		}
+	}
+
+	cpu := profile.GetCpu()
+
+	return &helper.HardwareProfile{
+		Version: profile.Version,
+		Cpu: helper.CpuProfile{
+			Vendor:  cpu.GetVendor(),
+			Model:   cpu.GetModel(),
+			Cores:   cpu.GetCores(),
+			Threads: cpu.GetThreads(),
+			Mhz:     cpu.GetMhz(),
+		},
+		Gpus:      gpus,
+		MemTotal:  profile.MemTotal,
+		MemFree:   profile.MemFree,
+		DiskTotal: profile.DiskTotal,
+		DiskFree:  profile.DiskFree,
+	}
+}
+
+// marshalHardwareWith encodes the hardware profile signed with the app status
+func marshalHardwareWith(a *fastrlp.Arena, profile *proto.HardwareProfile) *fastrlp.Value {
+	cpu := profile.GetCpu()
+
+	cpuValue := a.NewArray()
+	cpuValue.Set(a.NewString(cpu.GetVendor()))
+	cpuValue.Set(a.NewString(cpu.GetModel()))
+	cpuValue.Set(a.NewUint(uint64(cpu.GetCores())))
+	cpuValue.Set(a.NewUint(uint64(cpu.GetThreads())))
+	cpuValue.Set(a.NewUint(math.Float64bits(cpu.GetMhz())))
+
+	gpus := a.NewArray()
+
+	for _, gpu := range profile.Gpus {
+		gpuValue := a.NewArray()
+		gpuValue.Set(a.NewString(gpu.GetVendor()))
+		gpuValue.Set(a.NewString(gpu.GetModel()))
+		gpuValue.Set(a.NewUint(gpu.GetVram()))
+		gpuValue.Set(a.NewString(gpu.GetDriver()))
+		gpuValue.Set(a.NewString(gpu.GetComputeCapability()))
+		gpus.Set(gpuValue)
+	}
+
+	v := a.NewArray()
+	v.Set(a.NewUint(uint64(profile.Version)))
+	v.Set(cpuValue)
+	v.Set(gpus)
+	v.Set(a.NewUint(profile.MemTotal))
+	v.Set(a.NewUint(profile.MemFree))
+	v.Set(a.NewUint(profile.DiskTotal))
+	v.Set(a.NewUint(profile.DiskFree))
+
+	return v
+}
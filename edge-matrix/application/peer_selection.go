@@ -0,0 +1,101 @@
+package application
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// peer selection policy names
+	LeastLoadedPolicyName   = "least-loaded"
+	LowestLatencyPolicyName = "lowest-latency"
+	HighestPowerPolicyName  = "highest-power"
+)
+
+// PeerSelectionPolicy decides which app peer serves an edge call without a fixed PeerId
+type PeerSelectionPolicy interface {
+	// IsBetter returns true if peer p should be preferred over peer t
+	IsBetter(p, t *AppPeer) bool
+}
+
+// LeastLoadedPolicy prefers the peer with the fewest occupied slots, then the closest one
+type LeastLoadedPolicy struct{}
+
+func (LeastLoadedPolicy) IsBetter(p, t *AppPeer) bool {
+	return p.IsBetter(t)
+}
+
+// HighestPowerPolicy prefers the peer with the highest average E-power
+type HighestPowerPolicy struct{}
+
+func (HighestPowerPolicy) IsBetter(p, t *AppPeer) bool {
+	if p.AveragePower != t.AveragePower {
+		return p.AveragePower > t.AveragePower
+	}
+
+	return p.IsBetter(t)
+}
+
+// LowestLatencyPolicy prefers the peer with the lowest observed call latency.
+// Peers without observed latency are preferred, so that they get probed.
+type LowestLatencyPolicy struct {
+	// Latency returns the observed call latency of the peer, 0 if unknown
+	Latency func(peerID string) time.Duration
+}
+
+func (l LowestLatencyPolicy) IsBetter(p, t *AppPeer) bool {
+	pLatency, tLatency := l.Latency(p.ID), l.Latency(t.ID)
+	if pLatency != tLatency {
+		return pLatency < tLatency
+	}
+
+	return p.IsBetter(t)
+}
+
+// NewPeerSelectionPolicy returns the policy with the given name
+func NewPeerSelectionPolicy(name string, latency func(peerID string) time.Duration) (PeerSelectionPolicy, error) {
+	switch name {
+	case "", LeastLoadedPolicyName:
+		return LeastLoadedPolicy{}, nil
+	case LowestLatencyPolicyName:
+		return LowestLatencyPolicy{Latency: latency}, nil
+	case HighestPowerPolicyName:
+		return HighestPowerPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown peer selection policy: %s", name)
+	}
+}
+
+// EdgeCallTarget selects the app peers able to serve an edge call without a fixed PeerId.
+// Empty fields match any peer.
+type EdgeCallTarget struct {
+	AppName   string `json:"appName,omitempty"`
+	AppOrigin string `json:"appOrigin,omitempty"`
+	ModelHash string `json:"modelHash,omitempty"`
+	// case insensitive part of the model of one of the app peer gpus
+	Gpu string `json:"gpu,omitempty"`
+	// min vram in MB of one of the app peer gpus
+	MinVram uint64 `json:"minVram,omitempty"`
+}
+
+// Match returns true if the app peer can serve the target
+func (t *EdgeCallTarget) Match(p *AppPeer) bool {
+	return (t.AppName == "" || t.AppName == p.Name) &&
+		(t.AppOrigin == "" || t.AppOrigin == p.AppOrigin) &&
+		(t.ModelHash == "" || t.ModelHash == p.ModelHash) &&
+		t.matchHardware(p)
+}
+
+// matchHardware returns true if the hardware profile of the app peer meets the target,
+// app peers without hardware profile only match targets without hardware requirements
+func (t *EdgeCallTarget) matchHardware(p *AppPeer) bool {
+	if t.Gpu == "" && t.MinVram == 0 {
+		return true
+	}
+
+	if p.Hardware == nil {
+		return false
+	}
+
+	return (t.Gpu == "" || p.Hardware.HasGpu(t.Gpu)) && p.Hardware.MaxVram() >= t.MinVram
+}
@@ -0,0 +1,131 @@
+package application
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/application/proof/helper"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerMap_BestPeerWith(t *testing.T) {
+	t.Parallel()
+
+	peers := []*AppPeer{
+		{ID: "A", Name: "sd", AppOrigin: "emc", Guage_height: 5, AveragePower: 30, Distance: big.NewInt(1)},
+		{ID: "B", Name: "sd", AppOrigin: "emc", Guage_height: 1, AveragePower: 10, Distance: big.NewInt(2)},
+		{ID: "C", Name: "llm", AppOrigin: "emc", Guage_height: 0, AveragePower: 50, Distance: big.NewInt(3)},
+	}
+	latencies := map[string]time.Duration{"A": 10 * time.Millisecond, "B": 50 * time.Millisecond, "C": time.Second}
+
+	lowestLatency, err := NewPeerSelectionPolicy(LowestLatencyPolicyName, func(id string) time.Duration {
+		return latencies[id]
+	})
+	assert.NoError(t, err)
+
+	_, err = NewPeerSelectionPolicy("random", nil)
+	assert.Error(t, err)
+
+	sd := &EdgeCallTarget{AppName: "sd"}
+
+	testTable := []struct {
+		name     string
+		skipMap  map[string]bool
+		target   *EdgeCallTarget
+		policy   PeerSelectionPolicy
+		expected string
+	}{
+		{"least loaded", nil, nil, LeastLoadedPolicy{}, "C"},
+		{"least loaded matching target", nil, sd, LeastLoadedPolicy{}, "B"},
+		{"highest power matching target", nil, sd, HighestPowerPolicy{}, "A"},
+		{"lowest latency matching target", nil, sd, lowestLatency, "A"},
+		{"skipped peer", map[string]bool{"B": true}, sd, LeastLoadedPolicy{}, "A"},
+		{"no matching peer", nil, &EdgeCallTarget{ModelHash: "0x1"}, LeastLoadedPolicy{}, ""},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			bestPeer := NewPeerMap(peers).BestPeerWith(testCase.skipMap, testCase.target, testCase.policy)
+			if testCase.expected == "" {
+				assert.Nil(t, bestPeer)
+
+				return
+			}
+
+			assert.Equal(t, testCase.expected, bestPeer.ID)
+		})
+	}
+}
+
+func TestPeerMap_BestPeerWithSaturated(t *testing.T) {
+	t.Parallel()
+
+	peers := []*AppPeer{
+		{ID: "A", Guage_height: 4, Guage_max: 4, AveragePower: 50, Distance: big.NewInt(1)},
+		{ID: "B", Guage_height: 6, Guage_max: 8, AveragePower: 10, Distance: big.NewInt(2)},
+	}
+
+	// the saturated peer is avoided whatever the policy
+	assert.Equal(t, "B", NewPeerMap(peers).BestPeerWith(nil, nil, HighestPowerPolicy{}).ID)
+
+	// unless all peers are saturated
+	assert.Equal(t, "A", NewPeerMap(peers).BestPeerWith(map[string]bool{"B": true}, nil, HighestPowerPolicy{}).ID)
+}
+
+func TestPeerMap_MultipleApps(t *testing.T) {
+	t.Parallel()
+
+	nodeA, nodeB := peer.ID("A"), peer.ID("B")
+
+	peerMap := NewPeerMap([]*AppPeer{
+		{ID: nodeA.String(), Name: "sd", Guage_height: 5, Distance: big.NewInt(1)},
+		{ID: nodeA.String(), Name: "llm", Guage_height: 0, Distance: big.NewInt(1)},
+		{ID: nodeB.String(), Name: "sd", Guage_height: 1, Distance: big.NewInt(2)},
+	})
+
+	// each app of a node is an app peer
+	llm := peerMap.BestPeerWith(nil, &EdgeCallTarget{AppName: "llm"}, LeastLoadedPolicy{})
+	assert.Equal(t, nodeA.String(), llm.ID)
+	assert.Equal(t, "llm", llm.Name)
+
+	sd := peerMap.BestPeerWith(nil, &EdgeCallTarget{AppName: "sd"}, LeastLoadedPolicy{})
+	assert.Equal(t, nodeB.String(), sd.ID)
+
+	// removing a node removes all its apps
+	peerMap.Remove(nodeA)
+	assert.Nil(t, peerMap.Get(nodeA.String()))
+	assert.Nil(t, peerMap.BestPeerWith(nil, &EdgeCallTarget{AppName: "llm"}, LeastLoadedPolicy{}))
+	assert.NotNil(t, peerMap.Get(nodeB.String()))
+}
+
+func TestEdgeCallTarget_Hardware(t *testing.T) {
+	t.Parallel()
+
+	peerMap := NewPeerMap([]*AppPeer{
+		{ID: "A", Name: "llm", Distance: big.NewInt(1)},
+		{ID: "B", Name: "llm", Distance: big.NewInt(2), Hardware: &helper.HardwareProfile{
+			Gpus: []helper.GpuProfile{{Vendor: "NVIDIA Corporation", Model: "GA102 [GeForce RTX 3090]", Vram: 24576}},
+		}},
+		{ID: "C", Name: "llm", Distance: big.NewInt(3), Hardware: &helper.HardwareProfile{
+			Gpus: []helper.GpuProfile{{Vendor: "NVIDIA Corporation", Model: "GA100 [A100 PCIe 80GB]", Vram: 81920}},
+		}},
+	})
+
+	best := func(target *EdgeCallTarget) string {
+		if p := peerMap.BestPeerWith(nil, target, LeastLoadedPolicy{}); p != nil {
+			return p.ID
+		}
+
+		return ""
+	}
+
+	assert.Equal(t, "A", best(&EdgeCallTarget{AppName: "llm"}))
+	assert.Equal(t, "B", best(&EdgeCallTarget{AppName: "llm", Gpu: "nvidia"}))
+	assert.Equal(t, "C", best(&EdgeCallTarget{AppName: "llm", MinVram: 40960}))
+	assert.Equal(t, "", best(&EdgeCallTarget{AppName: "llm", Gpu: "rtx 3090", MinVram: 40960}))
+}
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"encoding/json"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	appPeerKeyPrefix = "apppeer/"
+)
+
+// peerStore keeps a snapshot of the app peer directory, persisted in a leveldb,
+// so that a restarted router can route edge calls before gossip refilled its peer map
+type peerStore struct {
+	db *leveldb.DB
+}
+
+// newPeerStore opens the peer store at path, in memory if path is empty
+func newPeerStore(path string) (*peerStore, error) {
+	var (
+		db  *leveldb.DB
+		err error
+	)
+
+	if path == "" {
+		db, err = leveldb.Open(storage.NewMemStorage(), nil)
+	} else {
+		db, err = leveldb.OpenFile(path, nil)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &peerStore{db: db}, nil
+}
+
+// Load returns the app peers of the last snapshot
+func (s *peerStore) Load() ([]*AppPeer, error) {
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(appPeerKeyPrefix)), nil)
+	defer iter.Release()
+
+	peers := make([]*AppPeer, 0)
+
+	for iter.Next() {
+		appPeer := &AppPeer{}
+		if err := json.Unmarshal(iter.Value(), appPeer); err != nil {
+			return nil, err
+		}
+
+		peers = append(peers, appPeer)
+	}
+
+	return peers, iter.Error()
+}
+
+// Snapshot replaces the stored app peers by the given ones
+func (s *peerStore) Snapshot(peers []*AppPeer) error {
+	batch := new(leveldb.Batch)
+
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(appPeerKeyPrefix)), nil)
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+	}
+
+	iter.Release()
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	for _, appPeer := range peers {
+		raw, err := json.Marshal(appPeer)
+		if err != nil {
+			return err
+		}
+
+		batch.Put([]byte(appPeerKeyPrefix+appPeer.key()), raw)
+	}
+
+	return s.db.Write(batch, nil)
+}
+
+func (s *peerStore) Close() error {
+	return s.db.Close()
+}
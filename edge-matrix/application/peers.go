@@ -1,9 +1,11 @@
 package application
 
 import (
+	"github.com/emc-protocol/edge-matrix/application/proof/helper"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"math/big"
 	"sync"
+	"time"
 )
 
 type AppPeer struct {
@@ -43,6 +45,27 @@ type AppPeer struct {
 	GpuInfo string
 	// version
 	Version string
+	// hex encoded public key edge call inputs can be encrypted to
+	PubKey string
+	// time the last app status of the peer was received
+	LastSeen time.Time
+	// hardware profile, nil if not sent by the peer
+	Hardware *helper.HardwareProfile
+	// price of an edge call to the app, in wei, nil if free
+	Price *big.Int
+	// true if the app peer was reloaded from the peer store, and neither
+	// its app status was received nor the peer reached since
+	Unverified bool
+}
+
+// key identifies the app peer, a node serving several apps has an app peer per app
+func (p *AppPeer) key() string {
+	return p.ID + "/" + p.Name
+}
+
+// IsSaturated returns true if all the app slots of the peer are occupied
+func (p *AppPeer) IsSaturated() bool {
+	return p.Guage_max > 0 && p.Guage_height >= p.Guage_max
 }
 
 func (p *AppPeer) IsBetter(t *AppPeer) bool {
@@ -67,25 +90,95 @@ func NewPeerMap(peers []*AppPeer) *PeerMap {
 
 func (m *PeerMap) Put(peers ...*AppPeer) {
 	for _, peer := range peers {
-		m.Store(peer.ID, peer)
+		m.Store(peer.key(), peer)
 	}
 }
 
-// Remove removes a peer from heap if it exists
-func (m *PeerMap) Remove(peerID peer.ID) {
-	m.Delete(peerID.String())
+// Remove removes the app peers of a node if they exist, and returns them
+func (m *PeerMap) Remove(peerID peer.ID) []*AppPeer {
+	id := peerID.String()
+
+	return m.removeIf(func(p *AppPeer) bool {
+		return p.ID == id
+	})
 }
 
-func (m *PeerMap) Get(id string) *AppPeer {
-	value, ok := m.Load(id)
-	if ok {
-		return value.(*AppPeer)
+// Expire removes the app peers not seen since the deadline, and returns them
+func (m *PeerMap) Expire(deadline time.Time) []*AppPeer {
+	return m.removeIf(func(p *AppPeer) bool {
+		return p.LastSeen.Before(deadline)
+	})
+}
+
+func (m *PeerMap) removeIf(match func(p *AppPeer) bool) []*AppPeer {
+	removed := make([]*AppPeer, 0)
+
+	m.Range(func(key, value interface{}) bool {
+		if peer := value.(*AppPeer); match(peer) {
+			m.Delete(key)
+
+			removed = append(removed, peer)
+		}
+
+		return true
+	})
+
+	return removed
+}
+
+// GetApp returns the app peer of the app served by the node, any of its app peers if app is empty
+func (m *PeerMap) GetApp(id string, app string) *AppPeer {
+	if app == "" {
+		return m.Get(id)
+	}
+
+	value, ok := m.Load(id + "/" + app)
+	if !ok {
+		return nil
 	}
-	return nil
+
+	return value.(*AppPeer)
+}
+
+// Get returns an app peer of the node, the addresses of all its app peers being the same
+func (m *PeerMap) Get(id string) *AppPeer {
+	var appPeer *AppPeer
+
+	m.Range(func(key, value interface{}) bool {
+		if peer := value.(*AppPeer); peer.ID == id {
+			appPeer = peer
+
+			return false
+		}
+
+		return true
+	})
+
+	return appPeer
+}
+
+// List returns all the app peers
+func (m *PeerMap) List() []*AppPeer {
+	peers := make([]*AppPeer, 0)
+
+	m.Range(func(key, value interface{}) bool {
+		peers = append(peers, value.(*AppPeer))
+
+		return true
+	})
+
+	return peers
 }
 
 // BestPeer returns the top of heap
 func (m *PeerMap) BestPeer(skipMap map[string]bool) *AppPeer {
+	return m.BestPeerWith(skipMap, nil, LeastLoadedPolicy{})
+}
+
+// BestPeerWith returns the best peer matching the target according to the policy,
+// the target may be nil to match any peer. Unverified and saturated peers are only
+// returned if all matching peers are unverified or saturated.
+func (m *PeerMap) BestPeerWith(skipMap map[string]bool, target *EdgeCallTarget, policy PeerSelectionPolicy) *AppPeer {
 	var bestPeer *AppPeer
 
 	m.Range(func(key, value interface{}) bool {
@@ -95,7 +188,34 @@ func (m *PeerMap) BestPeer(skipMap map[string]bool) *AppPeer {
 			return true
 		}
 
-		if bestPeer == nil || peer.IsBetter(bestPeer) {
+		if target != nil && !target.Match(peer) {
+			return true
+		}
+
+		if bestPeer == nil {
+			bestPeer = peer
+
+			return true
+		}
+
+		// unverified peers are only returned if all matching peers are unverified
+		if peer.Unverified != bestPeer.Unverified {
+			if bestPeer.Unverified {
+				bestPeer = peer
+			}
+
+			return true
+		}
+
+		if peer.IsSaturated() != bestPeer.IsSaturated() {
+			if bestPeer.IsSaturated() {
+				bestPeer = peer
+			}
+
+			return true
+		}
+
+		if policy.IsBetter(peer, bestPeer) {
 			bestPeer = peer
 		}
 
@@ -0,0 +1,154 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/application/proof"
+	"github.com/emc-protocol/edge-matrix/types"
+	p2phttp "github.com/libp2p/go-libp2p-http"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+const (
+	// PocMatMulPath is the path of the gpu challenges, served by the endpoints and
+	// forwarded to the hosted apps able to run them on their gpu
+	PocMatMulPath = "/poc/matmul"
+
+	// DefaultGpuPocTimeout is how long a challenged app peer has to answer a gpu challenge
+	DefaultGpuPocTimeout = 10 * time.Second
+)
+
+var ErrMatMulUnsupported = errors.New("matmul challenge not supported by the app")
+
+// matMulAnswer is the answer to a gpu challenge
+type matMulAnswer struct {
+	Hash types.Hash `json:"hash"`
+}
+
+// appMatMulProver runs the gpu challenges through the hosted app,
+// falling back to the cpu if the app does not support them
+type appMatMulProver struct {
+	endpoint *Endpoint
+}
+
+func (p *appMatMulProver) ProveMatMul(challenge *proof.MatMulChallenge) (types.Hash, error) {
+	hash, err := p.proveWithApp(challenge)
+	if errors.Is(err, ErrMatMulUnsupported) {
+		return proof.CpuMatMulProver{}.ProveMatMul(challenge)
+	}
+
+	return hash, err
+}
+
+func (p *appMatMulProver) proveWithApp(challenge *proof.MatMulChallenge) (types.Hash, error) {
+	raw, err := json.Marshal(challenge)
+	if err != nil {
+		return types.ZeroHash, err
+	}
+
+	resp, err := p.endpoint.httpClient.SendRequest(
+		http.MethodPost,
+		p.endpoint.appUrl+PocMatMulPath,
+		map[string]string{"Content-Type": "application/json"},
+		raw,
+	)
+	if err != nil {
+		return types.ZeroHash, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNotImplemented {
+		return types.ZeroHash, ErrMatMulUnsupported
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return types.ZeroHash, fmt.Errorf("matmul challenge failed, status %d", resp.StatusCode)
+	}
+
+	answer := &matMulAnswer{}
+	if err := json.Unmarshal(resp.Body, answer); err != nil {
+		return types.ZeroHash, err
+	}
+
+	return answer.Hash, nil
+}
+
+// SetMatMulProver sets the prover of the gpu challenges of the endpoint
+func (e *Endpoint) SetMatMulProver(prover proof.MatMulProver) {
+	e.matMulProver = prover
+}
+
+// handlePocMatMul answers a gpu challenge
+func (e *Endpoint) handlePocMatMul(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	challenge := &proof.MatMulChallenge{}
+	if err := json.NewDecoder(r.Body).Decode(challenge); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if err := challenge.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	hash, err := e.matMulProver.ProveMatMul(challenge)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	raw, err := json.Marshal(&matMulAnswer{Hash: hash})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(raw)
+}
+
+// ChallengeMatMul sends a gpu challenge to the endpoint of the peer and returns its answer
+func ChallengeMatMul(clientHost host.Host, peerId string, challenge *proof.MatMulChallenge, timeout time.Duration) (types.Hash, error) {
+	tr := &http.Transport{}
+	tr.RegisterProtocol("libp2p", p2phttp.NewTransport(clientHost, p2phttp.ProtocolOption(protocol.ID(ProtoTagEcApp))))
+	client := &http.Client{Transport: tr, Timeout: timeout}
+
+	raw, err := json.Marshal(challenge)
+	if err != nil {
+		return types.ZeroHash, err
+	}
+
+	res, err := client.Post(fmt.Sprintf("libp2p://%s%s", peerId, PocMatMulPath), "application/json", bytes.NewBuffer(raw))
+	if err != nil {
+		return types.ZeroHash, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return types.ZeroHash, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return types.ZeroHash, fmt.Errorf("matmul challenge failed, status %d: %s", res.StatusCode, body)
+	}
+
+	answer := &matMulAnswer{}
+	if err := json.Unmarshal(body, answer); err != nil {
+		return types.ZeroHash, err
+	}
+
+	return answer.Hash, nil
+}
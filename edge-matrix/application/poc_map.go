@@ -11,6 +11,12 @@ type PocMap struct {
 	all map[string]*proof.PocCpuRequest
 }
 
+func newPocMap() *PocMap {
+	return &PocMap{
+		all: make(map[string]*proof.PocCpuRequest),
+	}
+}
+
 // add inserts the given PocCpuRequest into the map. Returns false
 // if it already exists. [thread-safe]
 func (m *PocMap) add(msg *proof.PocCpuRequest) bool {
@@ -0,0 +1,444 @@
+package application
+
+import (
+	"crypto/rand"
+	"math"
+	mrand "math/rand"
+	"sync"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/application/proof"
+	"github.com/emc-protocol/edge-matrix/helper/hex"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// DefaultPocInterval is how often an app peer is challenged
+	DefaultPocInterval = 30 * time.Second
+	// DefaultPocTimeout is how long a challenged app peer has to answer
+	DefaultPocTimeout = 30 * time.Second
+	// DefaultPocMaxFailures is the number of consecutive failed challenges
+	// after which an app peer is only routed to if no other peer matches
+	DefaultPocMaxFailures = 3
+
+	pocSeedSize = 32
+)
+
+// PocStats is the proof of compute challenge history of a node
+type PocStats struct {
+	Passed uint64 `json:"passed"`
+	Failed uint64 `json:"failed"`
+	// failed challenges since the last passed one
+	Failures      uint64    `json:"failures"`
+	LastChallenge time.Time `json:"last_challenge"`
+	// answer time of the last passed challenge
+	Latency time.Duration `json:"latency"`
+	// hashes per second estimated from the last passed cpu challenge
+	Power int64 `json:"power"`
+	// multiply-adds per second estimated from the last passed gpu challenge
+	GpuPower int64 `json:"gpu_power"`
+}
+
+// PocHistory records the results of the challenges of each node
+type PocHistory struct {
+	sync.RWMutex
+
+	stats map[string]*PocStats
+}
+
+func NewPocHistory() *PocHistory {
+	return &PocHistory{
+		stats: make(map[string]*PocStats),
+	}
+}
+
+// Record records the result of a challenge of the node, and returns its updated stats
+func (h *PocHistory) Record(nodeId string, kind string, passed bool, power int64, latency time.Duration, now time.Time) PocStats {
+	h.Lock()
+	defer h.Unlock()
+
+	stats, ok := h.stats[nodeId]
+	if !ok {
+		stats = &PocStats{}
+		h.stats[nodeId] = stats
+	}
+
+	stats.LastChallenge = now
+
+	if passed {
+		stats.Passed++
+		stats.Failures = 0
+		stats.Latency = latency
+	} else {
+		stats.Failed++
+		stats.Failures++
+		power = 0
+	}
+
+	if kind == proof.PocKindMatMul {
+		stats.GpuPower = power
+	} else {
+		stats.Power = power
+	}
+
+	return *stats
+}
+
+// Get returns the stats of the node, false if it was never challenged
+func (h *PocHistory) Get(nodeId string) (PocStats, bool) {
+	h.RLock()
+	defer h.RUnlock()
+
+	stats, ok := h.stats[nodeId]
+	if !ok {
+		return PocStats{}, false
+	}
+
+	return *stats, true
+}
+
+// Failing returns the nodes which failed their last DefaultPocMaxFailures challenges
+func (h *PocHistory) Failing() map[string]bool {
+	h.RLock()
+	defer h.RUnlock()
+
+	failing := make(map[string]bool)
+
+	for nodeId, stats := range h.stats {
+		if stats.Failures >= DefaultPocMaxFailures {
+			failing[nodeId] = true
+		}
+	}
+
+	return failing
+}
+
+// hashProofPower estimates the hashes per second of a node from the time it took
+// to compute count hash proofs of the target
+func hashProofPower(target string, count int, latency time.Duration) int64 {
+	if latency <= 0 {
+		return 0
+	}
+
+	hashes := float64(count) * math.Pow(16, float64(len(target)))
+
+	return int64(hashes / latency.Seconds())
+}
+
+// PocReporter reports the results of the challenges, to be accounted in the E-power of the nodes
+type PocReporter interface {
+	SubmitPocResult(validator string, nodeId string, kind string, ticket int64, power int64) error
+}
+
+// MatMulChallenger sends the gpu challenges to the app peers
+type MatMulChallenger interface {
+	ChallengeMatMul(peerId string, challenge *proof.MatMulChallenge, timeout time.Duration) (types.Hash, error)
+}
+
+// PocScheduler periodically challenges random app peers with fresh seeds, verifies the
+// hash proofs they answer within the timeout, and records and reports the results
+type PocScheduler struct {
+	logger hclog.Logger
+
+	// id of the challenging node
+	validator string
+
+	client          SyncAppPeerClient
+	syncer          Syncer
+	blockchainStore blockchainStore
+	reporter        PocReporter
+	// gpu challenges sender, nil to only send cpu challenges
+	matMulChallenger MatMulChallenger
+
+	history *PocHistory
+	// challenges in flight, by node id
+	pending     *PocMap
+	queue       *proof.PocQueue
+	submitQueue *proof.PocSubmitQueue
+
+	interval   time.Duration
+	timeout    time.Duration
+	gpuTimeout time.Duration
+
+	// hash proofs computed by the app peers for a challenge, see syncAppService.GetData
+	proofTarget string
+	proofCount  int
+	// workload of the gpu challenges
+	matMulSize   uint32
+	matMulRounds uint32
+
+	closeCh chan struct{}
+}
+
+func NewPocScheduler(
+	logger hclog.Logger,
+	validator string,
+	client SyncAppPeerClient,
+	syncer Syncer,
+	blockchainStore blockchainStore,
+	reporter PocReporter,
+) *PocScheduler {
+	return &PocScheduler{
+		logger:          logger.Named("poc"),
+		validator:       validator,
+		client:          client,
+		syncer:          syncer,
+		blockchainStore: blockchainStore,
+		reporter:        reporter,
+		history:         NewPocHistory(),
+		pending:         newPocMap(),
+		queue:           proof.NewPocQueue(),
+		submitQueue:     proof.NewPocSubmitQueue(),
+		interval:        DefaultPocInterval,
+		timeout:         DefaultPocTimeout,
+		gpuTimeout:      DefaultGpuPocTimeout,
+		proofTarget:     proof.DefaultHashProofTarget,
+		proofCount:      proof.DefaultHashProofCount,
+		matMulSize:      proof.DefaultMatMulSize,
+		matMulRounds:    proof.DefaultMatMulRounds,
+		closeCh:         make(chan struct{}),
+	}
+}
+
+// SetInterval sets how often an app peer is challenged
+func (s *PocScheduler) SetInterval(interval time.Duration) {
+	s.interval = interval
+}
+
+// SetTimeout sets how long a challenged app peer has to answer
+func (s *PocScheduler) SetTimeout(timeout time.Duration) {
+	s.timeout = timeout
+}
+
+// SetMatMulChallenger enables the gpu challenges of the app peers with gpus
+func (s *PocScheduler) SetMatMulChallenger(challenger MatMulChallenger) {
+	s.matMulChallenger = challenger
+}
+
+// History returns the challenge history of the nodes
+func (s *PocScheduler) History() *PocHistory {
+	return s.history
+}
+
+// Start starts the scheduling, challenge and report processes
+func (s *PocScheduler) Start() {
+	go s.startScheduleProcess()
+	go s.startChallengeProcess()
+	go s.startSubmitProcess()
+}
+
+// Close stops the processes of the scheduler
+func (s *PocScheduler) Close() {
+	close(s.closeCh)
+	s.queue.Close()
+	s.submitQueue.Close()
+}
+
+func (s *PocScheduler) startScheduleProcess() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case <-ticker.C:
+			s.schedule()
+		}
+	}
+}
+
+// schedule queues a challenge of a random app peer not being challenged
+func (s *PocScheduler) schedule() {
+	candidates := make([]*AppPeer, 0)
+	seen := make(map[string]bool)
+
+	for _, appPeer := range s.syncer.GetAppPeers() {
+		if seen[appPeer.ID] || appPeer.ID == s.validator {
+			continue
+		}
+
+		seen[appPeer.ID] = true
+
+		if _, ok := s.pending.get(appPeer.ID); !ok {
+			candidates = append(candidates, appPeer)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return
+	}
+
+	//nolint:gosec
+	appPeer := candidates[mrand.Intn(len(candidates))]
+
+	seed, err := newPocSeed()
+	if err != nil {
+		s.logger.Error("failed to generate poc seed", "err", err)
+
+		return
+	}
+
+	request := &proof.PocCpuRequest{
+		NodeId: appPeer.ID,
+		Seed:   seed,
+		Start:  time.Now(),
+		Kind:   proof.PocKindHash,
+	}
+
+	// the app peers advertising gpus get challenges exercising them
+	if s.matMulChallenger != nil && appPeer.Hardware != nil && len(appPeer.Hardware.Gpus) > 0 {
+		request.Kind = proof.PocKindMatMul
+	}
+
+	if s.blockchainStore != nil {
+		request.BlockNum = s.blockchainStore.Header().Number
+	}
+
+	if !s.pending.add(request) {
+		return
+	}
+
+	s.queue.AddTask(&proof.PocCpuData{
+		Validator: appPeer.ID,
+		Seed:      seed,
+		ModelName: appPeer.Name,
+		Kind:      request.Kind,
+	}, proof.PriorityPushPoc)
+}
+
+func (s *PocScheduler) startChallengeProcess() {
+	for {
+		task := s.queue.PopTask()
+		if task == nil {
+			return
+		}
+
+		data := task.GetPocCpuDataInfo()
+		s.queue.DeleteTask(data.Validator)
+
+		go s.challenge(data)
+	}
+}
+
+// challenge sends the challenge to the node, and records and reports the result
+func (s *PocScheduler) challenge(data *proof.PocCpuData) {
+	request, ok := s.pending.get(data.Validator)
+	if !ok {
+		return
+	}
+
+	defer s.pending.remove(request)
+
+	var (
+		passed  bool
+		power   int64
+		latency time.Duration
+		err     error
+	)
+
+	if request.Kind == proof.PocKindMatMul {
+		passed, power, latency, err = s.challengeMatMul(request)
+	} else {
+		passed, power, latency, err = s.challengeHash(request)
+	}
+
+	s.history.Record(request.NodeId, request.Kind, passed, power, latency, time.Now())
+
+	s.logger.Debug("poc challenge", "id", request.NodeId, "kind", request.Kind, "passed", passed, "latency", latency, "err", err)
+
+	s.submitQueue.AddTask(&proof.PocSubmitData{
+		ValidationTicket: request.Start.UnixMilli(),
+		Validator:        s.validator,
+		Power:            power,
+		TargetNodeID:     request.NodeId,
+		Kind:             request.Kind,
+	}, proof.PriorityPushPoc)
+}
+
+// challengeHash requests the hash proofs of the seed to the node
+func (s *PocScheduler) challengeHash(request *proof.PocCpuRequest) (bool, int64, time.Duration, error) {
+	start := time.Now()
+
+	peerID, err := peer.Decode(request.NodeId)
+	if err != nil {
+		return false, 0, 0, err
+	}
+
+	proofs, err := s.client.GetPeerData(peerID, request.Seed, s.timeout)
+	latency := time.Since(start)
+
+	if err != nil {
+		return false, 0, latency, err
+	}
+
+	if latency > s.timeout || !proof.ValidateHashProofs(request.Seed, s.proofTarget, s.proofCount, proofs) {
+		return false, 0, latency, nil
+	}
+
+	return true, hashProofPower(s.proofTarget, s.proofCount, latency), latency, nil
+}
+
+// challengeMatMul sends a gpu challenge to the node, its answer being checked
+// against the reference computed on the cpu meanwhile
+func (s *PocScheduler) challengeMatMul(request *proof.PocCpuRequest) (bool, int64, time.Duration, error) {
+	challenge := &proof.MatMulChallenge{
+		Seed:   request.Seed,
+		Size:   s.matMulSize,
+		Rounds: s.matMulRounds,
+	}
+
+	expectedCh := make(chan types.Hash, 1)
+
+	go func() {
+		expected, _ := proof.CpuMatMulProver{}.ProveMatMul(challenge)
+		expectedCh <- expected
+	}()
+
+	start := time.Now()
+	hash, err := s.matMulChallenger.ChallengeMatMul(request.NodeId, challenge, s.gpuTimeout)
+	latency := time.Since(start)
+	expected := <-expectedCh
+
+	if err != nil {
+		return false, 0, latency, err
+	}
+
+	if latency > s.gpuTimeout || hash != expected || latency <= 0 {
+		return false, 0, latency, nil
+	}
+
+	return true, int64(float64(challenge.Ops()) / latency.Seconds()), latency, nil
+}
+
+func (s *PocScheduler) startSubmitProcess() {
+	for {
+		task := s.submitQueue.PopTask()
+		if task == nil {
+			return
+		}
+
+		data := task.GetPocSubmitData()
+		s.submitQueue.DeleteTask(data.TargetNodeID)
+
+		if s.reporter == nil {
+			continue
+		}
+
+		if err := s.reporter.SubmitPocResult(data.Validator, data.TargetNodeID, data.Kind, data.ValidationTicket, data.Power); err != nil {
+			s.logger.Warn("failed to submit poc result", "id", data.TargetNodeID, "err", err)
+		}
+	}
+}
+
+// newPocSeed returns a random hex encoded seed
+func newPocSeed() (string, error) {
+	seed := make([]byte, pocSeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToHex(seed), nil
+}
@@ -0,0 +1,259 @@
+package application
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/application/proof"
+	"github.com/emc-protocol/edge-matrix/application/proof/helper"
+	"github.com/emc-protocol/edge-matrix/helper/keccak"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/hashicorp/go-hclog"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+type pocClient struct {
+	SyncAppPeerClient
+
+	// nodes answering their challenges
+	honest map[peer.ID]bool
+	target string
+	count  int
+}
+
+func (c *pocClient) GetPeerData(id peer.ID, seed string, timeout time.Duration) (map[string][]byte, error) {
+	if !c.honest[id] {
+		return nil, errors.New("no answer")
+	}
+
+	proofs := make(map[string][]byte)
+
+	for i := 0; i < c.count; i++ {
+		proofSeed := proof.HashProofSeed(seed, i)
+
+		_, bytes, err := proof.ProofByCalcHash(proofSeed, c.target, timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		proofs[proofSeed] = bytes
+	}
+
+	return proofs, nil
+}
+
+type pocRecorder struct {
+	sync.Mutex
+
+	results map[string]int64
+}
+
+func (r *pocRecorder) SubmitPocResult(validator string, nodeId string, kind string, ticket int64, power int64) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.results[nodeId] = power
+
+	return nil
+}
+
+func TestPocScheduler_Challenge(t *testing.T) {
+	t.Parallel()
+
+	newPeerID := func() peer.ID {
+		key, _, err := libp2pCrypto.GenerateEd25519Key(nil)
+		assert.NoError(t, err)
+
+		id, err := peer.IDFromPrivateKey(key)
+		assert.NoError(t, err)
+
+		return id
+	}
+
+	nodeA, nodeB := newPeerID(), newPeerID()
+
+	client := &pocClient{honest: map[peer.ID]bool{nodeA: true}, target: "0", count: 4}
+	s := NewSyncer(hclog.NewNullLogger(), client, nil, nil, nil).(*syncer)
+	s.putToPeerMap(&AppPeer{ID: nodeA.String(), Name: "sd", Distance: big.NewInt(2)})
+	s.putToPeerMap(&AppPeer{ID: nodeB.String(), Name: "sd", Distance: big.NewInt(1)})
+
+	reporter := &pocRecorder{results: make(map[string]int64)}
+	scheduler := NewPocScheduler(hclog.NewNullLogger(), "validator", client, s, nil, reporter)
+	scheduler.proofTarget, scheduler.proofCount = client.target, client.count
+	s.SetPocHistory(scheduler.History())
+
+	for _, id := range []peer.ID{nodeA, nodeB} {
+		request := &proof.PocCpuRequest{NodeId: id.String(), Seed: "0x0a0b", Start: time.Now()}
+		assert.True(t, scheduler.pending.add(request))
+
+		scheduler.challenge(&proof.PocCpuData{Validator: id.String(), Seed: request.Seed})
+
+		_, pending := scheduler.pending.get(id.String())
+		assert.False(t, pending)
+	}
+
+	stats, ok := scheduler.History().Get(nodeA.String())
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), stats.Passed)
+	assert.Greater(t, stats.Power, int64(0))
+
+	stats, ok = scheduler.History().Get(nodeB.String())
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), stats.Failed)
+	assert.Equal(t, int64(0), stats.Power)
+
+	// the results are reported, the failed challenges with no power
+	assert.Equal(t, 2, scheduler.submitQueue.Len())
+
+	go scheduler.startSubmitProcess()
+	defer scheduler.Close()
+
+	assert.Eventually(t, func() bool {
+		reporter.Lock()
+		defer reporter.Unlock()
+
+		return len(reporter.results) == 2 && reporter.results[nodeA.String()] > 0 && reporter.results[nodeB.String()] == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// the nodes failing their challenges are routed to last
+	policy := LeastLoadedPolicy{}
+	assert.Equal(t, nodeB.String(), s.BestAppPeer(nil, nil, policy).ID)
+
+	for i := 0; i < DefaultPocMaxFailures; i++ {
+		scheduler.History().Record(nodeB.String(), proof.PocKindHash, false, 0, 0, time.Now())
+	}
+
+	assert.Equal(t, nodeA.String(), s.BestAppPeer(nil, nil, policy).ID)
+	assert.Equal(t, nodeB.String(), s.BestAppPeer(map[string]bool{nodeA.String(): true}, nil, policy).ID)
+}
+
+func TestPocHistory_Record(t *testing.T) {
+	t.Parallel()
+
+	history := NewPocHistory()
+	now := time.Now()
+
+	history.Record("a", proof.PocKindHash, false, 0, 0, now)
+	history.Record("a", proof.PocKindMatMul, false, 0, 0, now)
+	assert.Empty(t, history.Failing())
+
+	history.Record("a", proof.PocKindHash, false, 0, 0, now)
+	assert.True(t, history.Failing()["a"])
+
+	history.Record("a", proof.PocKindMatMul, true, 200, time.Second, now)
+	stats := history.Record("a", proof.PocKindHash, true, 100, time.Second, now)
+	assert.Empty(t, history.Failing())
+	assert.Equal(t, uint64(3), stats.Failed)
+	assert.Equal(t, uint64(0), stats.Failures)
+	assert.Equal(t, uint64(2), stats.Passed)
+	assert.Equal(t, int64(100), stats.Power)
+	assert.Equal(t, int64(200), stats.GpuPower)
+	assert.Equal(t, int64(16*4), hashProofPower("0", 4, time.Second))
+}
+
+type matMulClient struct {
+	// nodes answering their gpu challenges
+	honest map[string]bool
+}
+
+func (c *matMulClient) ChallengeMatMul(peerId string, challenge *proof.MatMulChallenge, timeout time.Duration) (types.Hash, error) {
+	if !c.honest[peerId] {
+		return types.StringToHash("0x01"), nil
+	}
+
+	return proof.CpuMatMulProver{}.ProveMatMul(challenge)
+}
+
+func TestPocScheduler_ChallengeMatMul(t *testing.T) {
+	t.Parallel()
+
+	gpuPeer := &AppPeer{
+		ID:       "gpu",
+		Name:     "sd",
+		Distance: big.NewInt(1),
+		Hardware: &helper.HardwareProfile{Gpus: []helper.GpuProfile{{Model: "RTX 4090", Vram: 24564}}},
+	}
+
+	s := NewSyncer(hclog.NewNullLogger(), &pocClient{}, nil, nil, nil).(*syncer)
+	s.putToPeerMap(gpuPeer)
+
+	scheduler := NewPocScheduler(hclog.NewNullLogger(), "validator", &pocClient{}, s, nil, nil)
+	scheduler.matMulSize, scheduler.matMulRounds = 16, 2
+
+	// without a gpu challenger, the app peers get cpu challenges
+	scheduler.schedule()
+	request, ok := scheduler.pending.get(gpuPeer.ID)
+	assert.True(t, ok)
+	assert.Equal(t, proof.PocKindHash, request.Kind)
+	scheduler.pending.remove(request)
+	scheduler.queue.DeleteTask(gpuPeer.ID)
+
+	// the app peers with gpus get gpu challenges
+	scheduler.SetMatMulChallenger(&matMulClient{honest: map[string]bool{"gpu": true}})
+	scheduler.schedule()
+	request, ok = scheduler.pending.get(gpuPeer.ID)
+	assert.True(t, ok)
+	assert.Equal(t, proof.PocKindMatMul, request.Kind)
+
+	scheduler.challenge(&proof.PocCpuData{Validator: gpuPeer.ID, Seed: request.Seed, Kind: request.Kind})
+
+	stats, _ := scheduler.History().Get(gpuPeer.ID)
+	assert.Equal(t, uint64(1), stats.Passed)
+	assert.Greater(t, stats.GpuPower, int64(0))
+
+	// wrong answers fail
+	request = &proof.PocCpuRequest{NodeId: "cpu", Seed: "0x0a0b", Kind: proof.PocKindMatMul}
+	assert.True(t, scheduler.pending.add(request))
+	scheduler.challenge(&proof.PocCpuData{Validator: "cpu", Seed: request.Seed, Kind: request.Kind})
+
+	stats, _ = scheduler.History().Get("cpu")
+	assert.Equal(t, uint64(1), stats.Failed)
+	assert.Equal(t, int64(0), stats.GpuPower)
+}
+
+func TestEndpoint_PocMatMul(t *testing.T) {
+	t.Parallel()
+
+	endpoint := &Endpoint{matMulProver: proof.CpuMatMulProver{}}
+	challenge := &proof.MatMulChallenge{Seed: "0x0a0b", Size: 8, Rounds: 3}
+
+	raw, err := json.Marshal(challenge)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	endpoint.handlePocMatMul(w, httptest.NewRequest(http.MethodPost, PocMatMulPath, bytes.NewReader(raw)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	answer := &matMulAnswer{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), answer))
+	assert.True(t, proof.VerifyMatMul(challenge, answer.Hash))
+
+	// the workload is deterministic, and depends on the seed and rounds
+	assert.False(t, proof.VerifyMatMul(&proof.MatMulChallenge{Seed: "0x0a0c", Size: 8, Rounds: 3}, answer.Hash))
+	assert.False(t, proof.VerifyMatMul(&proof.MatMulChallenge{Seed: "0x0a0b", Size: 8, Rounds: 2}, answer.Hash))
+
+	// the reference of a single round is the plain product of the inputs
+	a, b := proof.MatMulInputs("0x0a0b", 2)
+	product := []uint32{a[0]*b[0] + a[1]*b[2], a[0]*b[1] + a[1]*b[3], a[2]*b[0] + a[3]*b[2], a[2]*b[1] + a[3]*b[3]}
+	out := make([]byte, 16)
+
+	for i, v := range product {
+		binary.LittleEndian.PutUint32(out[4*i:], v)
+	}
+
+	assert.True(t, proof.VerifyMatMul(&proof.MatMulChallenge{Seed: "0x0a0b", Size: 2, Rounds: 1}, types.BytesToHash(keccak.Keccak256(nil, out))))
+
+	w = httptest.NewRecorder()
+	endpoint.handlePocMatMul(w, httptest.NewRequest(http.MethodPost, PocMatMulPath, bytes.NewReader([]byte(`{"seed":"0x0a","size":4096,"rounds":1}`))))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -56,6 +56,29 @@ func generateHash(seed string, target string, timeout time.Duration) (string, []
 	return "", nil, nil
 }
 
+// HashProofSeed returns the seed of the i-th hash proof of a challenge
+func HashProofSeed(seed string, i int) string {
+	return fmt.Sprintf("%s,%d", seed, i)
+}
+
+// ValidateHashProofs returns true if proofs holds the count hash proofs of the challenge seed
+func ValidateHashProofs(seed string, target string, count int, proofs map[string][]byte) bool {
+	if len(proofs) != count {
+		return false
+	}
+
+	for i := 0; i < count; i++ {
+		proofSeed := HashProofSeed(seed, i)
+
+		bytes, ok := proofs[proofSeed]
+		if !ok || len(bytes) == 0 || !ValidateHash(proofSeed, target, bytes) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func ValidateHash(seed string, target string, bytes []byte) bool {
 	dst := append([]byte(seed), bytes...)
 	keccak256Hash := crypto.Keccak256Hash(dst)
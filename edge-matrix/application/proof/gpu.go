@@ -1,5 +1,166 @@
 package proof
 
+import (
+	"encoding/binary"
+	"errors"
+	"runtime"
+	"sync"
+
+	"github.com/emc-protocol/edge-matrix/helper/keccak"
+	"github.com/emc-protocol/edge-matrix/types"
+)
+
 const (
 	AppOriginSD = "StableDiffusion"
 )
+
+const (
+	// DefaultMatMulSize is the size of the matrices of the gpu challenges
+	DefaultMatMulSize = 512
+	// DefaultMatMulRounds is the number of matrix multiplications of the gpu challenges
+	DefaultMatMulRounds = 8
+
+	// MaxMatMulSize bounds the matrices a node computes for a challenge
+	MaxMatMulSize = 2048
+	// MaxMatMulRounds bounds the multiplications a node computes for a challenge
+	MaxMatMulRounds = 32
+)
+
+var ErrInvalidMatMulChallenge = errors.New("invalid matmul challenge")
+
+// MatMulChallenge is a gpu proof of work: a chain of integer matrix multiplications,
+// deterministic on any accelerator, whose result hash the challenger checks on its cpu.
+//
+// The matrices A and B are Size x Size uint32 matrices in row-major order, filled from
+// MatMulInputs. The workload computes C = A x B, then C = C x B Rounds-1 times, all the
+// arithmetic wrapping around modulo 2^32, and answers the keccak256 hash of C, its
+// elements being encoded as little endian uint32.
+type MatMulChallenge struct {
+	Seed   string `json:"seed"`
+	Size   uint32 `json:"size"`
+	Rounds uint32 `json:"rounds"`
+}
+
+// Validate returns an error if the challenge is out of bounds
+func (c *MatMulChallenge) Validate() error {
+	if c.Seed == "" || c.Size == 0 || c.Size > MaxMatMulSize || c.Rounds == 0 || c.Rounds > MaxMatMulRounds {
+		return ErrInvalidMatMulChallenge
+	}
+
+	return nil
+}
+
+// Ops returns the number of multiply-adds of the challenge
+func (c *MatMulChallenge) Ops() uint64 {
+	size := uint64(c.Size)
+
+	return uint64(c.Rounds) * size * size * size
+}
+
+// MatMulInputs returns the matrices A and B of the seed. Their elements are read as
+// little endian uint32 from the concatenation of keccak256(seed || uint64 counter),
+// the counter starting at 0 and encoded big endian, A being filled first.
+func MatMulInputs(seed string, size uint32) ([]uint32, []uint32) {
+	n := int(size) * int(size)
+	values := make([]uint32, 0, 2*n+8)
+
+	buf := make([]byte, len(seed)+8)
+	copy(buf, seed)
+
+	for counter := uint64(0); len(values) < 2*n; counter++ {
+		binary.BigEndian.PutUint64(buf[len(seed):], counter)
+		hash := keccak.Keccak256(nil, buf)
+
+		for i := 0; i < len(hash); i += 4 {
+			values = append(values, binary.LittleEndian.Uint32(hash[i:]))
+		}
+	}
+
+	return values[:n], values[n : 2*n]
+}
+
+// MatMulProver computes the result hash of a gpu challenge
+type MatMulProver interface {
+	ProveMatMul(challenge *MatMulChallenge) (types.Hash, error)
+}
+
+// CpuMatMulProver computes the gpu challenges on the cpu. It is the reference the
+// challengers verify the answers with, and the fallback of the nodes without gpu.
+type CpuMatMulProver struct{}
+
+func (CpuMatMulProver) ProveMatMul(challenge *MatMulChallenge) (types.Hash, error) {
+	if err := challenge.Validate(); err != nil {
+		return types.ZeroHash, err
+	}
+
+	size := int(challenge.Size)
+	a, b := MatMulInputs(challenge.Seed, challenge.Size)
+
+	// b is transposed so that the rows of both operands are read sequentially
+	bt := make([]uint32, len(b))
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			bt[j*size+i] = b[i*size+j]
+		}
+	}
+
+	c := a
+	for round := uint32(0); round < challenge.Rounds; round++ {
+		c = mulTransposed(c, bt, size)
+	}
+
+	out := make([]byte, 4*len(c))
+	for i, v := range c {
+		binary.LittleEndian.PutUint32(out[4*i:], v)
+	}
+
+	return types.BytesToHash(keccak.Keccak256(nil, out)), nil
+}
+
+// VerifyMatMul returns true if hash is the result hash of the challenge
+func VerifyMatMul(challenge *MatMulChallenge, hash types.Hash) bool {
+	expected, err := CpuMatMulProver{}.ProveMatMul(challenge)
+
+	return err == nil && expected == hash
+}
+
+// mulTransposed returns a x b, bt being b transposed, the rows being computed in parallel
+func mulTransposed(a, bt []uint32, size int) []uint32 {
+	c := make([]uint32, size*size)
+	rows := make(chan int, size)
+
+	for i := 0; i < size; i++ {
+		rows <- i
+	}
+
+	close(rows)
+
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range rows {
+				row := a[i*size : (i+1)*size]
+
+				for j := 0; j < size; j++ {
+					col := bt[j*size : (j+1)*size]
+					sum := uint32(0)
+
+					for k, v := range row {
+						sum += v * col[k]
+					}
+
+					c[i*size+j] = sum
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return c
+}
@@ -0,0 +1,254 @@
+package helper
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jaypipes/ghw"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+const (
+	// HardwareProfileVersion is the version of the hardware profile format
+	HardwareProfileVersion = 1
+
+	// nvidiaSmiTimeout bounds the nvidia-smi query of the nvidia gpus
+	nvidiaSmiTimeout = 5 * time.Second
+
+	mb = 1024 * 1024
+)
+
+// HardwareProfile is the typed hardware inventory of a node
+type HardwareProfile struct {
+	// format version, HardwareProfileVersion
+	Version uint32       `json:"version"`
+	Cpu     CpuProfile   `json:"cpu"`
+	Gpus    []GpuProfile `json:"gpus"`
+	// memory in MB
+	MemTotal uint64 `json:"mem_total"`
+	MemFree  uint64 `json:"mem_free"`
+	// disk of the node data dir in MB
+	DiskTotal uint64 `json:"disk_total"`
+	DiskFree  uint64 `json:"disk_free"`
+}
+
+// CpuProfile describes the cpus of a node
+type CpuProfile struct {
+	Vendor string `json:"vendor"`
+	Model  string `json:"model"`
+	// physical cores
+	Cores uint32 `json:"cores"`
+	// logical cores
+	Threads uint32  `json:"threads"`
+	Mhz     float64 `json:"mhz"`
+}
+
+// GpuProfile describes a gpu of a node
+type GpuProfile struct {
+	Vendor string `json:"vendor"`
+	Model  string `json:"model"`
+	// video memory in MB, 0 if unknown
+	Vram   uint64 `json:"vram"`
+	Driver string `json:"driver"`
+	// cuda compute capability of the nvidia gpus, like 8.9
+	ComputeCapability string `json:"compute_capability,omitempty"`
+}
+
+// GetHardwareProfile returns the hardware profile of the node, the disk being the one of dir.
+// The parts that can not be read are left empty.
+func GetHardwareProfile(dir string) *HardwareProfile {
+	profile := &HardwareProfile{
+		Version: HardwareProfileVersion,
+		Cpu:     getCpuProfile(),
+		Gpus:    getGpuProfiles(),
+	}
+
+	profile.refreshUsage(dir)
+
+	return profile
+}
+
+// WithUsage returns a copy of the profile with the current free memory and disk
+func (p *HardwareProfile) WithUsage(dir string) *HardwareProfile {
+	profile := *p
+	profile.Gpus = append([]GpuProfile{}, p.Gpus...)
+	profile.refreshUsage(dir)
+
+	return &profile
+}
+
+// MaxVram returns the vram of the largest gpu in MB
+func (p *HardwareProfile) MaxVram() uint64 {
+	max := uint64(0)
+
+	for _, gpu := range p.Gpus {
+		if gpu.Vram > max {
+			max = gpu.Vram
+		}
+	}
+
+	return max
+}
+
+// HasGpu returns true if the vendor and model of one of the gpus contain model, ignoring case
+func (p *HardwareProfile) HasGpu(model string) bool {
+	model = strings.ToLower(model)
+
+	for _, gpu := range p.Gpus {
+		if strings.Contains(strings.ToLower(gpu.Vendor+" "+gpu.Model), model) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *HardwareProfile) refreshUsage(dir string) {
+	if v, err := mem.VirtualMemory(); err == nil && v != nil {
+		p.MemTotal = v.Total / mb
+		p.MemFree = v.Available / mb
+	}
+
+	if dir == "" {
+		dir = string(filepath.Separator)
+	}
+
+	if usage, err := disk.Usage(dir); err == nil && usage != nil {
+		p.DiskTotal = usage.Total / mb
+		p.DiskFree = usage.Free / mb
+	}
+}
+
+func getCpuProfile() CpuProfile {
+	profile := CpuProfile{}
+
+	if infos, err := cpu.Info(); err == nil && len(infos) > 0 {
+		profile.Vendor = infos[0].VendorID
+		profile.Model = infos[0].ModelName
+		profile.Mhz = infos[0].Mhz
+	}
+
+	if cores, err := cpu.Counts(false); err == nil {
+		profile.Cores = uint32(cores)
+	}
+
+	if threads, err := cpu.Counts(true); err == nil {
+		profile.Threads = uint32(threads)
+	}
+
+	return profile
+}
+
+func getGpuProfiles() []GpuProfile {
+	info, err := ghw.GPU()
+	if err != nil {
+		return []GpuProfile{}
+	}
+
+	nvidiaGpus := getNvidiaGpus()
+	profiles := make([]GpuProfile, 0, len(info.GraphicsCards))
+
+	for _, card := range info.GraphicsCards {
+		profile := GpuProfile{}
+
+		if device := card.DeviceInfo; device != nil {
+			if device.Vendor != nil {
+				profile.Vendor = device.Vendor.Name
+			}
+
+			if device.Product != nil {
+				profile.Model = device.Product.Name
+			}
+
+			profile.Driver = device.Driver
+		}
+
+		// amdgpu exposes the vram in sysfs, nvidia gpus are queried with nvidia-smi
+		profile.Vram = readSysfsVram(card.Address)
+
+		if nvidia, ok := nvidiaGpus[pciBusId(card.Address)]; ok {
+			profile.Vram = nvidia.Vram
+			profile.Driver = nvidia.Driver
+			profile.ComputeCapability = nvidia.ComputeCapability
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	return profiles
+}
+
+func readSysfsVram(address string) uint64 {
+	raw, err := os.ReadFile(filepath.Join("/sys/bus/pci/devices", address, "mem_info_vram_total"))
+	if err != nil {
+		return 0
+	}
+
+	vram, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return vram / mb
+}
+
+// getNvidiaGpus returns the nvidia gpus by pci bus id, empty if nvidia-smi is not available
+func getNvidiaGpus() map[string]GpuProfile {
+	ctx, cancel := context.WithTimeout(context.Background(), nvidiaSmiTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(
+		ctx,
+		"nvidia-smi",
+		"--query-gpu=pci.bus_id,memory.total,driver_version,compute_cap",
+		"--format=csv,noheader,nounits",
+	).Output()
+	if err != nil {
+		return map[string]GpuProfile{}
+	}
+
+	return parseNvidiaSmi(string(out))
+}
+
+// parseNvidiaSmi parses the csv lines of bus id, vram in MB, driver version and compute capability
+func parseNvidiaSmi(out string) map[string]GpuProfile {
+	gpus := make(map[string]GpuProfile)
+
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) < 4 {
+			continue
+		}
+
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		vram, _ := strconv.ParseUint(fields[1], 10, 64)
+
+		gpus[pciBusId(fields[0])] = GpuProfile{
+			Vram:              vram,
+			Driver:            "nvidia " + fields[2],
+			ComputeCapability: fields[3],
+		}
+	}
+
+	return gpus
+}
+
+// pciBusId normalizes a pci address, nvidia-smi using an 8 digit pci domain
+func pciBusId(address string) string {
+	address = strings.ToLower(address)
+	if i := strings.Index(address, ":"); i >= 0 && strings.Count(address, ":") == 2 {
+		address = address[i+1:]
+	}
+
+	return address
+}
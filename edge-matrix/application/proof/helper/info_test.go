@@ -217,3 +217,30 @@ func TestCPUPercentIntervalZero(t *testing.T) {
 func TestCPUPercentIntervalZeroPerCPU(t *testing.T) {
 	testCPUPercentLastUsed(t, true)
 }
+
+func TestParseNvidiaSmi(t *testing.T) {
+	gpus := parseNvidiaSmi("00000000:01:00.0, 24564, 535.104.05, 8.9\n00000000:02:00.0, 81920, 535.104.05, 8.0\n")
+
+	assert.Len(t, gpus, 2)
+	assert.Equal(t, GpuProfile{Vram: 24564, Driver: "nvidia 535.104.05", ComputeCapability: "8.9"}, gpus[pciBusId("0000:01:00.0")])
+	assert.Equal(t, uint64(81920), gpus["02:00.0"].Vram)
+}
+
+func TestHardwareProfile(t *testing.T) {
+	profile := &HardwareProfile{
+		Gpus: []GpuProfile{
+			{Vendor: "NVIDIA Corporation", Model: "GA102 [GeForce RTX 3090]", Vram: 24576},
+			{Vendor: "NVIDIA Corporation", Model: "GA100 [A100 PCIe 80GB]", Vram: 81920},
+		},
+	}
+
+	assert.Equal(t, uint64(81920), profile.MaxVram())
+	assert.True(t, profile.HasGpu("rtx 3090"))
+	assert.True(t, profile.HasGpu("nvidia"))
+	assert.False(t, profile.HasGpu("radeon"))
+
+	// the usage refresh does not change the profile it is called on
+	refreshed := profile.WithUsage(t.TempDir())
+	assert.Equal(t, profile.Gpus, refreshed.Gpus)
+	assert.Zero(t, profile.DiskTotal)
+}
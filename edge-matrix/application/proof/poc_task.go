@@ -2,17 +2,27 @@ package proof
 
 import "time"
 
+const (
+	// PocKindHash is the cpu challenge, hash proofs of a seed
+	PocKindHash = "hash"
+	// PocKindMatMul is the gpu challenge, see MatMulChallenge
+	PocKindMatMul = "matmul"
+)
+
 type PocCpuRequest struct {
 	NodeId   string
 	Seed     string
 	BlockNum uint64
 	Start    time.Time
+	// challenge kind, PocKindHash or PocKindMatMul
+	Kind string
 }
 
 type PocCpuData struct {
 	Validator string
 	Seed      string
 	ModelName string
+	Kind      string
 }
 
 type PocSubmitTask struct {
@@ -30,6 +40,7 @@ type PocSubmitData struct {
 	Validator        string
 	Power            int64
 	TargetNodeID     string
+	Kind             string
 }
 
 type PocTask struct {
@@ -0,0 +1,81 @@
+package application
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/helper/hex"
+	"github.com/emc-protocol/edge-matrix/helper/rpc"
+	"github.com/emc-protocol/edge-matrix/state/runtime/precompiled"
+	"github.com/emc-protocol/edge-matrix/types"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+var ErrNoPeerKey = errors.New("no private key for the endpoint peer")
+
+// TelegramSender sends telegrams to the chain
+type TelegramSender interface {
+	GetNextNonce(address string) (uint64, error)
+	SendRawTelegram(to types.Address, nonce uint64, input string, privateKey *ecdsa.PrivateKey) (*rpc.TelegramResponse, error)
+}
+
+// NewProviderRegistration returns the registration binding the peer of key to the provider on the chain,
+// the input of a telegram sent by the provider to the provider registry precompile. The sequence has
+// to be above the one of the registration of the peer in place.
+func NewProviderRegistration(
+	key libp2pCrypto.PrivKey,
+	chainID uint64,
+	provider types.Address,
+	sequence uint64,
+) (*precompiled.ProviderRegistration, error) {
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := precompiled.ProviderRegistrationHash(chainID, id.String(), provider, sequence)
+
+	sig, err := key.Sign(hash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &precompiled.ProviderRegistration{
+		PeerId:    id.String(),
+		Sequence:  sequence,
+		Signature: hex.EncodeToHex(sig),
+	}, nil
+}
+
+// RegisterProvider registers the endpoint address as the provider of its peer on the chain,
+// so that the edge calls it answers pass the attestation check of the chain.
+// The registration time is its sequence, each registration superseding the former ones.
+func (e *Endpoint) RegisterProvider(sender TelegramSender, chainID uint64) error {
+	key := e.h.Peerstore().PrivKey(e.h.ID())
+	if key == nil {
+		return ErrNoPeerKey
+	}
+
+	registration, err := NewProviderRegistration(key, chainID, e.address, uint64(time.Now().UnixNano()))
+	if err != nil {
+		return err
+	}
+
+	input, err := json.Marshal(registration)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := sender.GetNextNonce(e.address.String())
+	if err != nil {
+		return err
+	}
+
+	_, err = sender.SendRawTelegram(contracts.EdgeProviderRegistryPrecompile, nonce, string(input), e.privateKey)
+
+	return err
+}
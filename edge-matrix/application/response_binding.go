@@ -0,0 +1,162 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/helper/hex"
+	"github.com/emc-protocol/edge-matrix/state/runtime/precompiled"
+	"github.com/emc-protocol/edge-matrix/types"
+)
+
+const (
+	// headers the router sends along with an edge call
+	HeaderEmcFrom   = "Emc-From"
+	HeaderEmcNonce  = "Emc-Nonce"
+	HeaderEmcRouter = "Emc-Router"
+)
+
+const (
+	// DefaultResponseMaxAge is the max difference between the timestamp
+	// of a bound edge response and the local time of the router
+	DefaultResponseMaxAge = 5 * time.Minute
+)
+
+var (
+	ErrResponseNotBound         = errors.New("edge response is not bound to a request")
+	ErrResponseBindingMismatch  = errors.New("edge response is bound to another request")
+	ErrResponseExpired          = errors.New("edge response timestamp out of range")
+	ErrResponseHashMismatch     = errors.New("edge response hash mismatch")
+	ErrResponseProviderMismatch = errors.New("edge response provider mismatch")
+)
+
+// RequestBinding identifies the request an edge response answers
+type RequestBinding struct {
+	RequestHash types.Hash
+	Caller      types.Address
+	Nonce       uint64
+	ProviderID  string
+}
+
+// NewRequestBinding returns the binding of the edge call sent by caller with the telegram nonce
+func NewRequestBinding(call *EdgeCall, caller types.Address, nonce uint64) *RequestBinding {
+	return &RequestBinding{
+		RequestHash: call.Hash(),
+		Caller:      caller,
+		Nonce:       nonce,
+		ProviderID:  call.PeerId,
+	}
+}
+
+// requestBindingFromHttp returns the binding of a request received by the endpoint,
+// or nil if the request was not sent by a router
+func requestBindingFromHttp(r *http.Request, body []byte, providerID string) *RequestBinding {
+	from := r.Header.Get(HeaderEmcFrom)
+	if from == "" {
+		return nil
+	}
+
+	caller := types.Address{}
+	if err := caller.UnmarshalText([]byte(from)); err != nil {
+		return nil
+	}
+
+	nonce, err := strconv.ParseUint(r.Header.Get(HeaderEmcNonce), 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	call := &EdgeCall{
+		PeerId:   providerID,
+		Endpoint: r.URL.Path,
+		Input:    body,
+	}
+
+	// streamed requests carry the stream flag the router added to the call of their
+	// telegram, they are bound to this call, as the EdgeCall precompile checks it
+	if teleCall := telegramCall(r); teleCall != nil && teleCall.Hash() != call.Hash() {
+		if streamCall, err := NewStreamCall(teleCall); err == nil && streamCall.Hash() == call.Hash() {
+			call.Input = teleCall.Input
+		}
+	}
+
+	return NewRequestBinding(call, caller, nonce)
+}
+
+// telegramCall returns the edge call of the telegram forwarded by the router, nil if it has none
+func telegramCall(r *http.Request) *EdgeCall {
+	buf, err := hex.DecodeHex(r.Header.Get(HeaderEmcTelegram))
+	if err != nil || len(buf) == 0 {
+		return nil
+	}
+
+	tele := &types.Telegram{}
+	if err := tele.UnmarshalRLP(buf); err != nil {
+		return nil
+	}
+
+	call := &EdgeCall{}
+	if err := json.Unmarshal(tele.Input, call); err != nil {
+		return nil
+	}
+
+	return call
+}
+
+// bind commits the edge response to the request
+func (b *RequestBinding) bind(resp *EdgeResponse, timestamp uint64) {
+	resp.RequestHash = b.RequestHash
+	resp.Caller = b.Caller
+	resp.Nonce = b.Nonce
+	resp.ProviderID = b.ProviderID
+	resp.Timestamp = timestamp
+}
+
+// Hash returns the request hash of the edge call (keccak256 hash of the endpoint and the compacted input)
+func (e *EdgeCall) Hash() types.Hash {
+	return precompiled.EdgeCallRequestHash(e.Endpoint, e.Input)
+}
+
+// VerifyResponseBinding checks that the signed edge response answers the bound request,
+// and that its timestamp is within maxAge of now
+func VerifyResponseBinding(
+	signer Signer,
+	resp *EdgeResponse,
+	binding *RequestBinding,
+	maxAge time.Duration,
+	now time.Time,
+) error {
+	if !resp.IsBound() {
+		return ErrResponseNotBound
+	}
+
+	if resp.RequestHash != binding.RequestHash ||
+		resp.Caller != binding.Caller ||
+		resp.Nonce != binding.Nonce ||
+		resp.ProviderID != binding.ProviderID {
+		return ErrResponseBindingMismatch
+	}
+
+	age := now.Sub(time.Unix(int64(resp.Timestamp), 0))
+	if age > maxAge || age < -maxAge {
+		return ErrResponseExpired
+	}
+
+	if resp.Hash != signer.Hash(resp) {
+		return ErrResponseHashMismatch
+	}
+
+	provider, err := signer.Provider(resp)
+	if err != nil {
+		return err
+	}
+
+	if provider != resp.From {
+		return ErrResponseProviderMismatch
+	}
+
+	return nil
+}
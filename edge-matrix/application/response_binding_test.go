@@ -0,0 +1,152 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/chain"
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/crypto"
+	"github.com/emc-protocol/edge-matrix/helper/hex"
+	"github.com/emc-protocol/edge-matrix/state/runtime/precompiled"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestBindingFromHttp(t *testing.T) {
+	t.Parallel()
+
+	caller := types.StringToAddress("0x68b95f67a32935e3ed85600F558b74E0d2747120")
+	call := &EdgeCall{
+		PeerId:   "16Uiu2HAm",
+		Endpoint: "/api",
+		Input:    json.RawMessage(`{ "path": "/v1/chat" }`),
+	}
+
+	raw, err := json.Marshal(call.Input)
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest("POST", "/api", strings.NewReader(string(raw)))
+	r.Header.Set(HeaderEmcFrom, caller.String())
+	r.Header.Set(HeaderEmcNonce, "7")
+
+	// the binding received by the provider matches the one of the router
+	assert.Equal(t, NewRequestBinding(call, caller, 7), requestBindingFromHttp(r, raw, call.PeerId))
+
+	r.Header.Del(HeaderEmcFrom)
+	assert.Nil(t, requestBindingFromHttp(r, raw, call.PeerId))
+}
+
+func TestRequestBindingFromHttp_Stream(t *testing.T) {
+	t.Parallel()
+
+	caller := types.StringToAddress("0x1")
+	call := &EdgeCall{PeerId: "16Uiu2HAm", Endpoint: "/api", Input: json.RawMessage(`{"path":"/v1/chat"}`)}
+
+	input, err := json.Marshal(call)
+	assert.NoError(t, err)
+
+	to := contracts.EdgeCallPrecompile
+	tele := &types.Telegram{Nonce: 7, To: &to, Input: input, GasPrice: big.NewInt(0), Value: big.NewInt(0)}
+
+	streamCall, err := NewStreamCall(call)
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest("POST", "/api", bytes.NewReader(streamCall.Input))
+	r.Header.Set(HeaderEmcFrom, caller.String())
+	r.Header.Set(HeaderEmcNonce, "7")
+	r.Header.Set(HeaderEmcTelegram, hex.EncodeToHex(tele.MarshalRLP()))
+
+	// the streamed request is bound to the call of its telegram, without the stream flag
+	binding := requestBindingFromHttp(r, streamCall.Input, call.PeerId)
+	assert.Equal(t, NewRequestBinding(call, caller, 7), binding)
+	assert.NotEqual(t, streamCall.Hash(), binding.RequestHash)
+
+	// other requests keep their own hash
+	other := &EdgeCall{PeerId: call.PeerId, Endpoint: "/api", Input: json.RawMessage(`{"path":"/v1/other","stream":true}`)}
+	assert.Equal(t, other.Hash(), requestBindingFromHttp(r, other.Input, call.PeerId).RequestHash)
+}
+
+func TestVerifyResponseBinding(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	signer := NewEIP155Signer(chain.AllForksEnabled.At(0), 2)
+	binding := NewRequestBinding(
+		&EdgeCall{PeerId: "16Uiu2HAm", Endpoint: "/api", Input: json.RawMessage(`{}`)},
+		types.StringToAddress("0x1"),
+		3,
+	)
+	now := time.Now()
+
+	sign := func(binding *RequestBinding, timestamp time.Time) *EdgeResponse {
+		resp := &EdgeResponse{RespString: "b2s="}
+		if binding != nil {
+			binding.bind(resp, uint64(timestamp.Unix()))
+		}
+
+		signedResp, err := signer.SignEdgeResp(resp, key)
+		assert.NoError(t, err)
+
+		signedResp.From = crypto.PubKeyToAddress(&key.PublicKey)
+		signedResp.Hash = signer.Hash(resp)
+
+		// responses are verified after going through the wire
+		decoded := &EdgeResponse{}
+		assert.NoError(t, decoded.UnmarshalRLP(signedResp.MarshalRLP()))
+
+		return decoded
+	}
+
+	otherBinding := *binding
+	otherBinding.Nonce = 4
+
+	assert.NoError(t, VerifyResponseBinding(signer, sign(binding, now), binding, DefaultResponseMaxAge, now))
+	assert.ErrorIs(t, VerifyResponseBinding(signer, sign(nil, now), binding, DefaultResponseMaxAge, now), ErrResponseNotBound)
+	assert.ErrorIs(t, VerifyResponseBinding(signer, sign(&otherBinding, now), binding, DefaultResponseMaxAge, now), ErrResponseBindingMismatch)
+	assert.ErrorIs(t, VerifyResponseBinding(signer, sign(binding, now.Add(-time.Hour)), binding, DefaultResponseMaxAge, now), ErrResponseExpired)
+
+	// a response replayed with a rewritten binding no longer matches its signature
+	replayed := sign(&otherBinding, now)
+	replayed.Nonce = binding.Nonce
+	assert.ErrorIs(t, VerifyResponseBinding(signer, replayed, binding, DefaultResponseMaxAge, now), ErrResponseHashMismatch)
+
+	replayed.Hash = signer.Hash(replayed)
+	assert.Error(t, VerifyResponseBinding(signer, replayed, binding, DefaultResponseMaxAge, now))
+}
+
+func TestResponseBindingHash(t *testing.T) {
+	t.Parallel()
+
+	caller := types.StringToAddress("0x1")
+	call := &EdgeCall{PeerId: "16Uiu2HAm", Endpoint: "/api", Input: json.RawMessage(`{ "prompt": "hi" }`)}
+
+	resp := &EdgeResponse{RespString: "b2s=", StatusCode: 200, Headers: []string{"Content-Type: application/json"}}
+	NewRequestBinding(call, caller, 3).bind(resp, 1700000000)
+
+	// the EdgeCall precompile recomputes the signed hash from the telegram and its call
+	signer := NewEIP155Signer(chain.AllForksEnabled.At(0), 2)
+	binding := &precompiled.EdgeCallBinding{
+		RespDigest:  resp.Binding().RespDigest,
+		StatusCode:  200,
+		RequestHash: precompiled.EdgeCallRequestHash("/api", []byte(`{"prompt":"hi"}`)),
+		Caller:      caller,
+		Nonce:       3,
+		ProviderID:  call.PeerId,
+		Timestamp:   1700000000,
+	}
+	assert.Equal(t, signer.Hash(resp), binding.Hash(2))
+
+	// the body is committed by the digest
+	other := resp.Copy()
+	other.RespString = "bm8="
+	assert.NotEqual(t, resp.Binding().RespDigest, other.Binding().RespDigest)
+	assert.NotEqual(t, signer.Hash(resp), signer.Hash(other))
+}
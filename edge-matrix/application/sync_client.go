@@ -40,6 +40,7 @@ type syncAppPeerClient struct {
 	id                     string                // node id
 	peerStatusUpdateCh     chan *AppPeer         // peer status update channel
 	peerConnectionUpdateCh chan *event.PeerEvent // peer connection update channel
+	statusVerifier         *AppStatusVerifier    // verifies the gossiped app statuses
 
 	endpoint *Endpoint
 
@@ -120,6 +121,7 @@ func (m *syncAppPeerClient) GetPeerStatus(peerID peer.ID) (*AppPeer, error) {
 
 	return &AppPeer{
 		ID:           peerID.String(),
+		Name:         status.Name,
 		Starup_time:  status.StartupTime,
 		Uptime:       status.Uptime,
 		Guage_height: status.GuageHeight,
@@ -150,6 +152,8 @@ func (m *syncAppPeerClient) GetConnectedPeerStatuses() []*AppPeer {
 			status, err := m.GetPeerStatus(peerID)
 			if err != nil {
 				m.logger.Warn("failed to get status from a peer, skip", "id", peerID, "err", err)
+
+				return
 			}
 
 			syncPeersLock.Lock()
@@ -220,14 +224,28 @@ func (m *syncAppPeerClient) handleGossipAppStatusUpdate(obj interface{}, from pe
 
 	m.logger.Debug("handleGossipAppStatusUpdate", "from", from.String(), "ID", status.NodeId, "Name", status.Name, "Addr", status.Addr, "Relay", status.Relay)
 
+	if err := m.statusVerifier.Verify(status, time.Now()); err != nil {
+		m.logger.Warn("rejected app status", "from", from.String(), "ID", status.NodeId, "Name", status.Name, "err", err)
+
+		// the publishing relay is expected to verify the statuses it publishes,
+		// replayed or expired ones are just late gossip and only dropped
+		if errors.Is(err, ErrAppStatusSignature) && from != m.network.AddrInfo().ID {
+			m.network.DisconnectFromPeer(from, err.Error())
+		}
+
+		return
+	}
+
 	peerId, err := peer.Decode(status.NodeId)
 	if err != nil {
 		return
 	}
 
+	addr := m.verifiedAddr(peerId, status.Addr)
+
 	ip_addr := ""
-	if status.Addr != "" {
-		ip_addr, _ = m.getMaskedIp(status.Addr)
+	if addr != "" {
+		ip_addr, _ = m.getMaskedIp(addr)
 	}
 
 	event := &Event{}
@@ -247,12 +265,14 @@ func (m *syncAppPeerClient) handleGossipAppStatusUpdate(obj interface{}, from pe
 		ModelHash:    status.ModelHash,
 		AveragePower: status.AveragePower,
 		Version:      status.Version,
+		PubKey:       status.PubKey,
+		LastSeen:     time.Now(),
+		Hardware:     HardwareFromProto(status.Hardware),
+		Price:        DecodePrice(status.Price),
 	}
 	event.AddNewApp(app)
 	m.stream.push(event) // push to jsonRpc
 
-	// TODO validate AppStatus
-
 	// push appstatus to syncer
 	m.peerStatusUpdateCh <- &AppPeer{
 		ID:           status.NodeId,
@@ -263,7 +283,7 @@ func (m *syncAppPeerClient) handleGossipAppStatusUpdate(obj interface{}, from pe
 		Guage_max:    status.GuageMax,
 		Distance:     m.network.GetPeerDistance(from),
 		Relay:        status.Relay,
-		Addr:         status.Addr,
+		Addr:         addr,
 		AppOrigin:    status.AppOrigin,
 		Mac:          status.Mac,
 		CpuInfo:      status.CpuInfo,
@@ -272,7 +292,25 @@ func (m *syncAppPeerClient) handleGossipAppStatusUpdate(obj interface{}, from pe
 		ModelHash:    status.ModelHash,
 		AveragePower: status.AveragePower,
 		Version:      status.Version,
+		PubKey:       status.PubKey,
+		Hardware:     HardwareFromProto(status.Hardware),
+		Price:        DecodePrice(status.Price),
+	}
+}
+
+// verifiedAddr returns the address of the connection to the app peer, whose identity is verified
+// by the connection handshake. The addr of its app status is observed by the relay and not signed
+// by the node, it is only a hint used while the app peer is not connected.
+func (m *syncAppPeerClient) verifiedAddr(peerId peer.ID, hint string) string {
+	if !m.network.IsConnected(peerId) {
+		return hint
+	}
+
+	if info := m.network.GetPeerInfo(peerId); info != nil && len(info.Addrs) > 0 {
+		return info.Addrs[0].String()
 	}
+
+	return hint
 }
 
 func (m *syncAppPeerClient) PublishApplicationStatus(status *proto.AppStatus) {
@@ -285,6 +323,25 @@ func (m *syncAppPeerClient) PublishApplicationStatus(status *proto.AppStatus) {
 	}
 }
 
+// PublishAppPeerOffline notifies the app event subscribers that the app peer went offline
+func (m *syncAppPeerClient) PublishAppPeerOffline(appPeer *AppPeer) {
+	peerId, err := peer.Decode(appPeer.ID)
+	if err != nil {
+		return
+	}
+
+	event := &Event{}
+	event.AddNewApp(&Application{
+		Name:      appPeer.Name,
+		PeerID:    peerId,
+		AppOrigin: appPeer.AppOrigin,
+		Version:   appPeer.Version,
+		Offline:   true,
+		LastSeen:  appPeer.LastSeen,
+	})
+	m.stream.push(event) // push to jsonRpc
+}
+
 // startPeerEventProcess starts subscribing peer connection change events and process them
 func (m *syncAppPeerClient) startPeerEventProcess() {
 	defer close(m.peerConnectionUpdateCh)
@@ -345,22 +402,20 @@ func (m *syncAppPeerClient) PostPeerStatusData(peerID peer.ID, nodeId string) (s
 	return recv.Data, nil
 }
 
-// GetPeerData returns bytes of data from given hash to peer
+// GetPeerData returns the hash proofs of the data hash computed by the peer, within timeout
 func (m *syncAppPeerClient) GetPeerData(peerID peer.ID, hash string, timeout time.Duration) (map[string][]byte, error) {
 	clt, err := m.newSyncPeerClient(peerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create sync peer client: %w", err)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	// TODO handle timeout
 	data, err := clt.GetData(ctx, &proto.GetDataRequest{
 		DataHash: hash,
 	})
 	if err != nil {
-		cancel()
-
 		return nil, fmt.Errorf("failed to open GetData stream: %w", err)
 	}
 	recv, err := data.Recv()
@@ -442,6 +497,8 @@ type SyncAppPeerClient interface {
 	EnablePublishingPeerStatus()
 	// PublishApplicationStatus publish application status
 	PublishApplicationStatus(status *proto.AppStatus)
+	// PublishAppPeerOffline notifies the app event subscribers that the app peer went offline
+	PublishAppPeerOffline(appPeer *AppPeer)
 	// SubscribeAppEvents returns a application event subscription
 	SubscribeAppEvents() Subscription
 }
@@ -459,6 +516,7 @@ func NewSyncAppPeerClient(
 		id:                     network.AddrInfo().ID.String(),
 		peerStatusUpdateCh:     make(chan *AppPeer, 1),
 		peerConnectionUpdateCh: make(chan *event.PeerEvent, 1),
+		statusVerifier:         NewAppStatusVerifier(),
 		shouldEmitData:         true,
 		stream:                 &eventStream{},
 		closeCh:                make(chan struct{}),
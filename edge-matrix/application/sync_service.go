@@ -90,7 +90,7 @@ func (s *syncAppService) GetData(
 	loops := proof.DefaultHashProofCount
 	i := 0
 	for i < loops {
-		seed := fmt.Sprintf("%s,%d", req.GetDataHash(), i)
+		seed := proof.HashProofSeed(req.GetDataHash(), i)
 		_, bytes, err := proof.ProofByCalcHash(seed, target, time.Second*5)
 		if err != nil {
 			break
@@ -113,6 +113,10 @@ func (s *syncAppService) GetStatus(
 	req *empty.Empty,
 ) (*proto.AppStatus, error) {
 	application := s.applicationStore.GetEndpointApplication()
+	if application == nil {
+		return nil, ErrAppNotFound
+	}
+
 	return &proto.AppStatus{
 		Name:        application.Name,
 		StartupTime: application.StartupTime,
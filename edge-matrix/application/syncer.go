@@ -1,17 +1,23 @@
 package application
 
 import (
+	"github.com/emc-protocol/edge-matrix/network/event"
 	"github.com/emc-protocol/edge-matrix/types"
 	"github.com/emc-protocol/edge-matrix/validators"
 	"github.com/hashicorp/go-hclog"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"sync"
 	"time"
 )
 
 const (
 	appSyncerProto = "/appsyncer/0.1"
 	syncerName     = "appsyncer"
+
+	// DefaultAppPeerTTL is how long an app peer stays routable after its last app status,
+	// app peers publish their status every DefaultAppStatusSyncDuration
+	DefaultAppPeerTTL = 4 * DefaultAppStatusSyncDuration
 )
 
 type blockchainStore interface {
@@ -46,11 +52,23 @@ type syncer struct {
 
 	// Channel to notify Sync that a new status arrived
 	newStatusCh chan struct{}
+	closeCh     chan struct{}
+
+	// how long an app peer stays routable after its last app status
+	peerTTL time.Duration
 
 	blockchainStore blockchainStore
 	host            host.Host
 
 	peersBlockNumMap map[peer.ID]uint64
+
+	// snapshot of the peer map, nil if not persisted
+	store *peerStore
+	// serializes the updates of app peers from app statuses and refreshes
+	peersLock sync.Mutex
+
+	// proof of compute challenge history of the nodes, nil if not challenging
+	pocHistory *PocHistory
 }
 
 type ValidatorStore interface {
@@ -68,6 +86,16 @@ type Syncer interface {
 	Close() error
 	// GetAppPeer get AppPeer by PeerID
 	GetAppPeer(id string) *AppPeer
+	// GetApp get the AppPeer of the app served by PeerID, any of its AppPeers if app is empty
+	GetApp(id string, app string) *AppPeer
+	// BestAppPeer returns the best AppPeer matching the target according to the policy
+	BestAppPeer(skipMap map[string]bool, target *EdgeCallTarget, policy PeerSelectionPolicy) *AppPeer
+	// GetAppPeers returns all the known AppPeers
+	GetAppPeers() []*AppPeer
+	// SetPeerStore persists the AppPeers at path, reloading the ones of the last run
+	SetPeerStore(path string) error
+	// SetPocHistory routes to the nodes failing their proof of compute challenges last
+	SetPocHistory(history *PocHistory)
 }
 
 func NewSyncer(
@@ -82,6 +110,8 @@ func NewSyncer(
 		syncAppPeerClient:  syncAppPeerClient,
 		syncAppPeerService: syncAppPeerService,
 		newStatusCh:        make(chan struct{}),
+		closeCh:            make(chan struct{}),
+		peerTTL:            DefaultAppPeerTTL,
 		peerMap:            new(PeerMap),
 		host:               host,
 		blockchainStore:    blockchainStore,
@@ -89,15 +119,104 @@ func NewSyncer(
 	}
 }
 
-// initializePeerMap fetches peer statuses and initializes map
+// SetPeerStore persists the peer map at path, in memory if path is empty.
+// It must be set before the syncer starts.
+func (s *syncer) SetPeerStore(path string) error {
+	store, err := newPeerStore(path)
+	if err != nil {
+		return err
+	}
+
+	s.store = store
+
+	return nil
+}
+
+// initializePeerMap reloads the app peers of the last snapshot as unverified, so that
+// edge calls can be routed at once, and refreshes them from their nodes in the background
 func (s *syncer) initializePeerMap() {
-	peerStatuses := s.syncAppPeerClient.GetConnectedPeerStatuses()
-	s.peerMap.Put(peerStatuses...)
+	if s.store == nil {
+		return
+	}
+
+	peers, err := s.store.Load()
+	if err != nil {
+		s.logger.Warn("failed to reload app peers", "err", err)
+
+		return
+	}
+
+	now := time.Now()
+	nodes := make(map[string]bool)
+
+	for _, appPeer := range peers {
+		// the reloaded peers have a ttl to be refreshed or gossiped again
+		appPeer.Unverified = true
+		appPeer.LastSeen = now
+		nodes[appPeer.ID] = true
+	}
+
+	s.peerMap.Put(peers...)
+	s.logger.Info("app peers reloaded", "count", len(peers))
+
+	for id := range nodes {
+		go s.refreshPeer(id)
+	}
+}
+
+// refreshPeer fetches the status of the node of unverified app peers, the app peers
+// of the nodes that can not be reached are left to gossip or expiry
+func (s *syncer) refreshPeer(id string) {
+	peerID, err := peer.Decode(id)
+	if err != nil {
+		return
+	}
+
+	status, err := s.syncAppPeerClient.GetPeerStatus(peerID)
+	if err != nil {
+		s.logger.Debug("failed to refresh app peer", "id", id, "err", err)
+
+		return
+	}
+
+	s.peersLock.Lock()
+	defer s.peersLock.Unlock()
+
+	for _, appPeer := range s.peerMap.List() {
+		if appPeer.ID != id || !appPeer.Unverified {
+			continue
+		}
+
+		refreshed := *appPeer
+		refreshed.Unverified = false
+		refreshed.LastSeen = time.Now()
+
+		if appPeer.Name == status.Name {
+			refreshed.Starup_time = status.Starup_time
+			refreshed.Uptime = status.Uptime
+			refreshed.Guage_height = status.Guage_height
+			refreshed.Guage_max = status.Guage_max
+		}
+
+		s.peerMap.Put(&refreshed)
+	}
+}
+
+// snapshotPeerMap persists the peer map, if a peer store is set
+func (s *syncer) snapshotPeerMap() {
+	if s.store == nil {
+		return
+	}
+
+	if err := s.store.Snapshot(s.peerMap.List()); err != nil {
+		s.logger.Warn("failed to persist app peers", "err", err)
+	}
 }
 
 // Close terminates goroutine processes
 func (s *syncer) Close() error {
 	close(s.newStatusCh)
+	close(s.closeCh)
 
 	if err := s.syncAppPeerService.Close(); err != nil {
 		return err
@@ -105,6 +224,12 @@ func (s *syncer) Close() error {
 
 	s.syncAppPeerClient.Close()
 
+	if s.store != nil {
+		s.snapshotPeerMap()
+
+		return s.store.Close()
+	}
+
 	return nil
 }
 
@@ -115,8 +240,11 @@ func (s *syncer) Start(topicSubFlag bool) error {
 
 	s.syncAppPeerService.Start()
 
+	s.initializePeerMap()
+
 	go s.startPeerStatusUpdateProcess()
-	//go s.startPeerConnectionEventProcess()
+	go s.startPeerConnectionEventProcess()
+	go s.startPeerExpiryProcess()
 
 	return nil
 
@@ -133,8 +261,45 @@ func (s *syncer) startPeerStatusUpdateProcess() {
 	}
 }
 
+// startPeerConnectionEventProcess evicts the app peers of the disconnected peers
+func (s *syncer) startPeerConnectionEventProcess() {
+	for e := range s.syncAppPeerClient.GetPeerConnectionUpdateEventCh() {
+		if e.Type == event.PeerDisconnected {
+			s.removeFromPeerMap(e.PeerID)
+		}
+	}
+}
+
+// startPeerExpiryProcess periodically evicts the app peers whose last app status is older than the ttl
+func (s *syncer) startPeerExpiryProcess() {
+	ticker := time.NewTicker(DefaultAppStatusSyncDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case now := <-ticker.C:
+			s.expirePeers(now)
+			s.snapshotPeerMap()
+		}
+	}
+}
+
+// expirePeers evicts the app peers not seen since the ttl
+func (s *syncer) expirePeers(now time.Time) {
+	for _, appPeer := range s.peerMap.Expire(now.Add(-s.peerTTL)) {
+		s.logger.Info("app peer expired", "id", appPeer.ID, "name", appPeer.Name, "last_seen", appPeer.LastSeen)
+		s.syncAppPeerClient.PublishAppPeerOffline(appPeer)
+	}
+}
+
 // putToPeerMap puts given status to peer map
 func (s *syncer) putToPeerMap(status *AppPeer) {
+	s.peersLock.Lock()
+	defer s.peersLock.Unlock()
+
+	status.LastSeen = time.Now()
 	s.peerMap.Put(status)
 	s.notifyNewStatusEvent()
 }
@@ -144,9 +309,43 @@ func (s *syncer) GetAppPeer(id string) *AppPeer {
 	return s.peerMap.Get(id)
 }
 
-// removeFromPeerMap removes the peer from peer map
+func (s *syncer) GetApp(id string, app string) *AppPeer {
+	return s.peerMap.GetApp(id, app)
+}
+
+func (s *syncer) GetAppPeers() []*AppPeer {
+	return s.peerMap.List()
+}
+
+// SetPocHistory sets the challenge history of the nodes, the nodes failing their
+// challenges are only returned by BestAppPeer if no other app peer matches
+func (s *syncer) SetPocHistory(history *PocHistory) {
+	s.pocHistory = history
+}
+
+func (s *syncer) BestAppPeer(skipMap map[string]bool, target *EdgeCallTarget, policy PeerSelectionPolicy) *AppPeer {
+	if s.pocHistory != nil {
+		failing := s.pocHistory.Failing()
+		if len(failing) > 0 {
+			for id := range skipMap {
+				failing[id] = skipMap[id]
+			}
+
+			if best := s.peerMap.BestPeerWith(failing, target, policy); best != nil {
+				return best
+			}
+		}
+	}
+
+	return s.peerMap.BestPeerWith(skipMap, target, policy)
+}
+
+// removeFromPeerMap removes the app peers of the peer from peer map
 func (s *syncer) removeFromPeerMap(peerID peer.ID) {
-	s.peerMap.Remove(peerID)
+	for _, appPeer := range s.peerMap.Remove(peerID) {
+		s.logger.Info("app peer disconnected", "id", appPeer.ID, "name", appPeer.Name)
+		s.syncAppPeerClient.PublishAppPeerOffline(appPeer)
+	}
 }
 
 // notifyNewStatusEvent emits signal to newStatusCh
@@ -0,0 +1,117 @@
+package application
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+type offlineRecorder struct {
+	SyncAppPeerClient
+
+	offline []*AppPeer
+}
+
+func (c *offlineRecorder) PublishAppPeerOffline(appPeer *AppPeer) {
+	c.offline = append(c.offline, appPeer)
+}
+
+func TestSyncer_EvictsStalePeers(t *testing.T) {
+	t.Parallel()
+
+	client := &offlineRecorder{}
+	s := NewSyncer(hclog.NewNullLogger(), client, nil, nil, nil).(*syncer)
+
+	nodeA, nodeB := peer.ID("A"), peer.ID("B")
+
+	s.putToPeerMap(&AppPeer{ID: nodeA.String(), Name: "sd", Distance: big.NewInt(1)})
+	s.putToPeerMap(&AppPeer{ID: nodeB.String(), Name: "sd", Distance: big.NewInt(2)})
+
+	a := s.GetAppPeer(nodeA.String())
+	assert.False(t, a.LastSeen.IsZero())
+
+	// peers are routable until their ttl elapsed since their last app status
+	s.expirePeers(a.LastSeen.Add(DefaultAppPeerTTL / 2))
+	assert.NotNil(t, s.GetAppPeer(nodeA.String()))
+	assert.Empty(t, client.offline)
+
+	// peers whose last app status is older than the ttl are evicted
+	a.LastSeen = a.LastSeen.Add(-DefaultAppPeerTTL)
+	s.expirePeers(time.Now())
+	assert.Nil(t, s.GetAppPeer(nodeA.String()))
+	assert.NotNil(t, s.GetAppPeer(nodeB.String()))
+	assert.Len(t, client.offline, 1)
+
+	// disconnected peers are evicted at once
+	s.removeFromPeerMap(nodeB)
+	assert.Nil(t, s.GetAppPeer(nodeB.String()))
+	assert.Len(t, client.offline, 2)
+	assert.Equal(t, nodeB.String(), client.offline[1].ID)
+}
+
+type statusClient struct {
+	SyncAppPeerClient
+
+	statuses map[peer.ID]*AppPeer
+}
+
+func (c *statusClient) GetPeerStatus(id peer.ID) (*AppPeer, error) {
+	if status, ok := c.statuses[id]; ok {
+		return status, nil
+	}
+
+	return nil, ErrAppNotFound
+}
+
+func TestSyncer_ReloadsPeers(t *testing.T) {
+	t.Parallel()
+
+	newPeerID := func() peer.ID {
+		key, _, err := libp2pCrypto.GenerateEd25519Key(nil)
+		assert.NoError(t, err)
+
+		id, err := peer.IDFromPrivateKey(key)
+		assert.NoError(t, err)
+
+		return id
+	}
+
+	nodeA, nodeB := newPeerID(), newPeerID()
+	path := t.TempDir()
+
+	store, err := newPeerStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Snapshot([]*AppPeer{
+		{ID: nodeA.String(), Name: "sd", Uptime: 1, Distance: big.NewInt(1)},
+		{ID: nodeB.String(), Name: "sd", Uptime: 1, Distance: big.NewInt(2)},
+	}))
+	assert.NoError(t, store.Close())
+
+	client := &statusClient{statuses: map[peer.ID]*AppPeer{
+		nodeA: {ID: nodeA.String(), Name: "sd", Uptime: 2},
+	}}
+	s := NewSyncer(hclog.NewNullLogger(), client, nil, nil, nil).(*syncer)
+	assert.NoError(t, s.SetPeerStore(path))
+
+	// the peers of the last run are routable at once, until refreshed
+	s.initializePeerMap()
+	assert.Len(t, s.GetAppPeers(), 2)
+
+	// the reachable peers are refreshed, the other ones stay unverified
+	assert.Eventually(t, func() bool {
+		return !s.GetAppPeer(nodeA.String()).Unverified
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint64(2), s.GetAppPeer(nodeA.String()).Uptime)
+	assert.True(t, s.GetAppPeer(nodeB.String()).Unverified)
+
+	s.snapshotPeerMap()
+	peers, err := s.store.Load()
+	assert.NoError(t, err)
+	assert.Len(t, peers, 2)
+	assert.NoError(t, s.store.Close())
+}
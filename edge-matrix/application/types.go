@@ -28,10 +28,14 @@ type Network interface {
 	NewTopic(protoID string, obj proto.Message) (*network.Topic, error)
 	// IsConnected returns the node is connecting to the peer associated with the given ID
 	IsConnected(peerID peer.ID) bool
+	// GetPeerInfo fetches the information of a peer
+	GetPeerInfo(peerID peer.ID) *peer.AddrInfo
 	// SaveProtocolStream saves stream
 	SaveProtocolStream(protocol string, stream *rawGrpc.ClientConn, peerID peer.ID)
 	// CloseProtocolStream closes stream
 	CloseProtocolStream(protocol string, peerID peer.ID) error
+	// DisconnectFromPeer disconnects the peer
+	DisconnectFromPeer(peerID peer.ID, reason string)
 }
 
 type ApplicationStore interface {
@@ -0,0 +1,45 @@
+package blockchain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/emc-protocol/edge-matrix/chain"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockchain_CalculateBaseFee(t *testing.T) {
+	t.Parallel()
+
+	b := &Blockchain{
+		config: &chain.Chain{
+			Params: &chain.Params{
+				Forks: &chain.Forks{EIP1559: chain.NewFork(2)},
+			},
+		},
+	}
+
+	// no base fee before the fork, the initial one in its first block
+	assert.Equal(t, uint64(0), b.CalculateBaseFee(&types.Header{Number: 0}))
+	assert.Equal(t, uint64(chain.InitialBaseFee), b.CalculateBaseFee(&types.Header{Number: 1}))
+
+	parent := &types.Header{Number: 2, GasLimit: 100, BaseFee: 800}
+
+	// the base fee moves by up to 1/8 towards half the gas limit
+	parent.GasUsed = 50
+	assert.Equal(t, uint64(800), b.CalculateBaseFee(parent))
+
+	parent.GasUsed = 100
+	assert.Equal(t, uint64(900), b.CalculateBaseFee(parent))
+
+	parent.GasUsed = 0
+	assert.Equal(t, uint64(700), b.CalculateBaseFee(parent))
+
+	// increases are at least 1 and capped instead of overflowing
+	parent.GasUsed, parent.BaseFee = 51, 1
+	assert.Equal(t, uint64(2), b.CalculateBaseFee(parent))
+
+	parent.GasUsed, parent.BaseFee = 100, math.MaxUint64-1
+	assert.Equal(t, uint64(math.MaxUint64), b.CalculateBaseFee(parent))
+}
@@ -3,6 +3,7 @@ package blockchain
 import (
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"path/filepath"
 	"sync"
@@ -38,6 +39,7 @@ var (
 	ErrInvalidStateRoot     = errors.New("invalid block state root")
 	ErrInvalidGasUsed       = errors.New("invalid block gas used")
 	ErrInvalidReceiptsRoot  = errors.New("invalid block receipts root")
+	ErrInvalidBaseFee       = errors.New("invalid block base fee")
 )
 
 // Blockchain is a blockchain reference
@@ -411,6 +413,51 @@ func (b *Blockchain) calculateGasLimit(parentGasLimit uint64) uint64 {
 	return common.Max(blockGasTarget, common.Max(parentGasLimit-delta, 0))
 }
 
+// CalculateBaseFee calculates the base fee of the child of the given header. The base fee
+// starts at chain.InitialBaseFee in the first block of the EIP1559 fork, and moves by up to
+// 1/chain.BaseFeeChangeDenom per block towards the usage of half the gas limit.
+func (b *Blockchain) CalculateBaseFee(parent *types.Header) uint64 {
+	forks := b.Config().Forks
+	if !forks.IsEIP1559(parent.Number + 1) {
+		return 0
+	}
+
+	if !forks.IsEIP1559(parent.Number) || parent.BaseFee == 0 {
+		return chain.InitialBaseFee
+	}
+
+	gasTarget := parent.GasLimit / chain.ElasticityMultiplier
+	if gasTarget == 0 || parent.GasUsed == gasTarget {
+		return parent.BaseFee
+	}
+
+	if parent.GasUsed > gasTarget {
+		delta := new(big.Int).SetUint64(parent.BaseFee)
+		delta.Mul(delta, new(big.Int).SetUint64(parent.GasUsed-gasTarget))
+		delta.Div(delta, new(big.Int).SetUint64(gasTarget))
+		delta.Div(delta, big.NewInt(chain.BaseFeeChangeDenom))
+
+		if delta.Sign() == 0 {
+			delta.SetUint64(1)
+		}
+
+		// the base fee is capped instead of overflowing
+		baseFee := delta.Add(delta, new(big.Int).SetUint64(parent.BaseFee))
+		if !baseFee.IsUint64() {
+			return math.MaxUint64
+		}
+
+		return baseFee.Uint64()
+	}
+
+	delta := new(big.Int).SetUint64(parent.BaseFee)
+	delta.Mul(delta, new(big.Int).SetUint64(gasTarget-parent.GasUsed))
+	delta.Div(delta, new(big.Int).SetUint64(gasTarget))
+	delta.Div(delta, big.NewInt(chain.BaseFeeChangeDenom))
+
+	return parent.BaseFee - delta.Uint64()
+}
+
 // writeGenesis wrapper for the genesis write function
 func (b *Blockchain) writeGenesis(genesis *chain.Genesis) error {
 	header := genesis.GenesisHeader()
@@ -726,6 +773,7 @@ func (b *Blockchain) verifyBlock(block *types.Block) ([]*types.Receipt, error) {
 // - The hashes match up
 // - The block numbers match up
 // - The block gas limit / used matches up
+// - The block base fee matches up
 func (b *Blockchain) verifyBlockParent(childBlock *types.Block) error {
 	// Grab the parent block
 	parentHash := childBlock.ParentHash()
@@ -768,6 +816,11 @@ func (b *Blockchain) verifyBlockParent(childBlock *types.Block) error {
 		return fmt.Errorf("invalid gas limit, %w", gasLimitErr)
 	}
 
+	// Make sure the base fee follows the gas usage of the parent
+	if baseFee := b.CalculateBaseFee(parent); childBlock.Header.BaseFee != baseFee {
+		return fmt.Errorf("%w, got %d, want %d", ErrInvalidBaseFee, childBlock.Header.BaseFee, baseFee)
+	}
+
 	return nil
 }
 
@@ -1048,9 +1101,11 @@ func (b *Blockchain) updateGasPriceAvgWithBlock(block *types.Block) {
 		return
 	}
 
+	baseFee := new(big.Int).SetUint64(block.Header.BaseFee)
+
 	gasPrices := make([]*big.Int, len(block.Telegrams))
 	for i, transaction := range block.Telegrams {
-		gasPrices[i] = transaction.GasPrice
+		gasPrices[i] = transaction.EffectiveGasPrice(baseFee)
 	}
 
 	b.updateGasPriceAvg(gasPrices)
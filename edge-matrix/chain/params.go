@@ -15,6 +15,15 @@ type Params struct {
 	BlockGasTarget uint64                 `json:"blockGasTarget"`
 }
 
+const (
+	// InitialBaseFee is the base fee of the first block of the EIP1559 fork
+	InitialBaseFee = 1000000000
+	// BaseFeeChangeDenom bounds the change of the base fee between blocks to 1/8
+	BaseFeeChangeDenom = 8
+	// ElasticityMultiplier is the ratio of the block gas limit to the gas target of the base fee
+	ElasticityMultiplier = 2
+)
+
 func (p *Params) GetEngine() string {
 	// We know there is already one
 	for k := range p.Engine {
@@ -40,6 +49,13 @@ type Forks struct {
 	EIP150         *Fork `json:"EIP150,omitempty"`
 	EIP158         *Fork `json:"EIP158,omitempty"`
 	EIP155         *Fork `json:"EIP155,omitempty"`
+	// EIP1559 enables the dynamic fee telegrams and the base fee of the headers.
+	// It is not part of AllForksEnabled, existing chains keeping their header format.
+	EIP1559 *Fork `json:"EIP1559,omitempty"`
+	// EdgeCallAttestation enables the provider registry and the verification and payment
+	// of the edge call attestations by the EdgeCall precompile.
+	// It is not part of AllForksEnabled, existing chains keeping their edge call results.
+	EdgeCallAttestation *Fork `json:"edgeCallAttestation,omitempty"`
 }
 
 func (f *Forks) active(ff *Fork, block uint64) bool {
@@ -82,6 +98,14 @@ func (f *Forks) IsEIP155(block uint64) bool {
 	return f.active(f.EIP155, block)
 }
 
+func (f *Forks) IsEIP1559(block uint64) bool {
+	return f.active(f.EIP1559, block)
+}
+
+func (f *Forks) IsEdgeCallAttestation(block uint64) bool {
+	return f.active(f.EdgeCallAttestation, block)
+}
+
 func (f *Forks) At(block uint64) ForksInTime {
 	return ForksInTime{
 		Homestead:      f.active(f.Homestead, block),
@@ -93,6 +117,9 @@ func (f *Forks) At(block uint64) ForksInTime {
 		EIP150:         f.active(f.EIP150, block),
 		EIP158:         f.active(f.EIP158, block),
 		EIP155:         f.active(f.EIP155, block),
+		EIP1559:        f.active(f.EIP1559, block),
+
+		EdgeCallAttestation: f.active(f.EdgeCallAttestation, block),
 	}
 }
 
@@ -121,7 +148,9 @@ type ForksInTime struct {
 	London,
 	EIP150,
 	EIP158,
-	EIP155 bool
+	EIP155,
+	EIP1559,
+	EdgeCallAttestation bool
 }
 
 var AllForksEnabled = &Forks{
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/emc-protocol/edge-matrix/application"
 	"github.com/emc-protocol/edge-matrix/network"
 	"github.com/hashicorp/hcl"
 	"gopkg.in/yaml.v3"
@@ -39,10 +40,50 @@ type Config struct {
 	RunningMode    string `json:"running_mode,omitempty" yaml:"running_mode,omitempty"`
 	AppUrl         string `json:"app_url,omitempty" yaml:"app_url,omitempty"`
 	AppName        string `json:"app_name,omitempty" yaml:"app_name,omitempty"`
+	// AppHeaderAllowlist are the headers passed between edge call callers and the app
+	AppHeaderAllowlist []string `json:"app_header_allowlist,omitempty" yaml:"app_header_allowlist,omitempty"`
+	// AppMaxConcurrency is the max number of in-flight app requests
+	AppMaxConcurrency uint64 `json:"app_max_concurrency,omitempty" yaml:"app_max_concurrency,omitempty"`
+	// AppMaxQueue is the max number of app requests waiting for a free slot
+	AppMaxQueue uint64 `json:"app_max_queue,omitempty" yaml:"app_max_queue,omitempty"`
+	// AppAccess restricts the callers served by the app
+	AppAccess *AppAccess `json:"app_access,omitempty" yaml:"app_access,omitempty"`
+	// AppValidateResp validates the app responses against the app idl
+	AppValidateResp bool `json:"app_validate_responses,omitempty" yaml:"app_validate_responses,omitempty"`
+	// Apps are the apps served by the node, app_name and app_url configure a single app otherwise
+	Apps []*App `json:"apps,omitempty" yaml:"apps,omitempty"`
 	//AppOrigin string `json:"app_origin,omitempty" yaml:"app_origin,omitempty"`
 	EmcHost string `json:"emc_host,omitempty" yaml:"emc_host,omitempty"`
 }
 
+// App defines an app served by the node
+type App struct {
+	// name the app is addressed by in edge calls
+	Name string `json:"name" yaml:"name"`
+	Url  string `json:"url" yaml:"url"`
+	// max number of in-flight app requests, app_max_concurrency if 0
+	MaxConcurrency uint64 `json:"max_concurrency,omitempty" yaml:"max_concurrency,omitempty"`
+	// max number of app requests waiting for a free slot, app_max_queue if 0
+	MaxQueue uint64 `json:"max_queue,omitempty" yaml:"max_queue,omitempty"`
+	// price of an edge call to the app, in wei, free if empty
+	Price string `json:"price,omitempty" yaml:"price,omitempty"`
+}
+
+// AppAccess defines the callers served by the app. Once any restriction
+// is set, only callers authenticated by their edge call telegram are served.
+type AppAccess struct {
+	// if not empty, only these caller addresses are served
+	Allowlist []string `json:"allowlist,omitempty" yaml:"allowlist,omitempty"`
+	// caller addresses never served
+	Denylist []string `json:"denylist,omitempty" yaml:"denylist,omitempty"`
+	// requests per second allowed per caller, 0 for unlimited
+	RateLimit float64 `json:"rate_limit,omitempty" yaml:"rate_limit,omitempty"`
+	// max burst of requests per caller above the rate limit
+	RateBurst uint64 `json:"rate_burst,omitempty" yaml:"rate_burst,omitempty"`
+	// requests per UTC day allowed per caller, 0 for unlimited
+	DailyQuota uint64 `json:"daily_quota,omitempty" yaml:"daily_quota,omitempty"`
+}
+
 // Telemetry holds the config details for metric services.
 type Telemetry struct {
 	PrometheusAddr string `json:"prometheus_addr" yaml:"prometheus_addr"`
@@ -63,9 +104,15 @@ type Network struct {
 
 // TelePool defines the TelePool configuration params
 type TelePool struct {
-	PriceLimit         uint64 `json:"price_limit" yaml:"price_limit"`
-	MaxSlots           uint64 `json:"max_slots" yaml:"max_slots"`
-	MaxAccountEnqueued uint64 `json:"max_account_enqueued" yaml:"max_account_enqueued"`
+	PriceLimit          uint64 `json:"price_limit" yaml:"price_limit"`
+	MaxSlots            uint64 `json:"max_slots" yaml:"max_slots"`
+	MaxAccountEnqueued  uint64 `json:"max_account_enqueued" yaml:"max_account_enqueued"`
+	MaxEdgeCallsPerPeer uint64 `json:"max_edge_calls_per_peer" yaml:"max_edge_calls_per_peer"`
+	EdgeCallPolicy      string `json:"edge_call_policy" yaml:"edge_call_policy"`
+	Journal             bool   `json:"journal" yaml:"journal"`
+	Rejournal           uint64 `json:"rejournal_s" yaml:"rejournal_s"`
+	PriceBump           uint64 `json:"price_bump" yaml:"price_bump"`
+	Lifetime            uint64 `json:"lifetime_s" yaml:"lifetime_s"`
 }
 
 // Headers defines the HTTP response headers required to enable CORS.
@@ -123,9 +170,15 @@ func DefaultConfig() *Config {
 		Telemetry:  &Telemetry{},
 		ShouldSeal: true,
 		TelePool: &TelePool{
-			PriceLimit:         0,
-			MaxSlots:           4096,
-			MaxAccountEnqueued: 128,
+			PriceLimit:          0,
+			MaxSlots:            4096,
+			MaxAccountEnqueued:  128,
+			MaxEdgeCallsPerPeer: 16,
+			EdgeCallPolicy:      "least-loaded",
+			Journal:             true,
+			Rejournal:           3600,
+			PriceBump:           10,
+			Lifetime:            3 * 3600,
 		},
 		LogLevel:    "INFO",
 		RestoreFile: "",
@@ -140,6 +193,9 @@ func DefaultConfig() *Config {
 		RelayDiscovery:           false,
 		NumBlockConfirmations:    DefaultNumBlockConfirmations,
 		RunningMode:              DefaultRunningMode,
+		AppMaxConcurrency:        application.DefaultMaxConcurrency,
+		AppMaxQueue:              application.DefaultMaxQueue,
+		AppAccess:                &AppAccess{},
 	}
 }
 
@@ -3,8 +3,10 @@ package server
 import (
 	"errors"
 	"fmt"
+	"github.com/emc-protocol/edge-matrix/application"
 	"github.com/emc-protocol/edge-matrix/chain"
 	"math"
+	"math/big"
 	"net"
 
 	"github.com/emc-protocol/edge-matrix/command/server/config"
@@ -54,12 +56,108 @@ func (p *serverParams) initRawParams() error {
 		return err
 	}
 
+	if err := p.initAppAccess(); err != nil {
+		return err
+	}
+
+	if err := p.initApps(); err != nil {
+		return err
+	}
+
 	p.initPeerLimits()
 	p.initLogFileLocation()
 
 	return p.initAddresses()
 }
 
+func (p *serverParams) initAppAccess() error {
+	rawAccess := p.rawConfig.AppAccess
+	if rawAccess == nil {
+		return nil
+	}
+
+	parseAddresses := func(rawAddrs []string) ([]types.Address, error) {
+		addrs := make([]types.Address, 0, len(rawAddrs))
+
+		for _, rawAddr := range rawAddrs {
+			addr := types.Address{}
+			if err := addr.UnmarshalText([]byte(rawAddr)); err != nil {
+				return nil, fmt.Errorf("invalid app caller address %s, %w", rawAddr, err)
+			}
+
+			addrs = append(addrs, addr)
+		}
+
+		return addrs, nil
+	}
+
+	allowlist, err := parseAddresses(rawAccess.Allowlist)
+	if err != nil {
+		return err
+	}
+
+	denylist, err := parseAddresses(rawAccess.Denylist)
+	if err != nil {
+		return err
+	}
+
+	p.appAccess = &application.AccessConfig{
+		Allowlist:  allowlist,
+		Denylist:   denylist,
+		RateLimit:  rawAccess.RateLimit,
+		RateBurst:  rawAccess.RateBurst,
+		DailyQuota: rawAccess.DailyQuota,
+	}
+
+	return nil
+}
+
+func (p *serverParams) initApps() error {
+	rawApps := p.rawConfig.Apps
+	if len(rawApps) == 0 {
+		rawApps = []*config.App{{Name: p.rawConfig.AppName, Url: p.rawConfig.AppUrl}}
+	}
+
+	p.apps = make([]*application.AppConfig, 0, len(rawApps))
+	names := make(map[string]bool, len(rawApps))
+
+	for _, rawApp := range rawApps {
+		if names[rawApp.Name] {
+			return fmt.Errorf("duplicate app name %s", rawApp.Name)
+		}
+
+		names[rawApp.Name] = true
+
+		app := &application.AppConfig{
+			Name:           rawApp.Name,
+			Url:            rawApp.Url,
+			MaxConcurrency: rawApp.MaxConcurrency,
+			MaxQueue:       rawApp.MaxQueue,
+		}
+
+		if app.MaxConcurrency == 0 {
+			app.MaxConcurrency = p.rawConfig.AppMaxConcurrency
+		}
+
+		if app.MaxQueue == 0 {
+			app.MaxQueue = p.rawConfig.AppMaxQueue
+		}
+
+		if rawApp.Price != "" {
+			price, ok := new(big.Int).SetString(rawApp.Price, 10)
+			if !ok || price.Sign() < 0 {
+				return fmt.Errorf("invalid price %s of app %s", rawApp.Price, rawApp.Name)
+			}
+
+			app.Price = price
+		}
+
+		p.apps = append(p.apps, app)
+	}
+
+	return nil
+}
+
 func (p *serverParams) initBlockTime() error {
 	if p.rawConfig.BlockTime < 1 {
 		return errInvalidBlockTime
@@ -2,6 +2,7 @@ package server
 
 import (
 	"errors"
+	"github.com/emc-protocol/edge-matrix/application"
 	"github.com/emc-protocol/edge-matrix/chain"
 	"net"
 
@@ -32,6 +33,12 @@ const (
 	jsonRPCBlockRangeLimitFlag   = "json-rpc-block-range-limit"
 	maxSlotsFlag                 = "max-slots"
 	maxEnqueuedFlag              = "max-enqueued"
+	maxEdgeCallsFlag             = "max-edge-calls-per-peer"
+	edgeCallPolicyFlag           = "edge-call-policy"
+	teleJournalFlag              = "tele-journal"
+	teleRejournalFlag            = "tele-rejournal"
+	priceBumpFlag                = "price-bump"
+	teleLifetimeFlag             = "tele-lifetime"
 	blockGasTargetFlag           = "block-gas-target"
 	secretsConfigFlag            = "secrets-config"
 	restoreFlag                  = "restore"
@@ -43,11 +50,20 @@ const (
 
 	numBlockConfirmationsFlag = "num-block-confirmations"
 
-	relayOnFlag        = "relay-on"
-	relayDiscoveryFlag = "relay-discovery"
-	runningModeFlag    = "running-mode"
-	appNameFlag        = "app-name"
-	appUrlFlag         = "app-url"
+	relayOnFlag           = "relay-on"
+	relayDiscoveryFlag    = "relay-discovery"
+	runningModeFlag       = "running-mode"
+	appNameFlag           = "app-name"
+	appUrlFlag            = "app-url"
+	appHeaderAllowFlag    = "app-header-allowlist"
+	appMaxConcurrencyFlag = "app-max-concurrency"
+	appMaxQueueFlag       = "app-max-queue"
+	appCallerAllowFlag    = "app-caller-allowlist"
+	appCallerDenyFlag     = "app-caller-denylist"
+	appCallerRateFlag     = "app-caller-rate-limit"
+	appCallerBurstFlag    = "app-caller-rate-burst"
+	appCallerQuotaFlag    = "app-caller-daily-quota"
+	appValidateRespFlag   = "app-validate-responses"
 	//appOriginFlag = "app-origin"
 	icHostFlag = "ic-host"
 )
@@ -68,6 +84,7 @@ var (
 			Telemetry: &config.Telemetry{},
 			Network:   &config.Network{},
 			TelePool:  &config.TelePool{},
+			AppAccess: &config.AppAccess{},
 		},
 	}
 )
@@ -101,6 +118,9 @@ type serverParams struct {
 	secretsConfig *secrets.SecretsManagerConfig
 
 	logFileLocation string
+
+	appAccess *application.AccessConfig
+	apps      []*application.AppConfig
 }
 
 func (p *serverParams) isMaxPeersSet() bool {
@@ -196,22 +216,31 @@ func (p *serverParams) generateConfig() *server.Config {
 		DataDir:   p.rawConfig.DataDir,
 		Seal:      p.rawConfig.ShouldSeal,
 		//PriceLimit:         p.rawConfig.TelePool.PriceLimit,
-		MaxSlots:           p.rawConfig.TelePool.MaxSlots,
-		MaxAccountEnqueued: p.rawConfig.TelePool.MaxAccountEnqueued,
-		SecretsManager:     p.secretsConfig,
-		RestoreFile:        p.getRestoreFilePath(),
-		BlockTime:          p.rawConfig.BlockTime,
-		LogLevel:           hclog.LevelFromString(p.rawConfig.LogLevel),
-		JSONLogFormat:      p.rawConfig.JSONLogFormat,
-		LogFilePath:        p.logFileLocation,
+		MaxSlots:            p.rawConfig.TelePool.MaxSlots,
+		MaxAccountEnqueued:  p.rawConfig.TelePool.MaxAccountEnqueued,
+		MaxEdgeCallsPerPeer: p.rawConfig.TelePool.MaxEdgeCallsPerPeer,
+		EdgeCallPolicy:      p.rawConfig.TelePool.EdgeCallPolicy,
+		TeleJournal:         p.rawConfig.TelePool.Journal,
+		TeleRejournal:       p.rawConfig.TelePool.Rejournal,
+		PriceBump:           p.rawConfig.TelePool.PriceBump,
+		TeleLifetime:        p.rawConfig.TelePool.Lifetime,
+		SecretsManager:      p.secretsConfig,
+		RestoreFile:         p.getRestoreFilePath(),
+		BlockTime:           p.rawConfig.BlockTime,
+		LogLevel:            hclog.LevelFromString(p.rawConfig.LogLevel),
+		JSONLogFormat:       p.rawConfig.JSONLogFormat,
+		LogFilePath:         p.logFileLocation,
 
 		RelayOn:               p.rawConfig.RelayOn,
 		RelayDiscovery:        p.rawConfig.RelayDiscovery,
 		NumBlockConfirmations: p.rawConfig.NumBlockConfirmations,
 
 		RunningMode: p.rawConfig.RunningMode,
-		AppName:     p.rawConfig.AppName,
-		AppUrl:      p.rawConfig.AppUrl,
+		Apps:        p.apps,
+
+		AppHeaderAllowlist: p.rawConfig.AppHeaderAllowlist,
+		AppAccess:          p.appAccess,
+		AppValidateResp:    p.rawConfig.AppValidateResp,
 
 		EmcHost: p.rawConfig.EmcHost,
 	}
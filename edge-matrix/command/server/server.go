@@ -2,6 +2,7 @@ package server
 
 import (
 	"fmt"
+	"github.com/emc-protocol/edge-matrix/application"
 	"github.com/emc-protocol/edge-matrix/command"
 	"github.com/emc-protocol/edge-matrix/command/helper"
 	"github.com/emc-protocol/edge-matrix/command/server/config"
@@ -201,6 +202,69 @@ func setFlags(cmd *cobra.Command) {
 		"the url for application",
 	)
 
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.AppHeaderAllowlist,
+		appHeaderAllowFlag,
+		application.DefaultHeaderAllowlist,
+		"the headers passed between edge call callers and the application",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.AppMaxConcurrency,
+		appMaxConcurrencyFlag,
+		defaultConfig.AppMaxConcurrency,
+		"the max number of in-flight application requests",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.AppMaxQueue,
+		appMaxQueueFlag,
+		defaultConfig.AppMaxQueue,
+		"the max number of application requests waiting for a free slot, requests beyond are rejected as busy",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.AppAccess.Allowlist,
+		appCallerAllowFlag,
+		defaultConfig.AppAccess.Allowlist,
+		"the caller addresses served by the application, any caller if empty",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&params.rawConfig.AppAccess.Denylist,
+		appCallerDenyFlag,
+		defaultConfig.AppAccess.Denylist,
+		"the caller addresses never served by the application",
+	)
+
+	cmd.Flags().Float64Var(
+		&params.rawConfig.AppAccess.RateLimit,
+		appCallerRateFlag,
+		defaultConfig.AppAccess.RateLimit,
+		"the requests per second allowed per caller of the application, 0 for unlimited",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.AppAccess.RateBurst,
+		appCallerBurstFlag,
+		defaultConfig.AppAccess.RateBurst,
+		"the max burst of requests per caller above the rate limit",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.AppAccess.DailyQuota,
+		appCallerQuotaFlag,
+		defaultConfig.AppAccess.DailyQuota,
+		"the requests per UTC day allowed per caller of the application, 0 for unlimited",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.AppValidateResp,
+		appValidateRespFlag,
+		defaultConfig.AppValidateResp,
+		"validate the application responses against the application idl",
+	)
+
 	//cmd.Flags().StringVar(
 	//	&params.rawConfig.AppOrigin,
 	//	appOriginFlag,
@@ -229,6 +293,49 @@ func setFlags(cmd *cobra.Command) {
 		"maximum number of enqueued transactions per account",
 	)
 
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TelePool.MaxEdgeCallsPerPeer,
+		maxEdgeCallsFlag,
+		defaultConfig.TelePool.MaxEdgeCallsPerPeer,
+		"maximum number of in-flight edge calls routed to an app peer",
+	)
+
+	cmd.Flags().StringVar(
+		&params.rawConfig.TelePool.EdgeCallPolicy,
+		edgeCallPolicyFlag,
+		defaultConfig.TelePool.EdgeCallPolicy,
+		"the policy selecting the app peer of edge calls without a peer id "+
+			"(least-loaded, lowest-latency, highest-power)",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.TelePool.Journal,
+		teleJournalFlag,
+		defaultConfig.TelePool.Journal,
+		"journal the locally submitted telegrams in the data dir, replayed at startup",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TelePool.Rejournal,
+		teleRejournalFlag,
+		defaultConfig.TelePool.Rejournal,
+		"interval in seconds of the compaction of the telegram journal",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TelePool.PriceBump,
+		priceBumpFlag,
+		defaultConfig.TelePool.PriceBump,
+		"minimum gas price bump in percent to replace a pending telegram with the same nonce",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.TelePool.Lifetime,
+		teleLifetimeFlag,
+		defaultConfig.TelePool.Lifetime,
+		"maximum time in seconds a telegram stays enqueued (0 to never expire)",
+	)
+
 	cmd.Flags().Uint64Var(
 		&params.rawConfig.BlockTime,
 		blockTimeFlag,
@@ -43,7 +43,7 @@ var (
 )
 
 type txPoolInterface interface {
-	Prepare()
+	Prepare(baseFee uint64)
 	Length() uint64
 	Peek() *types.Telegram
 	Pop(tx *types.Telegram)
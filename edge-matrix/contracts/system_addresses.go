@@ -29,6 +29,8 @@ var (
 	EdgeSubscribeRegisterPrecompile = types.StringToAddress("0x3000")
 	// EdgeCallPrecompile is and address of edge call precompile
 	EdgeCallPrecompile = types.StringToAddress("0x3001")
+	// EdgeProviderRegistryPrecompile is an address of edge provider registry precompile
+	EdgeProviderRegistryPrecompile = types.StringToAddress("0x3002")
 	// EdgeRtcSubjectPrecompile is and address of edge subject precompile
 	EdgeRtcSubjectPrecompile = types.StringToAddress("0x3101")
 )
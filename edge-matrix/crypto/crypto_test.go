@@ -468,3 +468,25 @@ func TestEncrpt(t *testing.T) {
 	t.Log("decText: ", decText)
 	assert.Equal(t, StringToEncrypt, decText)
 }
+
+func TestECIESEncrypt(t *testing.T) {
+	priv, err := GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	msg := []byte("Encrypting this prompt to the provider")
+
+	ciphertext, err := Encrypt(&priv.PublicKey, msg)
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(ciphertext, msg))
+
+	plaintext, err := Decrypt(priv, ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, plaintext)
+
+	// only the owner of the key can decrypt
+	other, err := GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	_, err = Decrypt(other, ciphertext)
+	assert.Error(t, err)
+}
@@ -0,0 +1,36 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+)
+
+// eciesParams are the ECIES parameters used with secp256k1 keys
+var eciesParams = ecies.ECIES_AES128_SHA256
+
+// Encrypt encrypts the message to the secp256k1 public key (ECIES)
+func Encrypt(pub *ecdsa.PublicKey, msg []byte) ([]byte, error) {
+	return ecies.Encrypt(rand.Reader, &ecies.PublicKey{
+		X:      pub.X,
+		Y:      pub.Y,
+		Curve:  S256,
+		Params: eciesParams,
+	}, msg, nil, nil)
+}
+
+// Decrypt decrypts the message encrypted to the public key of the secp256k1 private key (ECIES)
+func Decrypt(priv *ecdsa.PrivateKey, ciphertext []byte) ([]byte, error) {
+	prv := &ecies.PrivateKey{
+		PublicKey: ecies.PublicKey{
+			X:      priv.X,
+			Y:      priv.Y,
+			Curve:  S256,
+			Params: eciesParams,
+		},
+		D: priv.D,
+	}
+
+	return prv.Decrypt(ciphertext, nil, nil)
+}
@@ -46,7 +46,8 @@ type FrontierSigner struct {
 
 var signerPool fastrlp.ArenaPool
 
-// calcTeleHash calculates the transaction hash (keccak256 hash of the RLP value)
+// calcTeleHash calculates the transaction hash (keccak256 hash of the RLP value).
+// The hash of dynamic fee telegrams commits their fee caps, and is prefixed by their type.
 func calcTeleHash(tele *types.Telegram, chainID uint64) types.Hash {
 	a := signerPool.Get()
 
@@ -64,6 +65,11 @@ func calcTeleHash(tele *types.Telegram, chainID uint64) types.Hash {
 	v.Set(a.NewBigInt(tele.Value))
 	v.Set(a.NewCopyBytes(tele.Input))
 
+	if tele.Type == types.DynamicFeeTx {
+		v.Set(a.NewBigInt(tele.GasTipCap))
+		v.Set(a.NewBigInt(tele.GasFeeCap))
+	}
+
 	// EIP155
 	if chainID != 0 {
 		v.Set(a.NewUint(chainID))
@@ -71,7 +77,12 @@ func calcTeleHash(tele *types.Telegram, chainID uint64) types.Hash {
 		v.Set(a.NewUint(0))
 	}
 
-	hash := keccak.Keccak256Rlp(nil, v)
+	var hash []byte
+	if tele.Type == types.DynamicFeeTx {
+		hash = keccak.Keccak256(nil, v.MarshalTo([]byte{byte(tele.Type)}))
+	} else {
+		hash = keccak.Keccak256Rlp(nil, v)
+	}
 
 	signerPool.Put(a)
 
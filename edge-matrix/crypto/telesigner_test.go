@@ -546,6 +546,44 @@ func TestEIP155Signer_TeleCallApi(t *testing.T) {
 	}
 }
 
+func TestEIP155Signer_DynamicFeeTele(t *testing.T) {
+	t.Parallel()
+
+	toAddress := types.StringToAddress("1")
+
+	key, keyGenError := GenerateECDSAKey()
+	if keyGenError != nil {
+		t.Fatalf("Unable to generate key")
+	}
+
+	txn := &types.Telegram{
+		Type:      types.DynamicFeeTx,
+		To:        &toAddress,
+		Value:     big.NewInt(1),
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(10),
+	}
+
+	signer := NewEIP155Signer(chain.AllForksEnabled.At(0), 100)
+
+	signedTx, signErr := signer.SignTele(txn, key)
+	if signErr != nil {
+		t.Fatalf("Unable to sign transaction")
+	}
+
+	recoveredSender, recoverErr := signer.Sender(signedTx)
+	assert.NoError(t, recoverErr)
+	assert.Equal(t, PubKeyToAddress(&key.PublicKey), recoveredSender)
+
+	// the fee caps are part of the signed hash
+	signedTx.GasFeeCap = big.NewInt(11)
+
+	recoveredSender, recoverErr = signer.Sender(signedTx)
+	if recoverErr == nil {
+		assert.NotEqual(t, PubKeyToAddress(&key.PublicKey), recoveredSender)
+	}
+}
+
 func TestEIP155Signer_ChainIDMismatch(t *testing.T) {
 	chainIDS := []uint64{1, 10, 100}
 	toAddress := types.StringToAddress("1")
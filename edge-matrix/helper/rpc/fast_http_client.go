@@ -85,6 +85,43 @@ func (f *FastHttpClient) SendPostJsonRequest(url string, reqEntityBytes []byte)
 	}
 }
 
+// HttpResponse holds the status code, headers and body of a response
+type HttpResponse struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       []byte
+}
+
+// SendRequest sends a request with any method, headers and body and returns the full response
+func (f *FastHttpClient) SendRequest(method string, url string, headers map[string]string, body []byte) (*HttpResponse, error) {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI(url)
+	req.Header.SetMethod(method)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if len(body) > 0 {
+		req.SetBodyRaw(body)
+	}
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	if err := f.client.Do(req, resp); err != nil {
+		return nil, err
+	}
+
+	httpResp := &HttpResponse{
+		StatusCode: resp.StatusCode(),
+		Headers:    make(map[string]string),
+		Body:       append([]byte(nil), resp.Body()...),
+	}
+	resp.Header.VisitAll(func(key, value []byte) {
+		httpResp.Headers[string(key)] = string(value)
+	})
+
+	return httpResp, nil
+}
+
 func HttpConnError(err error) (string, bool) {
 	errName := ""
 	known := false
@@ -8,6 +8,7 @@ import (
 	"github.com/emc-protocol/edge-matrix/helper/hex"
 	"github.com/emc-protocol/edge-matrix/rtc"
 	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/gorilla/websocket"
 	"github.com/libp2p/go-libp2p/core/host"
 	"math"
 	"reflect"
@@ -43,14 +44,16 @@ type endpoints struct {
 // Dispatcher handles all json rpc requests by delegating
 // the execution flow to the corresponding service
 type Dispatcher struct {
-	logger            hclog.Logger
-	serviceMap        map[string]*serviceData
-	filterManager     *FilterManager
-	rtcFilterManager  *RtcFilterManager
-	nodeFilterManager *NodeFilterManager
-	endpoints         endpoints
-	params            *dispatcherParams
-	host              host.Host
+	logger              hclog.Logger
+	serviceMap          map[string]*serviceData
+	filterManager       *FilterManager
+	rtcFilterManager    *RtcFilterManager
+	nodeFilterManager   *NodeFilterManager
+	streamFilterManager *StreamFilterManager
+	jobFilterManager    *JobFilterManager
+	endpoints           endpoints
+	params              *dispatcherParams
+	host                host.Host
 }
 
 type dispatcherParams struct {
@@ -82,6 +85,9 @@ func newDispatcher(
 		d.nodeFilterManager = NewNodeFilterManager(logger, store)
 		go d.nodeFilterManager.Run()
 
+		d.streamFilterManager = NewStreamFilterManager(logger, store)
+		d.jobFilterManager = NewJobFilterManager(logger, store)
+
 		d.host = store.GetHost()
 	}
 
@@ -248,6 +254,35 @@ func (d *Dispatcher) handleEdgeSubscribe(req Request, conn wsConn) (string, Erro
 			return "", NewInternalError(err.Error())
 		}
 		filterID = d.nodeFilterManager.NewNodeFilter(nodeQuery, conn)
+	} else if subscribeMethod == "stream" {
+		if len(params) < 2 {
+			return "", NewInvalidRequestError("params[1] is not exist")
+		}
+		raw, ok := params[1].(string)
+		if !ok {
+			return "", NewInvalidParamsError("params[1] is not a raw telegram")
+		}
+		decodeHex, err := hex.DecodeHex(raw)
+		if err != nil {
+			return "", NewInvalidRequestError(err.Error())
+		}
+		tele := &types.Telegram{}
+		if err := tele.UnmarshalRLP(decodeHex); err != nil {
+			return "", NewInvalidRequestError(err.Error())
+		}
+		tele.ComputeHash()
+
+		filterID = d.streamFilterManager.NewStreamFilter(tele, conn)
+	} else if subscribeMethod == "job" {
+		if len(params) < 2 {
+			return "", NewInvalidRequestError("params[1] is not exist")
+		}
+		jobID, ok := params[1].(string)
+		if !ok {
+			return "", NewInvalidParamsError("params[1] is not a job id")
+		}
+
+		filterID = d.jobFilterManager.NewJobFilter(jobID, conn)
 	} else {
 		return "", NewSubscriptionNotFoundError(subscribeMethod)
 	}
@@ -304,11 +339,27 @@ func (d *Dispatcher) handleUnsubscribe(req Request) (bool, Error) {
 		return false, NewSubscriptionNotFoundError(filterID)
 	}
 
+	if d.streamFilterManager != nil && d.streamFilterManager.Uninstall(filterID) {
+		return true, nil
+	}
+
+	if d.jobFilterManager != nil && d.jobFilterManager.Uninstall(filterID) {
+		return true, nil
+	}
+
 	return d.filterManager.Uninstall(filterID), nil
 }
 
 func (d *Dispatcher) RemoveFilterByWs(conn wsConn) {
 	d.filterManager.RemoveFilterByWs(conn)
+
+	if d.streamFilterManager != nil {
+		d.streamFilterManager.RemoveFilterByWs(conn)
+	}
+
+	if d.jobFilterManager != nil {
+		d.jobFilterManager.RemoveFilterByWs(conn)
+	}
 }
 
 func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
@@ -367,6 +418,33 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 			return NewRPCResponse(req.ID, "2.0", nil, err).Bytes()
 		}
 
+		// a stream may only start relaying chunks once
+		// the subscriber has received its subscription ID
+		if d.streamFilterManager != nil && d.streamFilterManager.Exists(filterID) {
+			if writeErr := conn.WriteMessage(websocket.TextMessage, []byte(resp)); writeErr != nil {
+				d.streamFilterManager.Uninstall(filterID)
+
+				return nil, writeErr
+			}
+
+			d.streamFilterManager.Start(filterID)
+
+			return nil, nil
+		}
+
+		// same for job updates
+		if d.jobFilterManager != nil && d.jobFilterManager.Exists(filterID) {
+			if writeErr := conn.WriteMessage(websocket.TextMessage, []byte(resp)); writeErr != nil {
+				d.jobFilterManager.Uninstall(filterID)
+
+				return nil, writeErr
+			}
+
+			d.jobFilterManager.Start(filterID)
+
+			return nil, nil
+		}
+
 		return []byte(resp), nil
 	}
 
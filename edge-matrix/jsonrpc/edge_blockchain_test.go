@@ -283,6 +283,46 @@ func TestEth_GasPrice(t *testing.T) {
 	assert.Equal(t, argUint64(store.averageGasPrice), res)
 }
 
+func TestEth_FeeHistory(t *testing.T) {
+	store := newMockBlockStore()
+
+	for i := uint64(0); i < 3; i++ {
+		block := newTestBlock(i, types.Hash{byte(i + 1)})
+		block.Header.GasLimit = 100
+		block.Header.GasUsed = 50
+		block.Header.BaseFee = 1000 + i
+		store.add(block)
+	}
+
+	cheap := &types.Telegram{Type: types.DynamicFeeTx, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2000)}
+	costly := &types.Telegram{Type: types.DynamicFeeTx, GasTipCap: big.NewInt(5), GasFeeCap: big.NewInt(2000)}
+
+	latest := store.blocks[2]
+	latest.Telegrams = []*types.Telegram{costly, cheap}
+	store.receipts[latest.Hash()] = []*types.Receipt{
+		{CumulativeGasUsed: 10},
+		{CumulativeGasUsed: 50},
+	}
+
+	eth := newTestEthEndpoint(store)
+
+	res, err := eth.FeeHistory(2, LatestBlockNumber, []float64{10, 90})
+	assert.NoError(t, err)
+
+	//nolint:forcetypeassert
+	history := res.(*feeHistoryResult)
+	assert.Equal(t, argUint64(1), history.OldestBlock)
+	assert.Equal(t, []argUint64{1001, 1002, 1002}, history.BaseFee)
+	assert.Equal(t, []float64{0.5, 0.5}, history.GasUsedRatio)
+	assert.Equal(t, []argBig{argBig(*big.NewInt(1)), argBig(*big.NewInt(5))}, history.Reward[1])
+
+	_, err = eth.FeeHistory(0, LatestBlockNumber, nil)
+	assert.ErrorIs(t, err, ErrInvalidBlockCount)
+
+	_, err = eth.FeeHistory(1, LatestBlockNumber, []float64{50, 10})
+	assert.ErrorIs(t, err, ErrInvalidRewardPercentile)
+}
+
 //func TestEth_Call(t *testing.T) {
 //	t.Parallel()
 //
@@ -516,6 +556,10 @@ func (m *mockBlockStore) GetAvgGasPrice() *big.Int {
 	return big.NewInt(m.averageGasPrice)
 }
 
+func (m *mockBlockStore) CalculateBaseFee(parent *types.Header) uint64 {
+	return parent.BaseFee
+}
+
 func (m *mockBlockStore) ApplyTxn(header *types.Header, txn *types.Telegram) (*runtime.ExecutionResult, error) {
 	return &runtime.ExecutionResult{Err: m.ethCallError}, nil
 }
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/emc-protocol/edge-matrix/application"
 	"github.com/emc-protocol/edge-matrix/contracts"
 	"github.com/emc-protocol/edge-matrix/rtc"
+	"github.com/emc-protocol/edge-matrix/telepool"
 	"github.com/hashicorp/go-hclog"
 	"github.com/umbracle/fastrlp"
 	"math/big"
+	"time"
 
 	"github.com/emc-protocol/edge-matrix/chain"
 	"github.com/emc-protocol/edge-matrix/helper/common"
@@ -19,7 +22,7 @@ import (
 
 type edgeTelePoolStore interface {
 	// AddTele adds a new telegram to the telegram pool
-	AddTele(tx *types.Telegram) (string, error)
+	AddTele(tx *types.Telegram) (*application.EdgeResponse, error)
 
 	// GetPendingTx gets the pending transaction from the transaction pool, if it's present
 	GetPendingTele(txHash types.Hash) (*types.Telegram, bool)
@@ -28,6 +31,28 @@ type edgeTelePoolStore interface {
 	GetNonce(addr types.Address) uint64
 }
 
+type edgeJobStore interface {
+	// SubmitEdgeJob routes an edge call telegram in the background
+	SubmitEdgeJob(tele *types.Telegram) (*telepool.EdgeJob, error)
+
+	// GetEdgeJob returns the async edge call with the given job ID
+	GetEdgeJob(id string) (*telepool.EdgeJob, error)
+}
+
+type edgeAppStore interface {
+	// GetAppSchema returns the schema parsed from the idl of an app
+	GetAppSchema(peerId string, app string) (*application.AppSchema, error)
+
+	// GetAppPeers returns the app peers known to the node
+	GetAppPeers() []*application.AppPeer
+
+	// GetBlob reads a chunk of a blob of an app peer
+	GetBlob(peerId string, hash types.Hash, offset uint64, length uint64) ([]byte, error)
+
+	// PutBlob uploads a chunk of a blob to an app peer
+	PutBlob(peerId string, hash types.Hash, size uint64, offset uint64, chunk []byte) (uint64, error)
+}
+
 type edgeRtcStore interface {
 	SendMsg(msg *rtc.RtcMsg) error
 	Sender(msg *rtc.RtcMsg) (types.Address, error)
@@ -67,6 +92,9 @@ type ethBlockchainStore interface {
 	// GetAvgGasPrice returns the average gas price
 	GetAvgGasPrice() *big.Int
 
+	// CalculateBaseFee returns the base fee of the child of the given header
+	CalculateBaseFee(parent *types.Header) uint64
+
 	// ApplyTxn applies a transaction object to the blockchain
 	ApplyTxn(header *types.Header, txn *types.Telegram) (*runtime.ExecutionResult, error)
 
@@ -77,6 +105,8 @@ type ethBlockchainStore interface {
 // edgeStore provides access to the methods needed by edge endpoint
 type edgeStore interface {
 	edgeTelePoolStore
+	edgeJobStore
+	edgeAppStore
 	edgeRtcStore
 	ethStateStore
 	ethBlockchainStore
@@ -171,6 +201,27 @@ func (e *Edge) BlockNumber() (interface{}, error) {
 	return argUintPtr(h.Number), nil
 }
 
+// sendRawTelegramResult is the json result of SendRawTelegram
+type sendRawTelegramResult struct {
+	TelegramHash string `json:"telegram_hash"`
+	Response     string `json:"response"`
+	// StatusCode and Headers are only set for app responses proxied by the endpoint /api
+	StatusCode uint64   `json:"status_code,omitempty"`
+	Headers    []string `json:"headers,omitempty"`
+	// peer id and address of the provider that served the edge call
+	ProviderID string `json:"provider_id,omitempty"`
+	Provider   string `json:"provider,omitempty"`
+}
+
+// providerAddress returns the address of the provider of the edge response, empty if none
+func providerAddress(resp *application.EdgeResponse) string {
+	if resp.From == types.ZeroAddress {
+		return ""
+	}
+
+	return resp.From.String()
+}
+
 // SendRawTelegram sends a raw telegram
 func (e *Edge) SendRawTelegram(buf argBytes) (interface{}, error) {
 	tele := &types.Telegram{}
@@ -184,8 +235,93 @@ func (e *Edge) SendRawTelegram(buf argBytes) (interface{}, error) {
 	if teleErr != nil {
 		return nil, teleErr
 	}
-	resp := fmt.Sprintf(`{"telegram_hash":"%s","response":"%s"}`, tele.Hash.String(), teleResp)
-	return resp, nil
+
+	resp, err := json.Marshal(&sendRawTelegramResult{
+		TelegramHash: tele.Hash.String(),
+		Response:     teleResp.RespString,
+		StatusCode:   teleResp.StatusCode,
+		Headers:      teleResp.Headers,
+		ProviderID:   teleResp.ProviderID,
+		Provider:     providerAddress(teleResp),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return string(resp), nil
+}
+
+// SubmitJob routes a raw edge call telegram in the background,
+// and returns its job right away. The job ID is the telegram hash
+func (e *Edge) SubmitJob(buf argBytes) (interface{}, error) {
+	tele := &types.Telegram{}
+	if err := tele.UnmarshalRLP(buf); err != nil {
+		return nil, err
+	}
+
+	tele.ComputeHash()
+
+	return e.store.SubmitEdgeJob(tele)
+}
+
+// GetJob returns the state of an async edge call, with its signed result once done
+func (e *Edge) GetJob(id string) (interface{}, error) {
+	return e.store.GetEdgeJob(id)
+}
+
+// GetAppSchema returns the typed schema of an app, parsed from its idl, so that clients can be
+// generated for it. The app is the default app of the peer if empty, the peer is the best
+// app peer serving the app if empty.
+func (e *Edge) GetAppSchema(peerId string, app string) (interface{}, error) {
+	return e.store.GetAppSchema(peerId, app)
+}
+
+// QueryNodes returns the page of the app peers known to the node matching the query,
+// all of them if the query is nil
+func (e *Edge) QueryNodes(query *NodeQuery) (interface{}, error) {
+	if query == nil {
+		query = &NodeQuery{}
+	}
+
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+
+	appPeers := e.store.GetAppPeers()
+
+	nodes := make([]*NodeInfo, len(appPeers))
+	for i, appPeer := range appPeers {
+		nodes[i] = newNodeInfoFromAppPeer(appPeer)
+	}
+
+	return query.Run(nodes, time.Now()), nil
+}
+
+// GetAppPeers is an alias of QueryNodes
+func (e *Edge) GetAppPeers(query *NodeQuery) (interface{}, error) {
+	return e.QueryNodes(query)
+}
+
+// GetBlob returns length bytes of a blob of the app peer from offset, at most application.BlobChunkSize.
+// Edge responses too large to be returned inline reference a blob by its hash and size.
+func (e *Edge) GetBlob(peerId string, hash types.Hash, offset argUint64, length argUint64) (interface{}, error) {
+	chunk, err := e.store.GetBlob(peerId, hash, uint64(offset), uint64(length))
+	if err != nil {
+		return nil, err
+	}
+
+	return argBytes(chunk), nil
+}
+
+// PutBlob uploads a chunk of a blob of the given size and hash to the app peer, at offset,
+// and returns the size of the blob uploaded so far. Uploads resume from that size.
+func (e *Edge) PutBlob(peerId string, hash types.Hash, size argUint64, offset argUint64, chunk argBytes) (interface{}, error) {
+	uploaded, err := e.store.PutBlob(peerId, hash, uint64(size), uint64(offset), chunk)
+	if err != nil {
+		return nil, err
+	}
+
+	return argUint64(uploaded), nil
 }
 
 func (e *Edge) SendRawMsg(buf argBytes) (interface{}, error) {
@@ -0,0 +1,146 @@
+package jsonrpc
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/emc-protocol/edge-matrix/types"
+)
+
+// maxFeeHistoryBlocks is the max number of blocks returned by edge_feeHistory
+const maxFeeHistoryBlocks = 1024
+
+var (
+	ErrInvalidBlockCount       = errors.New("invalid argument 0: block count must be between 1 and 1024")
+	ErrInvalidRewardPercentile = errors.New("invalid argument 2: reward percentiles must be ascending values between 0 and 100")
+)
+
+type feeHistoryResult struct {
+	OldestBlock  argUint64   `json:"oldestBlock"`
+	BaseFee      []argUint64 `json:"baseFeePerGas"`
+	GasUsedRatio []float64   `json:"gasUsedRatio"`
+	Reward       [][]argBig  `json:"reward,omitempty"`
+}
+
+// FeeHistory returns the base fees, the gas used ratio and the tips at the given percentiles
+// of the blockCount blocks up to newestBlock. The base fees include the one of the block
+// following newestBlock.
+func (e *Edge) FeeHistory(
+	blockCount argUint64,
+	newestBlock BlockNumber,
+	rewardPercentiles []float64,
+) (interface{}, error) {
+	if blockCount == 0 || blockCount > maxFeeHistoryBlocks {
+		return nil, ErrInvalidBlockCount
+	}
+
+	for i, percentile := range rewardPercentiles {
+		if percentile < 0 || percentile > 100 || (i > 0 && percentile < rewardPercentiles[i-1]) {
+			return nil, ErrInvalidRewardPercentile
+		}
+	}
+
+	newest, err := GetNumericBlockNumber(newestBlock, e.store)
+	if err != nil {
+		return nil, err
+	}
+
+	oldest := uint64(0)
+	if newest+1 > uint64(blockCount) {
+		oldest = newest + 1 - uint64(blockCount)
+	}
+
+	res := &feeHistoryResult{
+		OldestBlock:  argUint64(oldest),
+		BaseFee:      make([]argUint64, 0, newest-oldest+2),
+		GasUsedRatio: make([]float64, 0, newest-oldest+1),
+	}
+
+	var header *types.Header
+
+	for num := oldest; num <= newest; num++ {
+		block, ok := e.store.GetBlockByNumber(num, true)
+		if !ok {
+			return nil, ErrBlockNotFound
+		}
+
+		header = block.Header
+
+		res.BaseFee = append(res.BaseFee, argUint64(header.BaseFee))
+
+		ratio := float64(0)
+		if header.GasLimit != 0 {
+			ratio = float64(header.GasUsed) / float64(header.GasLimit)
+		}
+
+		res.GasUsedRatio = append(res.GasUsedRatio, ratio)
+
+		if len(rewardPercentiles) == 0 {
+			continue
+		}
+
+		receipts, err := e.store.GetReceiptsByHash(header.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Reward = append(res.Reward, blockRewards(block, receipts, rewardPercentiles))
+	}
+
+	res.BaseFee = append(res.BaseFee, argUint64(e.store.CalculateBaseFee(header)))
+
+	return res, nil
+}
+
+// blockRewards returns the tips paid in the block at the given percentiles
+// of its gas used
+func blockRewards(block *types.Block, receipts []*types.Receipt, percentiles []float64) []argBig {
+	rewards := make([]argBig, len(percentiles))
+
+	if len(block.Telegrams) == 0 || len(receipts) != len(block.Telegrams) {
+		return rewards
+	}
+
+	type tipGas struct {
+		tip *big.Int
+		gas uint64
+	}
+
+	var (
+		baseFee = new(big.Int).SetUint64(block.Header.BaseFee)
+		tips    = make([]tipGas, len(block.Telegrams))
+		prevGas uint64
+	)
+
+	for i, tele := range block.Telegrams {
+		tips[i] = tipGas{
+			tip: tele.EffectiveTip(baseFee),
+			gas: receipts[i].CumulativeGasUsed - prevGas,
+		}
+		prevGas = receipts[i].CumulativeGasUsed
+	}
+
+	sort.Slice(tips, func(i, j int) bool {
+		return tips[i].tip.Cmp(tips[j].tip) < 0
+	})
+
+	var (
+		idx     int
+		sumGas  = tips[0].gas
+		gasUsed = float64(block.Header.GasUsed)
+	)
+
+	for i, percentile := range percentiles {
+		threshold := uint64(gasUsed * percentile / 100)
+
+		for sumGas < threshold && idx < len(tips)-1 {
+			idx++
+			sumGas += tips[idx].gas
+		}
+
+		rewards[i] = argBig(*tips[idx].tip)
+	}
+
+	return rewards
+}
@@ -0,0 +1,174 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/emc-protocol/edge-matrix/telepool"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-hclog"
+	"sync"
+)
+
+// jobFilterManagerStore provides methods required by JobFilterManager
+type jobFilterManagerStore interface {
+	// SubscribeEdgeJob returns the current state of an async edge call and its updates
+	SubscribeEdgeJob(id string) (*telepool.EdgeJob, <-chan *telepool.EdgeJob, func(), error)
+}
+
+// JobFilterManager manages all async edge call subscriptions
+type JobFilterManager struct {
+	sync.RWMutex
+
+	logger hclog.Logger
+
+	store jobFilterManagerStore
+
+	filters map[string]*jobFilter
+}
+
+// jobFilter relays the updates of one async edge call to a web socket stream
+type jobFilter struct {
+	filterBase
+
+	jobID  string
+	cancel func()
+}
+
+// writeJob writes the job state to web socket stream
+func (f *jobFilter) writeJob(job *telepool.EdgeJob) error {
+	res, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return f.writeMessageToWs(string(res))
+}
+
+func NewJobFilterManager(logger hclog.Logger, store jobFilterManagerStore) *JobFilterManager {
+	return &JobFilterManager{
+		logger:  logger.Named("job-filter"),
+		store:   store,
+		filters: make(map[string]*jobFilter),
+	}
+}
+
+// NewJobFilter adds a new filter for the async edge call with the given job ID.
+// Updates are not relayed until Start is called
+func (f *JobFilterManager) NewJobFilter(jobID string, ws wsConn) string {
+	filter := &jobFilter{
+		filterBase: filterBase{
+			id:        uuid.New().String(),
+			ws:        ws,
+			heapIndex: NoIndexInHeap,
+		},
+		jobID: jobID,
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	f.filters[filter.id] = filter
+
+	return filter.id
+}
+
+// Start relays the job updates of the filter with given ID in the background.
+// It must be called once the subscription ID has been written to the subscriber
+func (f *JobFilterManager) Start(id string) {
+	f.RLock()
+	filter, ok := f.filters[id]
+	f.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	go f.run(filter)
+}
+
+// run writes the current job state and all its updates until it is finished
+func (f *JobFilterManager) run(filter *jobFilter) {
+	defer f.Uninstall(filter.id)
+
+	job, updates, cancel, err := f.store.SubscribeEdgeJob(filter.jobID)
+	if err != nil {
+		f.logger.Debug(fmt.Sprintf("job %s subscription failed, %v", filter.jobID, err))
+
+		_ = filter.writeMessageToWs(fmt.Sprintf(`{"job_id":%q,"error":%q}`, filter.jobID, err.Error()))
+
+		return
+	}
+
+	f.Lock()
+	if _, ok := f.filters[filter.id]; !ok {
+		// uninstalled while subscribing
+		f.Unlock()
+		cancel()
+
+		return
+	}
+
+	filter.cancel = cancel
+	f.Unlock()
+
+	if err := filter.writeJob(job); err != nil {
+		cancel()
+
+		return
+	}
+
+	for job := range updates {
+		if err := filter.writeJob(job); err != nil {
+			cancel()
+
+			return
+		}
+	}
+}
+
+// Exists checks the filter with given ID exists
+func (f *JobFilterManager) Exists(id string) bool {
+	f.RLock()
+	defer f.RUnlock()
+
+	_, ok := f.filters[id]
+
+	return ok
+}
+
+// Uninstall removes the filter with given ID, ending its subscription
+func (f *JobFilterManager) Uninstall(id string) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	filter, ok := f.filters[id]
+	if !ok {
+		return false
+	}
+
+	f.removeFilter(filter)
+
+	return true
+}
+
+// RemoveFilterByWs removes all the filters with given WS [Thread safe]
+func (f *JobFilterManager) RemoveFilterByWs(ws wsConn) {
+	f.Lock()
+	defer f.Unlock()
+
+	for _, filter := range f.filters {
+		if filter.ws == ws {
+			f.removeFilter(filter)
+		}
+	}
+}
+
+// removeFilter removes the filter and cancels its job subscription [NOT Thread Safe]
+func (f *JobFilterManager) removeFilter(filter *jobFilter) {
+	delete(f.filters, filter.id)
+
+	if filter.cancel != nil {
+		filter.cancel()
+		filter.cancel = nil
+	}
+}
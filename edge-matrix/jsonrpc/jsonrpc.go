@@ -57,6 +57,8 @@ type JSONRPCStore interface {
 	filterManagerStore
 	rtcFilterManagerStore
 	nodeFilterManagerStore
+	streamFilterManagerStore
+	jobFilterManagerStore
 	//bridgeStore
 	//debugStore
 }
@@ -260,7 +262,7 @@ func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
 						msgType,
 						[]byte(fmt.Sprintf("WS Handle error: %s", handleErr.Error())),
 					)
-				} else {
+				} else if resp != nil {
 					_ = wrapConn.WriteMessage(msgType, resp)
 				}
 			}()
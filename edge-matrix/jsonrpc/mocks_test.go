@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/emc-protocol/edge-matrix/blockchain"
+	"github.com/emc-protocol/edge-matrix/telepool"
 	"github.com/emc-protocol/edge-matrix/types"
 )
 
@@ -172,6 +173,10 @@ func (m *mockStore) GetCapacity() (uint64, uint64) {
 	return 0, 0
 }
 
+func (m *mockStore) GetEvictionStats() telepool.EvictionStats {
+	return telepool.EvictionStats{}
+}
+
 func (m *mockStore) GenerateExitProof(exitID, epoch, checkpointNumber uint64) (types.Proof, error) {
 	hash := types.BytesToHash([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
 
@@ -2,15 +2,216 @@ package jsonrpc
 
 import (
 	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/emc-protocol/edge-matrix/application"
+	"github.com/emc-protocol/edge-matrix/application/proof/helper"
+	"github.com/hashicorp/go-version"
+)
+
+const (
+	// defaultNodeQueryLimit is the page size of node queries without limit
+	defaultNodeQueryLimit = 100
+	// maxNodeQueryLimit is the max page size of node queries
+	maxNodeQueryLimit = 1000
+)
+
+// node query sort keys
+const (
+	NodeSortPower    = "power"
+	NodeSortLoad     = "load"
+	NodeSortLastSeen = "last_seen"
+	NodeSortUptime   = "uptime"
+	NodeSortVram     = "vram"
+)
+
+var (
+	ErrNodeQuerySort    = errors.New("unknown node query sort key")
+	ErrNodeQueryVersion = errors.New("invalid node query version")
 )
 
-// NodeQuery is a query to filter node
+// NodeQuery is a query to filter nodes. Every non-zero field is a predicate the node must
+// satisfy. Sorting and pagination only apply to node queries, not to node subscriptions.
 type NodeQuery struct {
-	name    string
-	tag     string
-	id      string
-	version string
+	Name      string `json:"name,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+	ID        string `json:"id,omitempty"`
+	AppOrigin string `json:"app_origin,omitempty"`
+	ModelHash string `json:"model_hash,omitempty"`
+	// exact version
+	Version string `json:"version,omitempty"`
+	// inclusive version range
+	MinVersion string `json:"min_version,omitempty"`
+	MaxVersion string `json:"max_version,omitempty"`
+	// case insensitive part of the model of one of the node gpus
+	Gpu string `json:"gpu,omitempty"`
+	// min vram of a node gpu, nodes not reporting their vram do not match
+	MinVramMB uint64 `json:"min_vram_mb,omitempty"`
+	// min total memory of the node
+	MinMemoryMB uint64 `json:"min_memory_mb,omitempty"`
+	// min physical cpu cores, nodes not reporting their hardware profile do not match
+	MinCpuCores uint32 `json:"min_cpu_cores,omitempty"`
+	// min average e-power
+	MinPower float32 `json:"min_power,omitempty"`
+	// max ratio of the app slots in use, from 0 to 1
+	MaxLoad *float64 `json:"max_load,omitempty"`
+	// max number of seconds since the last app status of the node
+	SeenWithin uint64 `json:"seen_within,omitempty"`
+
+	// sort key, one of power, load, last_seen, uptime and vram
+	Sort string `json:"sort,omitempty"`
+	// sort in descending order
+	Desc   bool   `json:"desc,omitempty"`
+	Offset uint64 `json:"offset,omitempty"`
+	// page size, defaultNodeQueryLimit if 0
+	Limit uint64 `json:"limit,omitempty"`
+}
+
+// NodeInfo is a node returned by node queries
+type NodeInfo struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Tag          string    `json:"tag,omitempty"`
+	AppOrigin    string    `json:"app_origin"`
+	ModelHash    string    `json:"model_hash"`
+	Version      string    `json:"version"`
+	Relay        string    `json:"relay,omitempty"`
+	CpuInfo      string    `json:"cpu_info"`
+	GpuInfo      string    `json:"gpu_info"`
+	MemInfo      string    `json:"mem_info"`
+	AveragePower float32   `json:"average_power"`
+	StartupTime  uint64    `json:"startup_time"`
+	Uptime       uint64    `json:"uptime"`
+	GuageHeight  uint64    `json:"guage_height"`
+	GuageMax     uint64    `json:"guage_max"`
+	PubKey       string    `json:"pub_key,omitempty"`
+	LastSeen     time.Time `json:"last_seen"`
+	// reloaded from the snapshot of a previous run and not refreshed yet
+	Unverified bool `json:"unverified,omitempty"`
+	// typed hardware profile, nil for the nodes only reporting the info strings
+	Hardware *helper.HardwareProfile `json:"hardware,omitempty"`
+
+	hardware *nodeHardware
+}
+
+// NodeQueryResult is a page of the nodes matching a node query
+type NodeQueryResult struct {
+	// number of matching nodes
+	Total uint64      `json:"total"`
+	Nodes []*NodeInfo `json:"nodes"`
+}
+
+func newNodeInfoFromAppPeer(p *application.AppPeer) *NodeInfo {
+	return &NodeInfo{
+		ID:           p.ID,
+		Name:         p.Name,
+		AppOrigin:    p.AppOrigin,
+		ModelHash:    p.ModelHash,
+		Version:      p.Version,
+		Relay:        p.Relay,
+		CpuInfo:      p.CpuInfo,
+		GpuInfo:      p.GpuInfo,
+		MemInfo:      p.MemInfo,
+		AveragePower: p.AveragePower,
+		StartupTime:  p.Starup_time,
+		Uptime:       p.Uptime,
+		GuageHeight:  p.Guage_height,
+		GuageMax:     p.Guage_max,
+		PubKey:       p.PubKey,
+		LastSeen:     p.LastSeen,
+		Unverified:   p.Unverified,
+		Hardware:     p.Hardware,
+	}
+}
+
+func newNodeInfoFromApplication(a *application.Application) *NodeInfo {
+	return &NodeInfo{
+		ID:           a.PeerID.String(),
+		Name:         a.Name,
+		Tag:          a.Tag,
+		AppOrigin:    a.AppOrigin,
+		ModelHash:    a.ModelHash,
+		Version:      a.Version,
+		CpuInfo:      a.CpuInfo,
+		GpuInfo:      a.GpuInfo,
+		MemInfo:      a.MemInfo,
+		AveragePower: a.AveragePower,
+		StartupTime:  a.StartupTime,
+		Uptime:       a.Uptime,
+		GuageHeight:  a.GuageHeight,
+		GuageMax:     a.GuageMax,
+		PubKey:       a.PubKey,
+		LastSeen:     a.LastSeen,
+		Hardware:     a.Hardware,
+	}
+}
+
+// load returns the ratio of the app slots in use
+func (n *NodeInfo) load() float64 {
+	if n.GuageMax == 0 {
+		return 0
+	}
+
+	return float64(n.GuageHeight) / float64(n.GuageMax)
+}
+
+func (n *NodeInfo) getHardware() *nodeHardware {
+	if n.hardware == nil {
+		if n.Hardware != nil {
+			n.hardware = newNodeHardware(n.Hardware)
+		} else {
+			n.hardware = parseNodeHardware(n.GpuInfo, n.MemInfo)
+		}
+	}
+
+	return n.hardware
+}
+
+// nodeHardware is the hardware of a node the queries filter on
+type nodeHardware struct {
+	gpus       []string
+	maxVramMB  uint64
+	memTotalMB uint64
+	cpuCores   uint32
+}
+
+func newNodeHardware(profile *helper.HardwareProfile) *nodeHardware {
+	hardware := &nodeHardware{
+		maxVramMB:  profile.MaxVram(),
+		memTotalMB: profile.MemTotal,
+		cpuCores:   profile.Cpu.Cores,
+	}
+
+	for _, gpu := range profile.Gpus {
+		hardware.gpus = append(hardware.gpus, gpu.Vendor+" "+gpu.Model)
+	}
+
+	return hardware
+}
+
+// parseNodeHardware parses the info strings of the nodes without hardware profile,
+// which do not report their vram nor cpu cores
+func parseNodeHardware(gpuInfo, memInfo string) *nodeHardware {
+	hardware := &nodeHardware{}
+
+	gpus := struct {
+		GraphicsCard []string `json:"graphics_card"`
+	}{}
+	if err := json.Unmarshal([]byte(gpuInfo), &gpus); err == nil {
+		hardware.gpus = gpus.GraphicsCard
+	}
+
+	mem := struct {
+		Total uint64 `json:"total"`
+	}{}
+	if err := json.Unmarshal([]byte(memInfo), &mem); err == nil {
+		hardware.memTotalMB = mem.Total / (1024 * 1024)
+	}
+
+	return hardware
 }
 
 func decodeNodeQueryFromInterface(i interface{}) (*NodeQuery, error) {
@@ -25,35 +226,108 @@ func decodeNodeQueryFromInterface(i interface{}) (*NodeQuery, error) {
 		return nil, err
 	}
 
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+
 	return query, nil
 }
 
-func (q *NodeQuery) Match(rm *application.Application) bool {
-	if q.tag != "" {
-		match := false
-		if q.tag == rm.Tag {
-			match = true
+// validate returns an error if the query can not be run
+func (q *NodeQuery) validate() error {
+	switch q.Sort {
+	case "", NodeSortPower, NodeSortLoad, NodeSortLastSeen, NodeSortUptime, NodeSortVram:
+	default:
+		return ErrNodeQuerySort
+	}
+
+	for _, v := range []string{q.MinVersion, q.MaxVersion} {
+		if v == "" {
+			continue
 		}
-		if !match {
-			return false
+
+		if _, err := version.NewVersion(v); err != nil {
+			return ErrNodeQueryVersion
 		}
 	}
-	// check name
-	if q.name != "" {
-		match := false
-		if rm.Name == q.name {
-			match = true
+
+	return nil
+}
+
+// Match returns true if the node of the app event satisfies the query
+func (q *NodeQuery) Match(rm *application.Application) bool {
+	return q.MatchNode(newNodeInfoFromApplication(rm), time.Now())
+}
+
+// MatchNode returns true if the node satisfies the query
+func (q *NodeQuery) MatchNode(n *NodeInfo, now time.Time) bool {
+	if !matchString(q.Tag, n.Tag) ||
+		!matchString(q.Name, n.Name) ||
+		!matchString(q.ID, n.ID) ||
+		!matchString(q.AppOrigin, n.AppOrigin) ||
+		!matchString(q.ModelHash, n.ModelHash) ||
+		!matchString(q.Version, n.Version) {
+		return false
+	}
+
+	if !q.matchVersionRange(n.Version) {
+		return false
+	}
+
+	if q.MinPower > 0 && n.AveragePower < q.MinPower {
+		return false
+	}
+
+	if q.MaxLoad != nil && n.load() > *q.MaxLoad {
+		return false
+	}
+
+	if q.SeenWithin > 0 && now.Sub(n.LastSeen) > time.Duration(q.SeenWithin)*time.Second {
+		return false
+	}
+
+	if q.Gpu != "" || q.MinVramMB > 0 || q.MinMemoryMB > 0 || q.MinCpuCores > 0 {
+		return q.matchHardware(n.getHardware())
+	}
+
+	return true
+}
+
+func (q *NodeQuery) matchVersionRange(v string) bool {
+	if q.MinVersion == "" && q.MaxVersion == "" {
+		return true
+	}
+
+	nodeVersion, err := version.NewVersion(v)
+	if err != nil {
+		return false
+	}
+
+	if q.MinVersion != "" {
+		if min, err := version.NewVersion(q.MinVersion); err != nil || nodeVersion.LessThan(min) {
+			return false
 		}
+	}
 
-		if !match {
+	if q.MaxVersion != "" {
+		if max, err := version.NewVersion(q.MaxVersion); err != nil || nodeVersion.GreaterThan(max) {
 			return false
 		}
 	}
 
-	if q.id != "" {
+	return true
+}
+
+func (q *NodeQuery) matchHardware(hardware *nodeHardware) bool {
+	if q.Gpu != "" {
 		match := false
-		if rm.PeerID.String() == q.id {
-			match = true
+
+		for _, gpu := range hardware.gpus {
+			if strings.Contains(strings.ToLower(gpu), strings.ToLower(q.Gpu)) {
+				match = true
+
+				break
+			}
 		}
 
 		if !match {
@@ -61,16 +335,93 @@ func (q *NodeQuery) Match(rm *application.Application) bool {
 		}
 	}
 
-	if q.version != "" {
-		match := false
-		if rm.Version == q.version {
-			match = true
+	if q.MinVramMB > 0 && hardware.maxVramMB < q.MinVramMB {
+		return false
+	}
+
+	if q.MinMemoryMB > 0 && hardware.memTotalMB < q.MinMemoryMB {
+		return false
+	}
+
+	if q.MinCpuCores > 0 && hardware.cpuCores < q.MinCpuCores {
+		return false
+	}
+
+	return true
+}
+
+// Run returns the page of the nodes matching the query
+func (q *NodeQuery) Run(nodes []*NodeInfo, now time.Time) *NodeQueryResult {
+	matches := make([]*NodeInfo, 0, len(nodes))
+
+	for _, n := range nodes {
+		if q.MatchNode(n, now) {
+			matches = append(matches, n)
 		}
+	}
 
-		if !match {
-			return false
+	q.sort(matches)
+
+	result := &NodeQueryResult{Total: uint64(len(matches)), Nodes: []*NodeInfo{}}
+
+	limit := q.Limit
+	if limit == 0 {
+		limit = defaultNodeQueryLimit
+	} else if limit > maxNodeQueryLimit {
+		limit = maxNodeQueryLimit
+	}
+
+	if q.Offset >= uint64(len(matches)) {
+		return result
+	}
+
+	end := q.Offset + limit
+	if end > uint64(len(matches)) {
+		end = uint64(len(matches))
+	}
+
+	result.Nodes = matches[q.Offset:end]
+
+	return result
+}
+
+// sort sorts the nodes by the sort key of the query, then by id and name
+// so that pages are stable
+func (q *NodeQuery) sort(nodes []*NodeInfo) {
+	less := func(a, b *NodeInfo) (bool, bool) {
+		switch q.Sort {
+		case NodeSortPower:
+			return a.AveragePower < b.AveragePower, a.AveragePower == b.AveragePower
+		case NodeSortLoad:
+			return a.load() < b.load(), a.load() == b.load()
+		case NodeSortLastSeen:
+			return a.LastSeen.Before(b.LastSeen), a.LastSeen.Equal(b.LastSeen)
+		case NodeSortUptime:
+			return a.Uptime < b.Uptime, a.Uptime == b.Uptime
+		case NodeSortVram:
+			aVram, bVram := a.getHardware().maxVramMB, b.getHardware().maxVramMB
+
+			return aVram < bVram, aVram == bVram
+		default:
+			return false, true
 		}
 	}
 
-	return true
+	sort.SliceStable(nodes, func(i, j int) bool {
+		isLess, isEqual := less(nodes[i], nodes[j])
+		if !isEqual {
+			return isLess != q.Desc
+		}
+
+		if nodes[i].ID != nodes[j].ID {
+			return nodes[i].ID < nodes[j].ID
+		}
+
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+// matchString returns true if the value equals the expected one, or nothing is expected
+func matchString(expected, value string) bool {
+	return expected == "" || expected == value
 }
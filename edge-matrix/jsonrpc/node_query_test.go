@@ -0,0 +1,131 @@
+package jsonrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/application"
+	"github.com/emc-protocol/edge-matrix/application/proof/helper"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeNodeQuery(t *testing.T) {
+	t.Parallel()
+
+	query, err := decodeNodeQueryFromInterface(map[string]interface{}{
+		"name":        "sd",
+		"min_version": "1.2.0",
+		"gpu":         "rtx",
+		"max_load":    0.5,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "sd", query.Name)
+	assert.Equal(t, "1.2.0", query.MinVersion)
+	assert.Equal(t, "rtx", query.Gpu)
+	assert.Equal(t, 0.5, *query.MaxLoad)
+
+	_, err = decodeNodeQueryFromInterface(map[string]interface{}{"sort": "name"})
+	assert.ErrorIs(t, err, ErrNodeQuerySort)
+
+	_, err = decodeNodeQueryFromInterface(map[string]interface{}{"max_version": "latest"})
+	assert.ErrorIs(t, err, ErrNodeQueryVersion)
+}
+
+func TestNodeQuery_Match(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	node := &NodeInfo{
+		ID:        "A",
+		Name:      "sd",
+		AppOrigin: "emc",
+		Version:   "1.3.2",
+		Hardware: &helper.HardwareProfile{
+			Version:  helper.HardwareProfileVersion,
+			Cpu:      helper.CpuProfile{Cores: 16, Threads: 32},
+			Gpus:     []helper.GpuProfile{{Vendor: "NVIDIA Corporation", Model: "AD102 [GeForce RTX 4090]", Vram: 24576}},
+			MemTotal: 65536,
+		},
+		AveragePower: 40,
+		GuageHeight:  1,
+		GuageMax:     4,
+		LastSeen:     now.Add(-10 * time.Second),
+	}
+
+	load := func(l float64) *float64 { return &l }
+
+	matching := []*NodeQuery{
+		{},
+		{Name: "sd", AppOrigin: "emc"},
+		{MinVersion: "1.3.0", MaxVersion: "1.3.2"},
+		{Gpu: "rtx 4090", MinVramMB: 16384, MinMemoryMB: 65536, MinCpuCores: 16},
+		{MinPower: 40, MaxLoad: load(0.25), SeenWithin: 30},
+	}
+	for _, query := range matching {
+		assert.True(t, query.MatchNode(node, now), "%+v", query)
+	}
+
+	notMatching := []*NodeQuery{
+		{Name: "llm"},
+		{MinVersion: "1.4.0"},
+		{MaxVersion: "1.3.1"},
+		{Gpu: "a100"},
+		{MinVramMB: 32768},
+		{MinMemoryMB: 131072},
+		{MinCpuCores: 32},
+		{MinPower: 50},
+		{MaxLoad: load(0.2)},
+		{SeenWithin: 5},
+	}
+	for _, query := range notMatching {
+		assert.False(t, query.MatchNode(node, now), "%+v", query)
+	}
+
+	// nodes without hardware profile are matched on their info strings
+	legacy := &NodeInfo{
+		GpuInfo: `{"gpus":1,"graphics_card":["card #0 -> vendor: 'NVIDIA Corporation' product: 'GA102 [GeForce RTX 3090]'"]}`,
+		MemInfo: `{"total": 68719476736, "free":1024, "used_percent":10.0}`,
+	}
+	assert.True(t, (&NodeQuery{Gpu: "RTX 3090", MinMemoryMB: 65536}).MatchNode(legacy, now))
+	assert.False(t, (&NodeQuery{MinVramMB: 1}).MatchNode(legacy, now))
+
+	// the node subscriptions use the same predicates
+	id := peer.ID("A")
+	app := &application.Application{Name: "sd", PeerID: id, Version: "1.3.2"}
+	assert.True(t, (&NodeQuery{ID: id.String(), MinVersion: "1.0.0"}).Match(app))
+	assert.False(t, (&NodeQuery{ID: "B"}).Match(app))
+}
+
+func TestNodeQuery_Run(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	nodes := []*NodeInfo{
+		{ID: "A", Name: "sd", AveragePower: 10},
+		{ID: "B", Name: "sd", AveragePower: 30},
+		{ID: "C", Name: "llm", AveragePower: 20},
+		{ID: "D", Name: "sd", AveragePower: 20},
+	}
+
+	ids := func(result *NodeQueryResult) []string {
+		res := make([]string, len(result.Nodes))
+		for i, n := range result.Nodes {
+			res[i] = n.ID
+		}
+
+		return res
+	}
+
+	result := (&NodeQuery{Name: "sd", Sort: NodeSortPower, Desc: true}).Run(nodes, now)
+	assert.Equal(t, uint64(3), result.Total)
+	assert.Equal(t, []string{"B", "D", "A"}, ids(result))
+
+	result = (&NodeQuery{Sort: NodeSortPower, Offset: 1, Limit: 2}).Run(nodes, now)
+	assert.Equal(t, uint64(4), result.Total)
+	assert.Equal(t, []string{"C", "D"}, ids(result))
+
+	result = (&NodeQuery{Offset: 10}).Run(nodes, now)
+	assert.Equal(t, uint64(4), result.Total)
+	assert.Empty(t, result.Nodes)
+}
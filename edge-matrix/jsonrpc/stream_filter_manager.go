@@ -0,0 +1,185 @@
+package jsonrpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-hclog"
+	"sync"
+)
+
+var (
+	ErrStreamClosed = errors.New("stream subscription closed")
+)
+
+// stream message types
+const (
+	streamMsgChunk = "chunk"
+	streamMsgDone  = "done"
+	streamMsgError = "error"
+)
+
+// streamFilterManagerStore provides methods required by StreamFilterManager
+type streamFilterManagerStore interface {
+	// AddTeleStream routes a streaming edge call telegram
+	AddTeleStream(tele *types.Telegram, onChunk func(chunk []byte) error) (string, error)
+}
+
+// StreamFilterManager manages all running edge call stream subscriptions
+type StreamFilterManager struct {
+	sync.RWMutex
+
+	logger hclog.Logger
+
+	store streamFilterManagerStore
+
+	filters map[string]*streamFilter
+}
+
+// streamFilter relays the response chunks of one edge call to a web socket stream
+type streamFilter struct {
+	filterBase
+
+	tele *types.Telegram
+	seq  uint64
+}
+
+// streamMsg is a single message written to the subscriber of a stream
+type streamMsg struct {
+	Type         string         `json:"type"`
+	Seq          uint64         `json:"seq,omitempty"`
+	Data         string         `json:"data,omitempty"`
+	TelegramHash *types.Hash    `json:"telegram_hash,omitempty"`
+	Response     string         `json:"response,omitempty"`
+	RespHash     *types.Hash    `json:"resp_hash,omitempty"`
+	Provider     *types.Address `json:"provider,omitempty"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// writeStreamMsg writes the message to web socket stream
+func (f *streamFilter) writeStreamMsg(msg *streamMsg) error {
+	res, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return f.writeMessageToWs(string(res))
+}
+
+func NewStreamFilterManager(logger hclog.Logger, store streamFilterManagerStore) *StreamFilterManager {
+	return &StreamFilterManager{
+		logger:  logger.Named("stream-filter"),
+		store:   store,
+		filters: make(map[string]*streamFilter),
+	}
+}
+
+// NewStreamFilter adds a new stream filter for the given edge call telegram.
+// The call is not routed until Start is called
+func (f *StreamFilterManager) NewStreamFilter(tele *types.Telegram, ws wsConn) string {
+	filter := &streamFilter{
+		filterBase: filterBase{
+			id:        uuid.New().String(),
+			ws:        ws,
+			heapIndex: NoIndexInHeap,
+		},
+		tele: tele,
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	f.filters[filter.id] = filter
+
+	return filter.id
+}
+
+// Start routes the edge call of the filter with given ID in the background.
+// It must be called once the subscription ID has been written to the subscriber
+func (f *StreamFilterManager) Start(id string) {
+	f.RLock()
+	filter, ok := f.filters[id]
+	f.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	go f.run(filter)
+}
+
+// run relays the stream chunks to the subscriber, and finishes with the signed digest
+func (f *StreamFilterManager) run(filter *streamFilter) {
+	defer f.Uninstall(filter.id)
+
+	resp, err := f.store.AddTeleStream(filter.tele, func(chunk []byte) error {
+		if !f.Exists(filter.id) {
+			return ErrStreamClosed
+		}
+
+		filter.seq++
+
+		return filter.writeStreamMsg(&streamMsg{
+			Type: streamMsgChunk,
+			Seq:  filter.seq,
+			Data: base64.StdEncoding.EncodeToString(chunk),
+		})
+	})
+	if err != nil {
+		f.logger.Debug(fmt.Sprintf("stream %s failed, %v", filter.id, err))
+
+		_ = filter.writeStreamMsg(&streamMsg{
+			Type:  streamMsgError,
+			Error: err.Error(),
+		})
+
+		return
+	}
+
+	_ = filter.writeStreamMsg(&streamMsg{
+		Type:         streamMsgDone,
+		TelegramHash: &filter.tele.Hash,
+		Response:     resp,
+		RespHash:     &filter.tele.RespHash,
+		Provider:     &filter.tele.RespFrom,
+	})
+}
+
+// Exists checks the filter with given ID exists
+func (f *StreamFilterManager) Exists(id string) bool {
+	f.RLock()
+	defer f.RUnlock()
+
+	_, ok := f.filters[id]
+
+	return ok
+}
+
+// Uninstall removes the filter with given ID, aborting its stream
+func (f *StreamFilterManager) Uninstall(id string) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.filters[id]; !ok {
+		return false
+	}
+
+	delete(f.filters, id)
+
+	return true
+}
+
+// RemoveFilterByWs removes all the filters with given WS [Thread safe]
+func (f *StreamFilterManager) RemoveFilterByWs(ws wsConn) {
+	f.Lock()
+	defer f.Unlock()
+
+	for id, filter := range f.filters {
+		if filter.ws == ws {
+			delete(f.filters, id)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package jsonrpc
 import (
 	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/emc-protocol/edge-matrix/telepool"
 	"github.com/emc-protocol/edge-matrix/types"
 )
 
@@ -14,6 +16,9 @@ type telePoolStore interface {
 
 	// GetCapacity returns the current and max capacity of the pool in slots
 	GetCapacity() (uint64, uint64)
+
+	// GetEvictionStats returns the replacement and eviction policy of the pool
+	GetEvictionStats() telepool.EvictionStats
 }
 
 // TelePool is the txpool jsonrpc endpoint
@@ -31,6 +36,11 @@ type InspectResponse struct {
 	Queued          map[string]map[string]string `json:"queued"`
 	CurrentCapacity uint64                       `json:"currentCapacity"`
 	MaxCapacity     uint64                       `json:"maxCapacity"`
+	PriceBump       uint64                       `json:"priceBump"`
+	Lifetime        uint64                       `json:"lifetime"`
+	Replaced        uint64                       `json:"replaced"`
+	Evicted         uint64                       `json:"evicted"`
+	Expired         uint64                       `json:"expired"`
 }
 
 type StatusResponse struct {
@@ -140,12 +150,18 @@ func (t *TelePool) Inspect() (interface{}, error) {
 
 	// get capacity of the TxPool
 	current, max := t.store.GetCapacity()
+	stats := t.store.GetEvictionStats()
 
 	resp := InspectResponse{
 		Pending:         pendingRPCTxs,
 		Queued:          queuedRPCTxs,
 		CurrentCapacity: current,
 		MaxCapacity:     max,
+		PriceBump:       stats.PriceBump,
+		Lifetime:        uint64(stats.Lifetime / time.Second),
+		Replaced:        stats.Replaced,
+		Evicted:         stats.Evicted,
+		Expired:         stats.Expired,
 	}
 
 	return resp, nil
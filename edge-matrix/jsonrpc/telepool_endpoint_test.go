@@ -4,7 +4,9 @@ import (
 	"math/big"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/emc-protocol/edge-matrix/telepool"
 	"github.com/emc-protocol/edge-matrix/types"
 
 	"github.com/stretchr/testify/assert"
@@ -183,6 +185,30 @@ func TestInspectEndpoint(t *testing.T) {
 		assert.NotNil(t, transactionInfo[strconv.FormatUint(testTx.Nonce, 10)])
 		assert.NotNil(t, transactionInfo[strconv.FormatUint(testTx2.Nonce, 10)])
 	})
+
+	t.Run("returns the eviction stats", func(t *testing.T) {
+		t.Parallel()
+
+		mockStore := newMockTxPoolStore()
+		mockStore.evictionStats = telepool.EvictionStats{
+			PriceBump: 10,
+			Lifetime:  time.Hour,
+			Replaced:  1,
+			Evicted:   2,
+			Expired:   3,
+		}
+		txPoolEndpoint := &TelePool{mockStore}
+
+		result, _ := txPoolEndpoint.Inspect()
+		//nolint:forcetypeassert
+		response := result.(InspectResponse)
+
+		assert.Equal(t, uint64(10), response.PriceBump)
+		assert.Equal(t, uint64(3600), response.Lifetime)
+		assert.Equal(t, uint64(1), response.Replaced)
+		assert.Equal(t, uint64(2), response.Evicted)
+		assert.Equal(t, uint64(3), response.Expired)
+	})
 }
 
 func TestStatusEndpoint(t *testing.T) {
@@ -234,6 +260,7 @@ type mockTxPoolStore struct {
 	capacity      uint64
 	maxSlots      uint64
 	includeQueued bool
+	evictionStats telepool.EvictionStats
 }
 
 func newMockTxPoolStore() *mockTxPoolStore {
@@ -253,6 +280,10 @@ func (s *mockTxPoolStore) GetCapacity() (uint64, uint64) {
 	return s.capacity, s.maxSlots
 }
 
+func (s *mockTxPoolStore) GetEvictionStats() telepool.EvictionStats {
+	return s.evictionStats
+}
+
 func newTestTransaction(nonce uint64, from types.Address) *types.Telegram {
 	txn := &types.Telegram{
 		Nonce:    nonce,
@@ -36,6 +36,11 @@ type transaction struct {
 	RespS    *big.Int      `json:"RespS"`
 	RespHash types.Hash    `json:"RespHash"`
 	RespFrom types.Address `json:"RespFrom"`
+
+	// Type and fee caps are only set for dynamic fee telegrams
+	Type                 *argUint64 `json:"type,omitempty"`
+	MaxFeePerGas         *argBig    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *argBig    `json:"maxPriorityFeePerGas,omitempty"`
 }
 
 func (t transaction) getHash() types.Hash { return t.Hash }
@@ -61,7 +66,7 @@ func toTransaction(
 ) *transaction {
 	res := &transaction{
 		Nonce:    argUint64(t.Nonce),
-		GasPrice: argBig(*t.GasPrice),
+		GasPrice: argBig(*t.GetGasFeeCap()),
 		Gas:      argUint64(t.Gas),
 		To:       t.To,
 		Value:    argBig(*t.Value),
@@ -79,6 +84,12 @@ func toTransaction(
 		RespV:    t.RespV,
 	}
 
+	if t.Type == types.DynamicFeeTx {
+		res.Type = argUintPtr(uint64(t.Type))
+		res.MaxFeePerGas = argBigPtr(t.GetGasFeeCap())
+		res.MaxPriorityFeePerGas = argBigPtr(t.GetGasTipCap())
+	}
+
 	if blockNumber != nil {
 		res.BlockNumber = blockNumber
 	}
@@ -115,6 +126,8 @@ type block struct {
 	Hash      types.Hash       `json:"hash"`
 	Telegrams []telegramOrHash `json:"telegrams"`
 	Uncles    []types.Hash     `json:"uncles"`
+	// BaseFee is only set once the EIP1559 fork is active
+	BaseFee *argUint64 `json:"baseFeePerGas,omitempty"`
 }
 
 func (b *block) Copy() *block {
@@ -155,6 +168,10 @@ func toBlock(b *types.Block, fullTx bool) *block {
 		Uncles:    []types.Hash{},
 	}
 
+	if h.BaseFee != 0 {
+		res.BaseFee = argUintPtr(h.BaseFee)
+	}
+
 	for idx, txn := range b.Telegrams {
 		if fullTx {
 			res.Telegrams = append(
@@ -4,6 +4,7 @@ import (
 	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/emc-protocol/edge-matrix/crypto"
 	"github.com/emc-protocol/edge-matrix/helper/hex"
 	"github.com/emc-protocol/edge-matrix/helper/rpc"
@@ -233,6 +234,73 @@ func (m *MinerHubAgent) RegisterComputingNode(nodeId string, minerPrincipal stri
 	return errors.New("RegisterComputingNode fail")
 }
 
+// call EMCHub's poc submit api, reporting the result of a proof of compute challenge
+// of the given kind of the computing node nodeId, power being 0 if the challenge failed
+func (m *MinerHubAgent) SubmitPocResult(validator string, nodeId string, kind string, ticket int64, power int64) error {
+	privateKey := m.getPrivateKey()
+	if privateKey == nil {
+		return errors.New("SubmitPocResult fail: unable to extract key")
+	}
+
+	message := fmt.Sprintf("%s,%s,%s,%d,%d", validator, nodeId, kind, ticket, power)
+	keccak256 := crypto.Keccak256([]byte(message))
+
+	signature, err := crypto.Sign(
+		privateKey,
+		keccak256,
+	)
+	if err != nil {
+		return errors.New("SubmitPocResult fail: " + err.Error())
+	}
+
+	var entity struct {
+		Validator        string `json:"validator"`
+		NodeId           string `json:"nodeId"`
+		Kind             string `json:"kind"`
+		ValidationTicket int64  `json:"validationTicket"`
+		Power            int64  `json:"power"`
+		Kecack256        string `json:"kecack256"`
+		Signature        string `json:"signature"`
+	}
+	entity.Validator = validator
+	entity.NodeId = nodeId
+	entity.Kind = kind
+	entity.ValidationTicket = ticket
+	entity.Power = power
+	entity.Kecack256 = hex.EncodeToString(keccak256)
+	entity.Signature = hex.EncodeToString(signature)
+
+	entityJsonBytes, err := json.Marshal(entity)
+	if err != nil {
+		return err
+	}
+	respBytes, err := m.httpClient.SendPostJsonRequest(DEFAULT_HUB_HOST+"/api/v1/poc/submit", entityJsonBytes)
+	if err != nil {
+		return errors.New("SubmitPocResult fail: " + err.Error())
+	}
+
+	if len(respBytes) > 0 {
+		m.logger.Debug("SubmitPocResult", "resp", string(respBytes))
+
+		var response struct {
+			Result int    `json:"_result"`
+			Desc   string `json:"_desc"`
+		}
+		err := json.Unmarshal(respBytes, &response)
+		if err != nil {
+			return errors.New("SubmitPocResult fail: " + err.Error())
+		}
+
+		if response.Result == 0 {
+			return nil
+		}
+
+		return errors.New("SubmitPocResult fail: " + response.Desc)
+	}
+
+	return errors.New("SubmitPocResult fail")
+}
+
 func (m *MinerHubAgent) AddRouter(minerPrincipal string) error {
 
 	return errors.New("AddRouter fail")
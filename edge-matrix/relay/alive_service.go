@@ -13,6 +13,7 @@ import (
 	"github.com/multiformats/go-multiaddr"
 	"regexp"
 	"sync"
+	"time"
 )
 
 const (
@@ -27,6 +28,9 @@ type networkingServer interface {
 	GetPeerAddrInfo(peerID peer.ID) peer.AddrInfo
 
 	GetRandomBootnode() *peer.AddrInfo
+
+	// DisconnectFromPeer disconnects the peer
+	DisconnectFromPeer(peerID peer.ID, reason string)
 }
 
 // BOOTNODE QUERIES //
@@ -41,7 +45,8 @@ type AliveService struct {
 	//routingTable *kb.RoutingTable // Kademlia 'k-bucket' routing table that contains connected nodes info
 
 	syncAppPeerClient application.SyncAppPeerClient
-	closeCh           chan struct{} // Channel used for stopping the AliveService
+	statusVerifier    *application.AppStatusVerifier // verifies the app statuses of the edge nodes
+	closeCh           chan struct{}                  // Channel used for stopping the AliveService
 }
 
 // NewAliveService creates a new instance of the alive service
@@ -56,6 +61,7 @@ func NewAliveService(
 		baseServer: server,
 		//routingTable:      routingTable,
 		syncAppPeerClient: syncAppPeerClient,
+		statusVerifier:    application.NewAppStatusVerifier(),
 		closeCh:           make(chan struct{}),
 	}
 }
@@ -91,23 +97,20 @@ func (d *AliveService) Hello(ctx context.Context, status *proto.AliveStatus) (*p
 		innerIp = isInnerIp(addrInfo.Addrs[0])
 	}
 	d.logger.Debug("-------->Alive status", "from", from, "name", status.Name, "app_origin", status.AppOrigin, "addr", addr, "relay", status.Relay)
+
+	appStatus := toAppStatus(from, status, addr)
+	if err := d.statusVerifier.Verify(appStatus, time.Now()); err != nil {
+		d.logger.Warn("rejected app status", "from", from, "name", status.Name, "err", err)
+
+		if errors.Is(err, application.ErrAppStatusSignature) {
+			d.baseServer.DisconnectFromPeer(from, err.Error())
+		}
+
+		return nil, err
+	}
+
 	if !innerIp || status.Relay != "" {
-		d.syncAppPeerClient.PublishApplicationStatus(&appProto.AppStatus{
-			Name:         status.Name,
-			NodeId:       from.String(),
-			Uptime:       status.Uptime,
-			StartupTime:  status.StartupTime,
-			Relay:        status.Relay,
-			Addr:         addr,
-			AppOrigin:    status.AppOrigin,
-			Mac:          status.Mac,
-			CpuInfo:      status.CpuInfo,
-			GpuInfo:      status.GpuInfo,
-			MemInfo:      status.MemInfo,
-			ModelHash:    status.ModelHash,
-			AveragePower: status.AveragePower,
-			Version:      status.Version,
-		})
+		d.syncAppPeerClient.PublishApplicationStatus(appStatus)
 	}
 
 	newRelayNode := d.baseServer.GetRandomBootnode()
@@ -121,6 +124,106 @@ func (d *AliveService) Hello(ctx context.Context, status *proto.AliveStatus) (*p
 	}, nil
 }
 
+// toAppStatus returns the app status of the alive status of the node,
+// the one signed by the node and published by the relay
+func toAppStatus(from peer.ID, status *proto.AliveStatus, addr string) *appProto.AppStatus {
+	return &appProto.AppStatus{
+		Name:         status.Name,
+		NodeId:       from.String(),
+		Uptime:       status.Uptime,
+		StartupTime:  status.StartupTime,
+		GuageHeight:  status.GuageHeight,
+		GuageMax:     status.GuageMax,
+		Relay:        status.Relay,
+		Addr:         addr,
+		AppOrigin:    status.AppOrigin,
+		Mac:          status.Mac,
+		CpuInfo:      status.CpuInfo,
+		GpuInfo:      status.GpuInfo,
+		MemInfo:      status.MemInfo,
+		ModelHash:    status.ModelHash,
+		AveragePower: status.AveragePower,
+		Version:      status.Version,
+		PubKey:       status.PubKey,
+		Seq:          status.Seq,
+		Signature:    status.Signature,
+		Hardware:     toAppHardware(status.Hardware),
+		Price:        status.Price,
+	}
+}
+
+// toAliveHardware converts the hardware profile of an app status to the one of an alive status
+func toAliveHardware(profile *appProto.HardwareProfile) *proto.HardwareProfile {
+	if profile == nil {
+		return nil
+	}
+
+	gpus := make([]*proto.GpuProfile, len(profile.Gpus))
+	for i, gpu := range profile.Gpus {
+		gpus[i] = &proto.GpuProfile{
+			Vendor:            gpu.GetVendor(),
+			Model:             gpu.GetModel(),
+			Vram:              gpu.GetVram(),
+			Driver:            gpu.GetDriver(),
+			ComputeCapability: gpu.GetComputeCapability(),
+		}
+	}
+
+	cpu := profile.GetCpu()
+
+	return &proto.HardwareProfile{
+		Version: profile.Version,
+		Cpu: &proto.CpuProfile{
+			Vendor:  cpu.GetVendor(),
+			Model:   cpu.GetModel(),
+			Cores:   cpu.GetCores(),
+			Threads: cpu.GetThreads(),
+			Mhz:     cpu.GetMhz(),
+		},
+		Gpus:      gpus,
+		MemTotal:  profile.MemTotal,
+		MemFree:   profile.MemFree,
+		DiskTotal: profile.DiskTotal,
+		DiskFree:  profile.DiskFree,
+	}
+}
+
+// toAppHardware converts the hardware profile of an alive status to the one of an app status
+func toAppHardware(profile *proto.HardwareProfile) *appProto.HardwareProfile {
+	if profile == nil {
+		return nil
+	}
+
+	gpus := make([]*appProto.GpuProfile, len(profile.Gpus))
+	for i, gpu := range profile.Gpus {
+		gpus[i] = &appProto.GpuProfile{
+			Vendor:            gpu.GetVendor(),
+			Model:             gpu.GetModel(),
+			Vram:              gpu.GetVram(),
+			Driver:            gpu.GetDriver(),
+			ComputeCapability: gpu.GetComputeCapability(),
+		}
+	}
+
+	cpu := profile.GetCpu()
+
+	return &appProto.HardwareProfile{
+		Version: profile.Version,
+		Cpu: &appProto.CpuProfile{
+			Vendor:  cpu.GetVendor(),
+			Model:   cpu.GetModel(),
+			Cores:   cpu.GetCores(),
+			Threads: cpu.GetThreads(),
+			Mhz:     cpu.GetMhz(),
+		},
+		Gpus:      gpus,
+		MemTotal:  profile.MemTotal,
+		MemFree:   profile.MemFree,
+		DiskTotal: profile.DiskTotal,
+		DiskFree:  profile.DiskFree,
+	}
+}
+
 func isInnerIp(ma multiaddr.Multiaddr) (innerIp bool) {
 	innerIp = false
 	ip4Addr, err := ma.ValueForProtocol(multiaddr.P_IP4)
@@ -52,6 +52,16 @@ type AliveStatus struct {
 	GpuInfo string `protobuf:"bytes,13,opt,name=gpu_info,json=gpuInfo,proto3" json:"gpu_info,omitempty"`
 	// version
 	Version string `protobuf:"bytes,14,opt,name=version,proto3" json:"version,omitempty"`
+	// provider public key, edge call inputs can be encrypted to
+	PubKey string `protobuf:"bytes,15,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	// sequence number, increasing with each status of the node
+	Seq uint64 `protobuf:"varint,16,opt,name=seq,proto3" json:"seq,omitempty"`
+	// signature of the app status by the node key
+	Signature []byte `protobuf:"bytes,17,opt,name=signature,proto3" json:"signature,omitempty"`
+	// hardware profile of the node
+	Hardware *HardwareProfile `protobuf:"bytes,18,opt,name=hardware,proto3" json:"hardware,omitempty"`
+	// price of an edge call to the app, in wei, empty if free
+	Price string `protobuf:"bytes,19,opt,name=price,proto3" json:"price,omitempty"`
 }
 
 func (x *AliveStatus) Reset() {
@@ -184,6 +194,302 @@ func (x *AliveStatus) GetVersion() string {
 	return ""
 }
 
+func (x *AliveStatus) GetPubKey() string {
+	if x != nil {
+		return x.PubKey
+	}
+	return ""
+}
+
+func (x *AliveStatus) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *AliveStatus) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *AliveStatus) GetHardware() *HardwareProfile {
+	if x != nil {
+		return x.Hardware
+	}
+	return nil
+}
+
+func (x *AliveStatus) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+// typed hardware inventory of a node
+type HardwareProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// profile format version
+	Version uint32        `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Cpu     *CpuProfile   `protobuf:"bytes,2,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Gpus    []*GpuProfile `protobuf:"bytes,3,rep,name=gpus,proto3" json:"gpus,omitempty"`
+	// memory in MB
+	MemTotal uint64 `protobuf:"varint,4,opt,name=mem_total,json=memTotal,proto3" json:"mem_total,omitempty"`
+	MemFree  uint64 `protobuf:"varint,5,opt,name=mem_free,json=memFree,proto3" json:"mem_free,omitempty"`
+	// disk of the node data dir in MB
+	DiskTotal uint64 `protobuf:"varint,6,opt,name=disk_total,json=diskTotal,proto3" json:"disk_total,omitempty"`
+	DiskFree  uint64 `protobuf:"varint,7,opt,name=disk_free,json=diskFree,proto3" json:"disk_free,omitempty"`
+}
+
+func (x *HardwareProfile) Reset() {
+	*x = HardwareProfile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_alive_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HardwareProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HardwareProfile) ProtoMessage() {}
+
+func (x *HardwareProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_alive_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HardwareProfile.ProtoReflect.Descriptor instead.
+func (*HardwareProfile) Descriptor() ([]byte, []int) {
+	return file_relay_proto_alive_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HardwareProfile) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *HardwareProfile) GetCpu() *CpuProfile {
+	if x != nil {
+		return x.Cpu
+	}
+	return nil
+}
+
+func (x *HardwareProfile) GetGpus() []*GpuProfile {
+	if x != nil {
+		return x.Gpus
+	}
+	return nil
+}
+
+func (x *HardwareProfile) GetMemTotal() uint64 {
+	if x != nil {
+		return x.MemTotal
+	}
+	return 0
+}
+
+func (x *HardwareProfile) GetMemFree() uint64 {
+	if x != nil {
+		return x.MemFree
+	}
+	return 0
+}
+
+func (x *HardwareProfile) GetDiskTotal() uint64 {
+	if x != nil {
+		return x.DiskTotal
+	}
+	return 0
+}
+
+func (x *HardwareProfile) GetDiskFree() uint64 {
+	if x != nil {
+		return x.DiskFree
+	}
+	return 0
+}
+
+type CpuProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vendor string `protobuf:"bytes,1,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	Model  string `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	// physical cores
+	Cores uint32 `protobuf:"varint,3,opt,name=cores,proto3" json:"cores,omitempty"`
+	// logical cores
+	Threads uint32  `protobuf:"varint,4,opt,name=threads,proto3" json:"threads,omitempty"`
+	Mhz     float64 `protobuf:"fixed64,5,opt,name=mhz,proto3" json:"mhz,omitempty"`
+}
+
+func (x *CpuProfile) Reset() {
+	*x = CpuProfile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_alive_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CpuProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CpuProfile) ProtoMessage() {}
+
+func (x *CpuProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_alive_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CpuProfile.ProtoReflect.Descriptor instead.
+func (*CpuProfile) Descriptor() ([]byte, []int) {
+	return file_relay_proto_alive_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CpuProfile) GetVendor() string {
+	if x != nil {
+		return x.Vendor
+	}
+	return ""
+}
+
+func (x *CpuProfile) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CpuProfile) GetCores() uint32 {
+	if x != nil {
+		return x.Cores
+	}
+	return 0
+}
+
+func (x *CpuProfile) GetThreads() uint32 {
+	if x != nil {
+		return x.Threads
+	}
+	return 0
+}
+
+func (x *CpuProfile) GetMhz() float64 {
+	if x != nil {
+		return x.Mhz
+	}
+	return 0
+}
+
+type GpuProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vendor string `protobuf:"bytes,1,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	Model  string `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	// video memory in MB
+	Vram   uint64 `protobuf:"varint,3,opt,name=vram,proto3" json:"vram,omitempty"`
+	Driver string `protobuf:"bytes,4,opt,name=driver,proto3" json:"driver,omitempty"`
+	// cuda compute capability of the nvidia gpus
+	ComputeCapability string `protobuf:"bytes,5,opt,name=compute_capability,json=computeCapability,proto3" json:"compute_capability,omitempty"`
+}
+
+func (x *GpuProfile) Reset() {
+	*x = GpuProfile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_alive_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GpuProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GpuProfile) ProtoMessage() {}
+
+func (x *GpuProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_alive_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GpuProfile.ProtoReflect.Descriptor instead.
+func (*GpuProfile) Descriptor() ([]byte, []int) {
+	return file_relay_proto_alive_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GpuProfile) GetVendor() string {
+	if x != nil {
+		return x.Vendor
+	}
+	return ""
+}
+
+func (x *GpuProfile) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *GpuProfile) GetVram() uint64 {
+	if x != nil {
+		return x.Vram
+	}
+	return 0
+}
+
+func (x *GpuProfile) GetDriver() string {
+	if x != nil {
+		return x.Driver
+	}
+	return ""
+}
+
+func (x *GpuProfile) GetComputeCapability() string {
+	if x != nil {
+		return x.ComputeCapability
+	}
+	return ""
+}
+
 type AliveStatusResp struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -196,7 +502,7 @@ type AliveStatusResp struct {
 func (x *AliveStatusResp) Reset() {
 	*x = AliveStatusResp{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_relay_proto_alive_proto_msgTypes[1]
+		mi := &file_relay_proto_alive_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -209,7 +515,7 @@ func (x *AliveStatusResp) String() string {
 func (*AliveStatusResp) ProtoMessage() {}
 
 func (x *AliveStatusResp) ProtoReflect() protoreflect.Message {
-	mi := &file_relay_proto_alive_proto_msgTypes[1]
+	mi := &file_relay_proto_alive_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -222,7 +528,7 @@ func (x *AliveStatusResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AliveStatusResp.ProtoReflect.Descriptor instead.
 func (*AliveStatusResp) Descriptor() ([]byte, []int) {
-	return file_relay_proto_alive_proto_rawDescGZIP(), []int{1}
+	return file_relay_proto_alive_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *AliveStatusResp) GetSuccess() bool {
@@ -243,7 +549,7 @@ var File_relay_proto_alive_proto protoreflect.FileDescriptor
 
 var file_relay_proto_alive_proto_rawDesc = []byte{
 	0x0a, 0x17, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x6c,
-	0x69, 0x76, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x76, 0x31, 0x22, 0x91, 0x03,
+	0x69, 0x76, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x76, 0x31, 0x22, 0xa1, 0x04,
 	0x0a, 0x0b, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a,
 	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
 	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x75, 0x70, 0x5f, 0x74, 0x69, 0x6d,
@@ -269,16 +575,57 @@ var file_relay_proto_alive_proto_rawDesc = []byte{
 	0x67, 0x70, 0x75, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
 	0x67, 0x70, 0x75, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
 	0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x22, 0x49, 0x0a, 0x0f, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x1c,
-	0x0a, 0x09, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x32, 0x36, 0x0a, 0x05,
-	0x41, 0x6c, 0x69, 0x76, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x0f,
-	0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x1a,
-	0x13, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x42, 0x0e, 0x5a, 0x0c, 0x2f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2f, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65,
+	0x71, 0x18, 0x10, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x1c, 0x0a, 0x09,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x68, 0x61,
+	0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x76,
+	0x31, 0x2e, 0x48, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x52, 0x08, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70,
+	0x72, 0x69, 0x63, 0x65, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63,
+	0x65, 0x22, 0xe5, 0x01, 0x0a, 0x0f, 0x48, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x50, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x20, 0x0a, 0x03, 0x63, 0x70, 0x75, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x70, 0x75, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x03, 0x63, 0x70,
+	0x75, 0x12, 0x22, 0x0a, 0x04, 0x67, 0x70, 0x75, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x70, 0x75, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52,
+	0x04, 0x67, 0x70, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x6d, 0x5f, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6d, 0x65, 0x6d, 0x54, 0x6f, 0x74,
+	0x61, 0x6c, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x65, 0x6d, 0x5f, 0x66, 0x72, 0x65, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6d, 0x65, 0x6d, 0x46, 0x72, 0x65, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x64, 0x69, 0x73, 0x6b, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x09, 0x64, 0x69, 0x73, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x1b, 0x0a, 0x09,
+	0x64, 0x69, 0x73, 0x6b, 0x5f, 0x66, 0x72, 0x65, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x08, 0x64, 0x69, 0x73, 0x6b, 0x46, 0x72, 0x65, 0x65, 0x22, 0x7c, 0x0a, 0x0a, 0x43, 0x70, 0x75,
+	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x12,
+	0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x74,
+	0x68, 0x72, 0x65, 0x61, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x68,
+	0x72, 0x65, 0x61, 0x64, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x68, 0x7a, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x03, 0x6d, 0x68, 0x7a, 0x22, 0x95, 0x01, 0x0a, 0x0a, 0x47, 0x70, 0x75, 0x50,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x12, 0x14,
+	0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x76, 0x72, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x04, 0x76, 0x72, 0x61, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x72, 0x69, 0x76,
+	0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72,
+	0x12, 0x2d, 0x0a, 0x12, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x5f, 0x63, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x63, 0x6f,
+	0x6d, 0x70, 0x75, 0x74, 0x65, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x22,
+	0x49, 0x0a, 0x0f, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x1c, 0x0a, 0x09,
+	0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x32, 0x36, 0x0a, 0x05, 0x41, 0x6c,
+	0x69, 0x76, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x0f, 0x2e, 0x76,
+	0x31, 0x2e, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x1a, 0x13, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x42, 0x0e, 0x5a, 0x0c, 0x2f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -293,19 +640,25 @@ func file_relay_proto_alive_proto_rawDescGZIP() []byte {
 	return file_relay_proto_alive_proto_rawDescData
 }
 
-var file_relay_proto_alive_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_relay_proto_alive_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_relay_proto_alive_proto_goTypes = []interface{}{
 	(*AliveStatus)(nil),     // 0: v1.AliveStatus
-	(*AliveStatusResp)(nil), // 1: v1.AliveStatusResp
+	(*HardwareProfile)(nil), // 1: v1.HardwareProfile
+	(*CpuProfile)(nil),      // 2: v1.CpuProfile
+	(*GpuProfile)(nil),      // 3: v1.GpuProfile
+	(*AliveStatusResp)(nil), // 4: v1.AliveStatusResp
 }
 var file_relay_proto_alive_proto_depIdxs = []int32{
-	0, // 0: v1.Alive.Hello:input_type -> v1.AliveStatus
-	1, // 1: v1.Alive.Hello:output_type -> v1.AliveStatusResp
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: v1.AliveStatus.hardware:type_name -> v1.HardwareProfile
+	2, // 1: v1.HardwareProfile.cpu:type_name -> v1.CpuProfile
+	3, // 2: v1.HardwareProfile.gpus:type_name -> v1.GpuProfile
+	0, // 3: v1.Alive.Hello:input_type -> v1.AliveStatus
+	4, // 4: v1.Alive.Hello:output_type -> v1.AliveStatusResp
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_relay_proto_alive_proto_init() }
@@ -327,6 +680,42 @@ func file_relay_proto_alive_proto_init() {
 			}
 		}
 		file_relay_proto_alive_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HardwareProfile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_alive_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CpuProfile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_alive_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GpuProfile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_alive_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AliveStatusResp); i {
 			case 0:
 				return &v.state
@@ -345,7 +734,7 @@ func file_relay_proto_alive_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_relay_proto_alive_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -48,8 +48,7 @@ type RelayConnInfo struct {
 type RelayClient struct {
 	logger hclog.Logger // the logger
 
-	subscription application.Subscription // reference to the application subscription
-	closeCh      chan struct{}            // the channel used for closing the RelayClient
+	closeCh chan struct{} // the channel used for closing the RelayClient
 
 	host host.Host // the libp2p host reference
 
@@ -64,7 +63,10 @@ type RelayClient struct {
 
 	relaynodes *relaynodesWrapper // reference of all relaynodes for the node
 
-	application *application.Application // reference of application
+	applications     map[string]*application.Application // latest status of the applications served, by name
+	applicationsLock sync.Mutex                          // lock for the applications map
+
+	statusSeq application.AppStatusSeq // sequence numbers of the app statuses sent to the relays
 }
 
 // RelayPeerInfo holds the relay information about the peer
@@ -400,7 +402,7 @@ func setupLibp2pKey(secretsManager secrets.SecretsManager) (crypto.PrivKey, erro
 }
 
 // setupAlive Sets up the live service for the node
-func (s *RelayClient) StartAlive(subscription application.Subscription) error {
+func (s *RelayClient) StartAlive(subscriptions ...application.Subscription) error {
 	// Set up a fresh routing table
 	//keyID := kb.ConvertPeerID(s.host.ID())
 	//
@@ -434,8 +436,10 @@ func (s *RelayClient) StartAlive(subscription application.Subscription) error {
 	// and instantiates connections to them
 	s.ConnectToBootnodes(s.relaynodes.getRelaynodes())
 
-	// Start application event update process
-	go s.startApplicationEventProcess(subscription)
+	// Start application event update processes
+	for _, subscription := range subscriptions {
+		go s.startApplicationEventProcess(subscription)
+	}
 
 	// Start the alive job
 	go s.startAliveService()
@@ -627,7 +631,8 @@ func (s *RelayClient) sayHello(
 	peerID peer.ID,
 ) (bool, string, error) {
 
-	if s.application == nil {
+	applications := s.getApplications()
+	if len(applications) == 0 {
 		return false, "", errors.New("no application info")
 	}
 
@@ -643,25 +648,45 @@ func (s *RelayClient) sayHello(
 		relay = fmt.Sprintf("%s/p2p/%s", relayPeerInfo.Info.Info.Addrs[0].String(), relayPeerInfo.Info.Info.ID.String())
 	}
 
-	resp, err := clt.Hello(
-		context.Background(),
-		&proto.AliveStatus{
-			Name:         s.application.Name,
-			StartupTime:  s.application.StartupTime,
-			Uptime:       s.application.Uptime,
+	// the relay publishes an app status per application
+	var resp *proto.AliveStatusResp
+
+	for _, app := range applications {
+		status := &proto.AliveStatus{
+			Name:         app.Name,
+			StartupTime:  app.StartupTime,
+			Uptime:       app.Uptime,
+			GuageHeight:  app.GuageHeight,
+			GuageMax:     app.GuageMax,
 			Relay:        relay,
-			AppOrigin:    s.application.AppOrigin,
-			Mac:          s.application.Mac,
-			CpuInfo:      s.application.CpuInfo,
-			GpuInfo:      s.application.GpuInfo,
-			MemInfo:      s.application.MemInfo,
-			ModelHash:    s.application.ModelHash,
-			AveragePower: s.application.AveragePower,
-			Version:      s.application.Version,
-		},
-	)
-	if err != nil {
-		return false, "", err
+			AppOrigin:    app.AppOrigin,
+			Mac:          app.Mac,
+			CpuInfo:      app.CpuInfo,
+			GpuInfo:      app.GpuInfo,
+			MemInfo:      app.MemInfo,
+			ModelHash:    app.ModelHash,
+			AveragePower: app.AveragePower,
+			Version:      app.Version,
+			PubKey:       app.PubKey,
+			Seq:          s.statusSeq.Next(),
+			Hardware:     toAliveHardware(application.HardwareToProto(app.Hardware)),
+			Price:        application.EncodePrice(app.Price),
+		}
+
+		// the app status published by the relay is signed by the node key
+		appStatus := toAppStatus(s.host.ID(), status, "")
+		if err := application.SignAppStatus(s.host.Peerstore().PrivKey(s.host.ID()), appStatus); err != nil {
+			return false, "", fmt.Errorf("unable to sign app status, %w", err)
+		}
+
+		status.Signature = appStatus.Signature
+
+		var err error
+
+		resp, err = clt.Hello(context.Background(), status)
+		if err != nil {
+			return false, "", err
+		}
 	}
 
 	if closeErr := s.CloseProtocolStream(EdgeAliveProto, peerID); closeErr != nil {
@@ -709,7 +734,7 @@ func (s *RelayClient) keepAliveToBootnodes() {
 			return
 		}
 
-		for s.application == nil {
+		for len(s.getApplications()) == 0 {
 			time.Sleep(1 * time.Second)
 		}
 		success, discovery, err := s.sayHello(bootnode.ID)
@@ -740,26 +765,39 @@ func (s *RelayClient) disconnectFromPeer(peer peer.ID, reason string) {
 }
 
 // startApplicationEventProcess starts application event subscription
-func (m *RelayClient) startApplicationEventProcess(subscrption application.Subscription) {
-	m.subscription = subscrption
+func (m *RelayClient) startApplicationEventProcess(subscription application.Subscription) {
 	for {
 		var event *application.Event
 
 		select {
 		case <-m.closeCh:
 			return
-		case event = <-m.subscription.GetEventCh():
+		case event = <-subscription.GetEventCh():
 		}
 
 		if l := len(event.NewApp); l > 0 {
 			latest := event.NewApp[l-1]
 
-			m.application = latest
-
+			m.applicationsLock.Lock()
+			m.applications[latest.Name] = latest
+			m.applicationsLock.Unlock()
 		}
 	}
 }
 
+// getApplications returns the latest status of the applications served
+func (m *RelayClient) getApplications() []*application.Application {
+	m.applicationsLock.Lock()
+	defer m.applicationsLock.Unlock()
+
+	applications := make([]*application.Application, 0, len(m.applications))
+	for _, app := range m.applications {
+		applications = append(applications, app)
+	}
+
+	return applications
+}
+
 // NewRelayClient returns a new instance of the relay client
 func NewRelayClient(logger hclog.Logger, config *emcNetwork.Config, relayOn bool) (*RelayClient, error) {
 	logger = logger.Named("relay-client")
@@ -805,12 +843,13 @@ func NewRelayClient(logger hclog.Logger, config *emcNetwork.Config, relayOn bool
 	}
 
 	clt := &RelayClient{
-		logger:     logger,
-		host:       edgeNodeHost,
-		closeCh:    make(chan struct{}),
-		protocols:  map[string]Protocol{},
-		relayPeers: make(map[peer.ID]*RelayPeerInfo),
-		peers:      make(map[peer.ID]*PeerConnInfo),
+		logger:       logger,
+		host:         edgeNodeHost,
+		closeCh:      make(chan struct{}),
+		protocols:    map[string]Protocol{},
+		relayPeers:   make(map[peer.ID]*RelayPeerInfo),
+		peers:        make(map[peer.ID]*PeerConnInfo),
+		applications: make(map[string]*application.Application),
 		relaynodes: &relaynodesWrapper{
 			relaynodeArr:       make([]*peer.AddrInfo, 0),
 			relaynodesMap:      make(map[peer.ID]*peer.AddrInfo),
@@ -147,6 +147,15 @@ func (s *RelayServer) registerAliveService(aliveService *AliveService) {
 	s.RegisterProtocol(EdgeAliveProto, grpcStream)
 }
 
+// DisconnectFromPeer disconnects the relay server from the specified peer
+func (s *RelayServer) DisconnectFromPeer(peerID peer.ID, reason string) {
+	s.logger.Info(fmt.Sprintf("Closing connection to peer [%s] for reason [%s]", peerID.String(), reason))
+
+	if err := s.host.Network().ClosePeer(peerID); err != nil {
+		s.logger.Error(fmt.Sprintf("Unable to gracefully close peer connection, %v", err))
+	}
+}
+
 // NewRelayServer returns a new instance of the relay server
 func NewRelayServer(logger hclog.Logger, secretsManager secrets.SecretsManager, relayListenAddr multiaddr.Multiaddr, config *emcNetwork.Config, RelayDiscovery bool) (*RelayServer, error) {
 	logger = logger.Named("relay-server")
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"github.com/emc-protocol/edge-matrix/application"
 	"github.com/emc-protocol/edge-matrix/chain"
 	"net"
 
@@ -25,7 +26,18 @@ type Config struct {
 	PriceLimit         uint64
 	MaxAccountEnqueued uint64
 	MaxSlots           uint64
-	BlockTime          uint64
+	// MaxEdgeCallsPerPeer is the max number of in-flight edge calls routed to an app peer
+	MaxEdgeCallsPerPeer uint64
+	// EdgeCallPolicy selects the app peer of edge calls without a peer id
+	EdgeCallPolicy string
+	// TeleJournal journals the locally submitted telegrams, compacted every TeleRejournal seconds
+	TeleJournal   bool
+	TeleRejournal uint64
+	// PriceBump is the minimum gas price bump in percent of a replacement telegram
+	PriceBump uint64
+	// TeleLifetime is how long in seconds a telegram stays enqueued
+	TeleLifetime uint64
+	BlockTime    uint64
 
 	Telemetry   *Telemetry
 	Network     *network.Config
@@ -49,10 +61,16 @@ type Config struct {
 
 	NumBlockConfirmations uint64
 
-	AppName     string
-	AppUrl      string
-	AppOrigin   string
-	RunningMode string
+	// Apps are the apps served by the node, the first one is the default app
+	Apps []*application.AppConfig
+	// AppHeaderAllowlist are the headers passed between edge call callers and the app
+	AppHeaderAllowlist []string
+	// AppAccess restricts the callers served by the app
+	AppAccess *application.AccessConfig
+	// AppValidateResp validates the app responses against the app idl
+	AppValidateResp bool
+	AppOrigin       string
+	RunningMode     string
 
 	EmcHost string
 }
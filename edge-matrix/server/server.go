@@ -11,6 +11,7 @@ import (
 	"github.com/emc-protocol/edge-matrix/consensus"
 	"github.com/emc-protocol/edge-matrix/crypto"
 	"github.com/emc-protocol/edge-matrix/helper/progress"
+	"github.com/emc-protocol/edge-matrix/helper/rpc"
 	"github.com/emc-protocol/edge-matrix/miner"
 	minerProto "github.com/emc-protocol/edge-matrix/miner/proto"
 	"github.com/emc-protocol/edge-matrix/relay"
@@ -91,6 +92,9 @@ type Server struct {
 	// application syncer Client
 	syncAppPeerClient application.SyncAppPeerClient
 
+	// proof of compute challenges of the app peers
+	pocScheduler *application.PocScheduler
+
 	// telegram pool
 	telepool *telepool.TelegramPool
 
@@ -259,8 +263,11 @@ func NewServer(config *Config) (*Server, error) {
 			m.network,
 			m.edgeNetwork,
 			&telepool.Config{
-				MaxSlots:           m.config.MaxSlots,
-				MaxAccountEnqueued: m.config.MaxAccountEnqueued,
+				MaxSlots:            m.config.MaxSlots,
+				MaxAccountEnqueued:  m.config.MaxAccountEnqueued,
+				MaxEdgeCallsPerPeer: m.config.MaxEdgeCallsPerPeer,
+				PriceBump:           m.config.PriceBump,
+				Lifetime:            time.Duration(m.config.TeleLifetime) * time.Second,
 			},
 			m.config.Chain.TeleVersion,
 		)
@@ -269,6 +276,30 @@ func NewServer(config *Config) (*Server, error) {
 		}
 
 		m.telepool.SetSigner(signer)
+		m.telepool.SetResponseSigner(application.NewEIP155Signer(chain.AllForksEnabled.At(0), uint64(m.config.Chain.Params.ChainID)))
+
+		policy, err := application.NewPeerSelectionPolicy(m.config.EdgeCallPolicy, m.telepool.EdgeCallLatency)
+		if err != nil {
+			return nil, err
+		}
+
+		m.telepool.SetPeerSelectionPolicy(policy)
+
+		jobsPath := ""
+		if m.config.DataDir != "" {
+			jobsPath = filepath.Join(m.config.DataDir, "edgejobs")
+		}
+
+		if err := m.telepool.SetEdgeJobStore(jobsPath); err != nil {
+			return nil, err
+		}
+
+		if m.config.TeleJournal && m.config.DataDir != "" {
+			m.telepool.SetJournal(
+				filepath.Join(m.config.DataDir, "telepool.journal"),
+				time.Duration(m.config.TeleRejournal)*time.Second,
+			)
+		}
 
 		// Setup consensus
 		if err := m.setupConsensus(); err != nil {
@@ -372,16 +403,62 @@ func NewServer(config *Config) (*Server, error) {
 			}
 		}
 
-		endpoint, err := application.NewApplicationEndpoint(m.logger, key, endpointHost, m.config.AppName, m.config.AppUrl, m.blockchain, minerAgent, m.runningMode == RunningModeEdge)
+		appServer, err := application.NewAppServer(m.logger, endpointHost)
 		if err != nil {
 			return nil, err
 		}
 
-		endpoint.SetSigner(application.NewEIP155Signer(chain.AllForksEnabled.At(0), uint64(m.config.Chain.Params.ChainID)))
+		blobStore, err := application.NewBlobStore(filepath.Join(m.config.DataDir, "blobs"), 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := application.NewBlobServer(m.logger, endpointHost, blobStore); err != nil {
+			return nil, err
+		}
+
+		subscriptions := make([]application.Subscription, 0, len(m.config.Apps))
+
+		var providerEndpoint *application.Endpoint
+
+		for _, app := range m.config.Apps {
+			endpoint, err := application.NewApplicationEndpoint(m.logger, key, endpointHost, app.Name, app.Url, m.blockchain, minerAgent, m.runningMode == RunningModeEdge)
+			if err != nil {
+				return nil, err
+			}
+
+			endpoint.SetSigner(application.NewEIP155Signer(chain.AllForksEnabled.At(0), uint64(m.config.Chain.Params.ChainID)))
+			if len(m.config.AppHeaderAllowlist) > 0 {
+				endpoint.SetHeaderAllowlist(m.config.AppHeaderAllowlist)
+			}
+
+			endpoint.SetCapacity(app.MaxConcurrency, app.MaxQueue)
+			endpoint.SetPrice(app.Price)
+			endpoint.SetTeleSigner(signer)
+
+			if m.config.AppAccess != nil {
+				endpoint.SetAccessConfig(m.config.AppAccess)
+			}
+
+			endpoint.SetResponseValidation(m.config.AppValidateResp)
+			endpoint.SetBlobStore(blobStore)
+			endpoint.SetDataDir(m.config.DataDir)
+
+			if err := appServer.AddEndpoint(endpoint); err != nil {
+				return nil, err
+			}
+
+			subscriptions = append(subscriptions, endpoint.SubscribeEvents())
+
+			// the endpoints share the peer and key, registering one registers them all
+			if providerEndpoint == nil {
+				providerEndpoint = endpoint
+			}
+		}
 
 		if m.runningMode == RunningModeEdge {
 			// keep edge peer alive
-			err := m.relayClient.StartAlive(endpoint.SubscribeEvents())
+			err := m.relayClient.StartAlive(subscriptions...)
 			if err != nil {
 				return nil, err
 			}
@@ -389,21 +466,43 @@ func NewServer(config *Config) (*Server, error) {
 
 		if m.runningMode == RunningModeFull {
 			// setup app status syncer
-			syncAppclient := application.NewSyncAppPeerClient(m.logger, m.edgeNetwork, minerAgent, m.edgeNetwork.GetHost(), endpoint)
+			syncAppclient := application.NewSyncAppPeerClient(m.logger, m.edgeNetwork, minerAgent, m.edgeNetwork.GetHost(), appServer)
 			m.syncAppPeerClient = syncAppclient
 
 			syncer := application.NewSyncer(
 				m.logger,
 				syncAppclient,
-				application.NewSyncAppPeerService(m.logger, m.edgeNetwork, endpoint, m.blockchain, minerAgent),
+				application.NewSyncAppPeerService(m.logger, m.edgeNetwork, appServer, m.blockchain, minerAgent),
 				m.edgeNetwork.GetHost(),
 				m.blockchain)
+
+			peersPath := ""
+			if m.config.DataDir != "" {
+				peersPath = filepath.Join(m.config.DataDir, "apppeers")
+			}
+
+			if err := syncer.SetPeerStore(peersPath); err != nil {
+				return nil, err
+			}
+
 			// start app status syncer
 			err = syncer.Start(true)
 			if err != nil {
 				return nil, err
 			}
 
+			// challenge the app peers with proofs of compute, routing to the failing ones last
+			m.pocScheduler = application.NewPocScheduler(
+				m.logger,
+				m.edgeNetwork.GetHost().ID().String(),
+				syncAppclient,
+				syncer,
+				m.blockchain,
+				minerAgent)
+			m.pocScheduler.SetMatMulChallenger(m.telepool)
+			syncer.SetPocHistory(m.pocScheduler.History())
+			m.pocScheduler.Start()
+
 			// setup and start jsonrpc server
 			if err := m.setupJSONRPC(); err != nil {
 				return nil, err
@@ -460,11 +559,28 @@ func NewServer(config *Config) (*Server, error) {
 			m.telepool.Start()
 
 		}
+
+		if providerEndpoint != nil {
+			go m.registerProvider(providerEndpoint)
+		}
 	}
 
 	return m, nil
 }
 
+// registerProvider registers the endpoint address as the provider of its peer,
+// through the local json-rpc server on full nodes
+func (s *Server) registerProvider(endpoint *application.Endpoint) {
+	rpcHost := ""
+	if s.runningMode == RunningModeFull && s.config.JSONRPC.JSONRPCAddr != nil {
+		rpcHost = "http://" + s.config.JSONRPC.JSONRPCAddr.String()
+	}
+
+	if err := endpoint.RegisterProvider(rpc.NewJsonRpcClient(rpcHost), uint64(s.config.Chain.Params.ChainID)); err != nil {
+		s.logger.Warn("unable to register the endpoint provider", "err", err)
+	}
+}
+
 //func (s *Server) restoreChain() error {
 //	if s.config.RestoreFile == nil {
 //		return nil
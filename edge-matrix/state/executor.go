@@ -190,6 +190,10 @@ func (e *Executor) BeginTxn(
 		PostHook:    e.PostHook,
 	}
 
+	if forkConfig.EIP1559 {
+		txn.baseFee = new(big.Int).SetUint64(header.BaseFee)
+	}
+
 	return txn, nil
 }
 
@@ -205,6 +209,8 @@ type Transition struct {
 	getHash GetHashByNumber
 	ctx     runtime.TxContext
 	gasPool uint64
+	// baseFee is the base fee of the block, nil before the EIP1559 fork
+	baseFee *big.Int
 
 	// result
 	receipts []*types.Receipt
@@ -240,7 +246,7 @@ var emptyFrom = types.Address{}
 func (t *Transition) WriteFailedReceipt(txn *types.Telegram) error {
 	signer := crypto.NewSigner(t.config, uint64(t.ctx.ChainID))
 
-	if txn.From == emptyFrom && txn.Type == types.LegacyTx {
+	if txn.From == emptyFrom && txn.Type != types.StateTx {
 		// Decrypt the from address
 		from, err := signer.Sender(txn)
 		if err != nil {
@@ -272,7 +278,7 @@ func (t *Transition) WriteFailedReceipt(txn *types.Telegram) error {
 func (t *Transition) Write(tele *types.Telegram) error {
 	var err error
 
-	if tele.From == emptyFrom && tele.Type == types.LegacyTx {
+	if tele.From == emptyFrom && tele.Type != types.StateTx {
 		// Decrypt the from address
 		signer := crypto.NewSigner(t.config, uint64(t.ctx.ChainID))
 
@@ -374,9 +380,17 @@ func (t *Transition) ContextPtr() *runtime.TxContext {
 }
 
 func (t *Transition) subGasLimitPrice(msg *types.Telegram) error {
+	gas := new(big.Int).SetUint64(msg.Gas)
+
+	// the balance must cover the fee cap of dynamic fee telegrams and their value,
+	// even if they only pay the base fee plus their tip
+	if msg.Type == types.DynamicFeeTx && t.state.GetBalance(msg.From).Cmp(msg.Cost()) < 0 {
+		return ErrNotEnoughFundsForGas
+	}
+
 	// deduct the upfront max gas cost
-	upfrontGasCost := new(big.Int).Set(msg.GasPrice)
-	upfrontGasCost.Mul(upfrontGasCost, new(big.Int).SetUint64(msg.Gas))
+	upfrontGasCost := msg.EffectiveGasPrice(t.baseFee)
+	upfrontGasCost.Mul(upfrontGasCost, gas)
 
 	if err := t.state.SubBalance(msg.From, upfrontGasCost); err != nil {
 		if errors.Is(err, runtime.ErrNotEnoughFunds) {
@@ -409,6 +423,10 @@ var (
 	ErrIntrinsicGasOverflow  = fmt.Errorf("overflow in intrinsic gas calculation")
 	ErrNotEnoughIntrinsicGas = fmt.Errorf("not enough gas supplied for intrinsic gas costs")
 	ErrNotEnoughFunds        = fmt.Errorf("not enough funds for transfer with given value")
+	ErrTxTypeNotSupported    = fmt.Errorf("telegram type not supported")
+	ErrTipAboveFeeCap        = fmt.Errorf("max priority fee per gas higher than max fee per gas")
+	ErrFeeCapTooLow          = fmt.Errorf("max fee per gas less than block base fee")
+	ErrGasPriceNotAllowed    = fmt.Errorf("gas price set on a dynamic fee telegram")
 )
 
 type TransitionApplicationError struct {
@@ -474,12 +492,13 @@ func (t *Transition) apply(tele *types.Telegram) (*runtime.ExecutionResult, erro
 		return nil, NewTransitionApplicationError(ErrNotEnoughIntrinsicGas, false)
 	}
 
-	gasPrice := new(big.Int).Set(tele.GasPrice)
+	gasPrice := tele.EffectiveGasPrice(t.baseFee)
 	value := new(big.Int).Set(tele.Value)
 
 	// set the specific transaction fields in the context
 	t.ctx.GasPrice = types.BytesToHash(gasPrice.Bytes())
 	t.ctx.Origin = tele.From
+	t.ctx.EdgeCall = t.edgeCallAttestation(tele)
 
 	var result *runtime.ExecutionResult
 	if tele.IsContractCreation() {
@@ -497,12 +516,19 @@ func (t *Transition) apply(tele *types.Telegram) (*runtime.ExecutionResult, erro
 	}
 
 	// refund the sender
-	remaining := new(big.Int).Mul(new(big.Int).SetUint64(result.GasLeft), tele.GasPrice)
+	remaining := new(big.Int).Mul(new(big.Int).SetUint64(result.GasLeft), gasPrice)
 	t.state.AddBalance(tele.From, remaining)
 
-	// pay the coinbase
-	coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), tele.GasPrice)
-	t.state.AddBalance(t.ctx.Coinbase, coinbaseFee)
+	// pay the coinbase, the base fee being burnt
+	tip := gasPrice
+	if t.baseFee != nil {
+		tip = new(big.Int).Sub(gasPrice, t.baseFee)
+	}
+
+	if tip.Sign() > 0 {
+		coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), tip)
+		t.state.AddBalance(t.ctx.Coinbase, coinbaseFee)
+	}
 
 	// return gas to the pool
 	t.addGasPool(result.GasLeft)
@@ -510,6 +536,32 @@ func (t *Transition) apply(tele *types.Telegram) (*runtime.ExecutionResult, erro
 	return result, nil
 }
 
+// edgeCallAttestation returns the provider attestation of the telegram,
+// nil if it has none or the EdgeCallAttestation fork is not active
+func (t *Transition) edgeCallAttestation(tele *types.Telegram) *runtime.EdgeCallAttestation {
+	if !t.config.EdgeCallAttestation || tele.RespFrom == types.ZeroAddress {
+		return nil
+	}
+
+	attestation := &runtime.EdgeCallAttestation{
+		Caller:        tele.From,
+		Nonce:         tele.Nonce,
+		RespDigest:    tele.RespDigest,
+		RespStatus:    tele.RespStatus,
+		RespProvider:  tele.RespProvider,
+		RespTimestamp: tele.RespTimestamp,
+		RespHash:      tele.RespHash,
+		RespFrom:      tele.RespFrom,
+	}
+
+	signer := crypto.NewEIP155Signer(t.config, uint64(t.ctx.ChainID))
+	if provider, err := signer.Provider(tele); err == nil {
+		attestation.Provider = provider
+	}
+
+	return attestation
+}
+
 func (t *Transition) Create2(
 	caller types.Address,
 	code []byte,
@@ -763,6 +815,10 @@ func (t *Transition) GetNonce(addr types.Address) uint64 {
 	return t.state.GetNonce(addr)
 }
 
+func (t *Transition) IncrNonce(addr types.Address) {
+	t.state.IncrNonce(addr)
+}
+
 func (t *Transition) Selfdestruct(addr types.Address, beneficiary types.Address) {
 	if !t.state.HasSuicided(addr) {
 		t.state.AddRefund(24000)
@@ -871,14 +927,20 @@ func TransactionGasCost(msg *types.Telegram, isHomestead, isIstanbul bool) (uint
 // checkAndProcessLegacyTx - first check if this message satisfies all consensus rules before
 // applying the message. The rules include these clauses:
 // 1. the nonce of the message caller is correct
-// 2. caller has enough balance to cover transaction fee(gaslimit * gasprice)
+// 2. the fee cap of the message covers the base fee of the block
+// 3. caller has enough balance to cover transaction fee(gaslimit * gasprice)
 func checkAndProcessLegacyTx(msg *types.Telegram, t *Transition) error {
 	// 1. the nonce of the message caller is correct
 	if err := t.nonceCheck(msg); err != nil {
 		return NewTransitionApplicationError(err, true)
 	}
 
-	// 2. caller has enough balance to cover transaction fee(gaslimit * gasprice)
+	// 2. the fees of the message cover the base fee of the block
+	if err := t.checkDynamicFee(msg); err != nil {
+		return err
+	}
+
+	// 3. caller has enough balance to cover transaction fee(gaslimit * gasprice)
 	if err := t.subGasLimitPrice(msg); err != nil {
 		return NewTransitionApplicationError(err, true)
 	}
@@ -886,6 +948,30 @@ func checkAndProcessLegacyTx(msg *types.Telegram, t *Transition) error {
 	return nil
 }
 
+// checkDynamicFee checks the fee caps of the message against the base fee of the block.
+// Messages under the base fee are recoverable, as it may decrease in the next blocks.
+func (t *Transition) checkDynamicFee(msg *types.Telegram) error {
+	if msg.Type == types.DynamicFeeTx {
+		if !t.config.EIP1559 {
+			return NewTransitionApplicationError(ErrTxTypeNotSupported, false)
+		}
+
+		if msg.GetGasTipCap().Cmp(msg.GetGasFeeCap()) > 0 {
+			return NewTransitionApplicationError(ErrTipAboveFeeCap, false)
+		}
+
+		if msg.GasPrice != nil && msg.GasPrice.Sign() != 0 {
+			return NewTransitionApplicationError(ErrGasPriceNotAllowed, false)
+		}
+	}
+
+	if t.baseFee != nil && msg.GetGasFeeCap().Cmp(t.baseFee) < 0 {
+		return NewTransitionApplicationError(ErrFeeCapTooLow, true)
+	}
+
+	return nil
+}
+
 func checkAndProcessStateTx(msg *types.Telegram, t *Transition) error {
 	if msg.GasPrice.Cmp(big.NewInt(0)) != 0 {
 		return NewTransitionApplicationError(
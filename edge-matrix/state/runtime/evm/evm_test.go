@@ -90,6 +90,10 @@ func (m *mockHost) GetNonce(addr types.Address) uint64 {
 	panic("Not implemented in tests")
 }
 
+func (m *mockHost) IncrNonce(addr types.Address) {
+	panic("Not implemented in tests")
+}
+
 func (m *mockHost) Transfer(from types.Address, to types.Address, amount *big.Int) error {
 	panic("Not implemented in tests")
 }
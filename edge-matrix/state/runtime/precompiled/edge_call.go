@@ -1,15 +1,120 @@
 package precompiled
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+
 	"github.com/emc-protocol/edge-matrix/chain"
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/crypto"
+	"github.com/emc-protocol/edge-matrix/helper/keccak"
 	"github.com/emc-protocol/edge-matrix/state/runtime"
 	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/umbracle/fastrlp"
+)
+
+var (
+	ErrInvalidEdgeCallSig       = errors.New("invalid edge call provider signature")
+	ErrEdgeCallBindingMismatch  = errors.New("edge call attestation is bound to another request")
+	ErrUnregisteredEdgeProvider = errors.New("edge call provider is not registered for the peer")
 )
 
+const (
+	// edgeCallGas is the cost of the recovery of the provider, of the payment of the
+	// provider and of the EdgeCall log with its 3 topics and 3 words of data
+	edgeCallGas = 3000 + 9000 + 375 + 3*375 + 3*32*8
+	// the request hash is charged as the KECCAK256 of the input
+	edgeCallHashGas     = 30
+	edgeCallHashWordGas = 6
+)
+
+// EdgeCallEventSig is the topic of the logs of the verified edge calls, indexed by
+// caller and provider, the data being the request and response hashes and the amount paid
+var EdgeCallEventSig = crypto.Keccak256Hash([]byte("EdgeCall(address,address,bytes32,bytes32,uint256)"))
+
+// EdgeCallRefundEventSig is the topic of the logs of the edge calls refunded to their caller,
+// indexed by caller, the data being the request hash and the amount refunded
+var EdgeCallRefundEventSig = crypto.Keccak256Hash([]byte("EdgeCallRefund(address,bytes32,uint256)"))
+
+var edgeCallArenaPool fastrlp.ArenaPool
+
+// edgeCallInput is the part of the edge call input the precompile checks
+type edgeCallInput struct {
+	PeerId   string          `json:"peerId"`
+	Endpoint string          `json:"endpoint"`
+	Input    json.RawMessage `json:"input"`
+}
+
+// EdgeCallRequestHash returns the hash of the edge call request,
+// the keccak256 hash of the endpoint and the compacted input
+func EdgeCallRequestHash(endpoint string, input []byte) types.Hash {
+	compacted := new(bytes.Buffer)
+	if err := json.Compact(compacted, input); err == nil {
+		input = compacted.Bytes()
+	}
+
+	return crypto.Keccak256Hash(append([]byte(endpoint), input...))
+}
+
+// EdgeCallBinding is the part of a bound edge response the EdgeCall precompile checks.
+// The body, headers and blob of the response are committed by RespDigest.
+type EdgeCallBinding struct {
+	RespDigest  types.Hash
+	StatusCode  uint64
+	RequestHash types.Hash
+	Caller      types.Address
+	Nonce       uint64
+	ProviderID  string
+	Timestamp   uint64
+}
+
+// Hash returns the hash the provider signs for the bound edge response
+func (b *EdgeCallBinding) Hash(chainID uint64) types.Hash {
+	a := edgeCallArenaPool.Get()
+
+	v := a.NewArray()
+	v.Set(a.NewBytes(b.RespDigest.Bytes()))
+	v.Set(a.NewUint(b.StatusCode))
+	v.Set(a.NewBytes(b.RequestHash.Bytes()))
+	v.Set(a.NewBytes(b.Caller.Bytes()))
+	v.Set(a.NewUint(b.Nonce))
+	v.Set(a.NewString(b.ProviderID))
+	v.Set(a.NewUint(b.Timestamp))
+
+	// EIP155
+	if chainID != 0 {
+		v.Set(a.NewUint(chainID))
+		v.Set(a.NewUint(0))
+		v.Set(a.NewUint(0))
+	}
+
+	hash := keccak.Keccak256Rlp(nil, v)
+
+	edgeCallArenaPool.Put(a)
+
+	return types.BytesToHash(hash)
+}
+
+// edgeCall verifies the provider attestation of the edge call telegrams: the response hash
+// signed by the provider must be the one of the response bound to the call of the telegram,
+// and the provider recovered from its signature must be the one registered for the peer.
+//
+// The value of the telegram is escrowed in the precompile account by the call, and paid
+// to the provider once its attestation is verified. Calls without attestation, or answered
+// with an app error, are refunded to the caller. Calls with an invalid attestation fail,
+// their value being refunded to the caller with the revert of the call.
+//
+// Before the EdgeCallAttestation fork, edge calls are not verified nor paid.
 type edgeCall struct{}
 
-func (c *edgeCall) gas(input []byte, _ *chain.ForksInTime) uint64 {
-	return 0
+func (c *edgeCall) gas(input []byte, config *chain.ForksInTime) uint64 {
+	if !config.EdgeCallAttestation {
+		return 0
+	}
+
+	return edgeCallGas + baseGasCalc(input, edgeCallHashGas, edgeCallHashWordGas)
 }
 
 func (c *edgeCall) run(input []byte, caller types.Address, host runtime.Host) ([]byte, error) {
@@ -17,19 +122,104 @@ func (c *edgeCall) run(input []byte, caller types.Address, host runtime.Host) ([
 		return abiBoolFalse, runtime.ErrInvalidInputData
 	}
 
-	// check if caller is native token contract
-	//if caller != contracts.NativeTokenContract {
-	//	return abiBoolFalse, runtime.ErrUnauthorizedCaller
-	//}
-	//
-	//from := types.BytesToAddress(input[0:32])
-	//to := types.BytesToAddress(input[32:64])
-	//amount := new(big.Int).SetBytes(input[64:96])
-	//
-	//// state changes
-	//if err := host.Transfer(from, to, amount); err != nil {
-	//	return abiBoolFalse, err
-	//}
+	return abiBoolTrue, nil
+}
+
+func (c *edgeCall) runWithValue(input []byte, caller types.Address, value *big.Int, host runtime.Host) ([]byte, error) {
+	call := &edgeCallInput{}
+	if err := json.Unmarshal(input, call); err != nil {
+		return abiBoolFalse, runtime.ErrInvalidInputData
+	}
+
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	ctx := host.GetTxContext()
+	requestHash := EdgeCallRequestHash(call.Endpoint, call.Input)
+
+	// calls sealed without attestation are refunded
+	attestation := ctx.EdgeCall
+	if attestation == nil || attestation.RespFrom == types.ZeroAddress {
+		return refundEdgeCall(host, caller, requestHash, value)
+	}
+
+	if attestation.Provider != attestation.RespFrom {
+		return abiBoolFalse, ErrInvalidEdgeCallSig
+	}
+
+	// the signed response must answer this call of the telegram sender, calls
+	// routed by target being answered by the peer the router selected
+	binding := &EdgeCallBinding{
+		RespDigest:  attestation.RespDigest,
+		StatusCode:  attestation.RespStatus,
+		RequestHash: requestHash,
+		Caller:      attestation.Caller,
+		Nonce:       attestation.Nonce,
+		ProviderID:  attestation.RespProvider,
+		Timestamp:   attestation.RespTimestamp,
+	}
+
+	if attestation.RespTimestamp == 0 ||
+		(call.PeerId != "" && call.PeerId != attestation.RespProvider) ||
+		binding.Hash(uint64(ctx.ChainID)) != attestation.RespHash {
+		return abiBoolFalse, ErrEdgeCallBindingMismatch
+	}
+
+	if registeredProvider(host, attestation.RespProvider) != attestation.Provider {
+		return abiBoolFalse, ErrUnregisteredEdgeProvider
+	}
+
+	// calls answered with an app error are not paid
+	if attestation.RespStatus >= 400 {
+		return refundEdgeCall(host, caller, requestHash, value)
+	}
+
+	// release the escrowed value to the provider
+	if value.Sign() > 0 {
+		if err := host.Transfer(contracts.EdgeCallPrecompile, attestation.Provider, value); err != nil {
+			return abiBoolFalse, err
+		}
+	}
+
+	data := make([]byte, 0, 3*types.HashLength)
+	data = append(data, requestHash.Bytes()...)
+	data = append(data, attestation.RespHash.Bytes()...)
+	data = append(data, types.BytesToHash(value.Bytes()).Bytes()...)
+
+	host.EmitLog(
+		contracts.EdgeCallPrecompile,
+		[]types.Hash{
+			EdgeCallEventSig,
+			types.BytesToHash(caller.Bytes()),
+			types.BytesToHash(attestation.Provider.Bytes()),
+		},
+		data,
+	)
 
 	return abiBoolTrue, nil
 }
+
+// refundEdgeCall returns the escrowed value of the edge call to its caller
+func refundEdgeCall(host runtime.Host, caller types.Address, requestHash types.Hash, value *big.Int) ([]byte, error) {
+	if value.Sign() > 0 {
+		if err := host.Transfer(contracts.EdgeCallPrecompile, caller, value); err != nil {
+			return abiBoolFalse, err
+		}
+	}
+
+	data := make([]byte, 0, 2*types.HashLength)
+	data = append(data, requestHash.Bytes()...)
+	data = append(data, types.BytesToHash(value.Bytes()).Bytes()...)
+
+	host.EmitLog(
+		contracts.EdgeCallPrecompile,
+		[]types.Hash{
+			EdgeCallRefundEventSig,
+			types.BytesToHash(caller.Bytes()),
+		},
+		data,
+	)
+
+	return abiBoolFalse, nil
+}
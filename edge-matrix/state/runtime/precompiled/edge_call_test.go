@@ -0,0 +1,268 @@
+package precompiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/emc-protocol/edge-matrix/chain"
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/helper/hex"
+	"github.com/emc-protocol/edge-matrix/state/runtime"
+	"github.com/emc-protocol/edge-matrix/types"
+	libp2pCrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+type edgeCallLog struct {
+	topics []types.Hash
+	data   []byte
+}
+
+type edgeCallHost struct {
+	dummyHost
+
+	storage map[types.Hash]types.Hash
+	nonce   uint64
+	ctx     runtime.TxContext
+	logs    []edgeCallLog
+}
+
+func (h *edgeCallHost) GetNonce(addr types.Address) uint64 {
+	return h.nonce
+}
+
+func (h *edgeCallHost) IncrNonce(addr types.Address) {
+	h.nonce++
+}
+
+func (h *edgeCallHost) GetStorage(addr types.Address, key types.Hash) types.Hash {
+	return h.storage[key]
+}
+
+func (h *edgeCallHost) SetStorage(addr types.Address, key types.Hash, value types.Hash, config *chain.ForksInTime) runtime.StorageStatus {
+	h.storage[key] = value
+
+	return runtime.StorageModified
+}
+
+func (h *edgeCallHost) GetTxContext() runtime.TxContext {
+	return h.ctx
+}
+
+func (h *edgeCallHost) EmitLog(addr types.Address, topics []types.Hash, data []byte) {
+	h.logs = append(h.logs, edgeCallLog{topics: topics, data: data})
+}
+
+func Test_EdgeCallPrecompile(t *testing.T) {
+	var (
+		caller   = types.Address{0x1}
+		provider = types.Address{0x2}
+		forger   = types.Address{0x3}
+		chainID  = int64(100)
+	)
+
+	key, _, err := libp2pCrypto.GenerateEd25519Key(nil)
+	require.NoError(t, err)
+
+	peerId, err := peer.IDFromPrivateKey(key)
+	require.NoError(t, err)
+
+	registration := func(chainID int64, provider types.Address, sequence uint64) []byte {
+		sig, err := key.Sign(ProviderRegistrationHash(uint64(chainID), peerId.String(), provider, sequence).Bytes())
+		require.NoError(t, err)
+
+		input, err := json.Marshal(&ProviderRegistration{
+			PeerId:    peerId.String(),
+			Sequence:  sequence,
+			Signature: hex.EncodeToHex(sig),
+		})
+		require.NoError(t, err)
+
+		return input
+	}
+
+	register := func(input []byte, provider types.Address, host runtime.Host) error {
+		_, err := (&edgeProviderRegistry{}).run(input, provider, host)
+
+		return err
+	}
+
+	callInput := func(peerId string, prompt string) []byte {
+		return []byte(fmt.Sprintf(`{"peerId":%q,"endpoint":"/api","input":{"prompt":%q}}`, peerId, prompt))
+	}
+
+	// attest returns the attestation of the response of the provider to the call, sent with nonce
+	attest := func(input []byte, nonce uint64) *runtime.EdgeCallAttestation {
+		call := &edgeCallInput{}
+		require.NoError(t, json.Unmarshal(input, call))
+
+		binding := &EdgeCallBinding{
+			RespDigest:  types.StringToHash("0x0b"),
+			StatusCode:  200,
+			RequestHash: EdgeCallRequestHash(call.Endpoint, call.Input),
+			Caller:      caller,
+			Nonce:       nonce,
+			ProviderID:  peerId.String(),
+			Timestamp:   1700000000,
+		}
+
+		return &runtime.EdgeCallAttestation{
+			Caller:        binding.Caller,
+			Nonce:         binding.Nonce,
+			RespDigest:    binding.RespDigest,
+			RespStatus:    binding.StatusCode,
+			RespProvider:  binding.ProviderID,
+			RespTimestamp: binding.Timestamp,
+			RespHash:      binding.Hash(uint64(chainID)),
+			RespFrom:      provider,
+			Provider:      provider,
+		}
+	}
+
+	host := &edgeCallHost{dummyHost: *newDummyHost(), storage: map[types.Hash]types.Hash{}}
+	host.ctx.ChainID = chainID
+
+	call := func(input []byte, attestation *runtime.EdgeCallAttestation, value *big.Int) error {
+		host.ctx.EdgeCall = attestation
+		_, err := (&edgeCall{}).runWithValue(input, caller, value, host)
+
+		return err
+	}
+
+	input := callInput(peerId.String(), "hello")
+	attestation := attest(input, 1)
+
+	t.Run("Before the fork", func(t *testing.T) {
+		config := &chain.ForksInTime{}
+		contract := &runtime.Contract{CodeAddress: contracts.EdgeProviderRegistryPrecompile}
+
+		require.False(t, NewPrecompiled().CanRun(contract, host, config))
+		require.Zero(t, (&edgeCall{}).gas(input, config))
+
+		_, err := (&edgeCall{}).run(input, caller, host)
+		require.NoError(t, err)
+	})
+	t.Run("Gas", func(t *testing.T) {
+		config := &chain.ForksInTime{EdgeCallAttestation: true}
+		contract := &runtime.Contract{CodeAddress: contracts.EdgeProviderRegistryPrecompile}
+
+		require.True(t, NewPrecompiled().CanRun(contract, host, config))
+		require.Equal(t, uint64(edgeProviderRegistryGas), (&edgeProviderRegistry{}).gas(input, config))
+		words := uint64(len(input)+31) / 32
+		require.Equal(t, uint64(edgeCallGas)+30+6*words, (&edgeCall{}).gas(input, config))
+	})
+	t.Run("Invalid registration", func(t *testing.T) {
+		input := []byte(fmt.Sprintf(`{"peerId":%q,"signature":"0x00"}`, peerId))
+		_, err := (&edgeProviderRegistry{}).run(input, provider, host)
+		require.ErrorIs(t, err, ErrInvalidProviderRegistration)
+	})
+	t.Run("Unregistered provider", func(t *testing.T) {
+		require.ErrorIs(t, call(input, attestation, nil), ErrUnregisteredEdgeProvider)
+	})
+	t.Run("Verified call", func(t *testing.T) {
+		require.NoError(t, register(registration(chainID, provider, 1), provider, host))
+		require.NoError(t, call(input, attestation, nil))
+
+		// calls routed by target are answered by the peer selected by the router
+		targetInput := callInput("", "hello")
+		require.NoError(t, call(targetInput, attest(targetInput, 1), nil))
+
+		require.Len(t, host.logs, 2)
+		require.Equal(t, []types.Hash{
+			EdgeCallEventSig,
+			types.BytesToHash(caller.Bytes()),
+			types.BytesToHash(provider.Bytes()),
+		}, host.logs[0].topics)
+		require.Equal(t, append(append(
+			EdgeCallRequestHash("/api", []byte(`{"prompt":"hello"}`)).Bytes(),
+			attestation.RespHash.Bytes()...),
+			types.ZeroHash.Bytes()...,
+		), host.logs[0].data)
+	})
+	t.Run("Paid call", func(t *testing.T) {
+		// the value escrowed by the call is released to the provider
+		host.AddBalance(contracts.EdgeCallPrecompile, big.NewInt(100))
+
+		require.NoError(t, call(input, attestation, big.NewInt(100)))
+		require.Zero(t, host.GetBalance(contracts.EdgeCallPrecompile).Sign())
+		require.Equal(t, big.NewInt(100), host.GetBalance(provider))
+		require.Equal(t, types.BytesToHash(big.NewInt(100).Bytes()).Bytes(), host.logs[len(host.logs)-1].data[64:])
+	})
+	t.Run("Refunded call", func(t *testing.T) {
+		// the value escrowed by calls without attestation returns to the caller
+		host.AddBalance(contracts.EdgeCallPrecompile, big.NewInt(100))
+
+		require.NoError(t, call(input, nil, big.NewInt(100)))
+		require.Zero(t, host.GetBalance(contracts.EdgeCallPrecompile).Sign())
+		require.Equal(t, big.NewInt(100), host.GetBalance(caller))
+		require.Equal(t, EdgeCallRefundEventSig, host.logs[len(host.logs)-1].topics[0])
+
+		// as does the value of calls answered with an app error
+		failed := *attestation
+		failed.RespStatus = 500
+		failed.RespHash = (&EdgeCallBinding{
+			RespDigest:  failed.RespDigest,
+			StatusCode:  500,
+			RequestHash: EdgeCallRequestHash("/api", []byte(`{"prompt":"hello"}`)),
+			Caller:      caller,
+			Nonce:       failed.Nonce,
+			ProviderID:  failed.RespProvider,
+			Timestamp:   failed.RespTimestamp,
+		}).Hash(uint64(chainID))
+
+		host.AddBalance(contracts.EdgeCallPrecompile, big.NewInt(50))
+
+		require.NoError(t, call(input, &failed, big.NewInt(50)))
+		require.Equal(t, big.NewInt(150), host.GetBalance(caller))
+		require.Equal(t, big.NewInt(100), host.GetBalance(provider))
+	})
+	t.Run("Copied attestation", func(t *testing.T) {
+		// the attestation answers another request
+		require.ErrorIs(t, call(callInput(peerId.String(), "bye"), attestation, nil), ErrEdgeCallBindingMismatch)
+
+		// the attestation answers the request sent with another nonce or by another caller
+		copied := *attestation
+		copied.Nonce = 2
+		require.ErrorIs(t, call(input, &copied, nil), ErrEdgeCallBindingMismatch)
+
+		copied = *attestation
+		copied.Caller = forger
+		require.ErrorIs(t, call(input, &copied, nil), ErrEdgeCallBindingMismatch)
+
+		// the binding carried by the telegram is not the one signed
+		copied = *attestation
+		copied.RespStatus = 201
+		require.ErrorIs(t, call(input, &copied, nil), ErrEdgeCallBindingMismatch)
+
+		// the attestation is made by another peer than the one of the call
+		require.ErrorIs(t, call(callInput("16Uiu2HAmOther", "hello"), attestation, nil), ErrEdgeCallBindingMismatch)
+	})
+	t.Run("Forged attestation", func(t *testing.T) {
+		// the signature does not recover the claimed provider
+		forged := *attestation
+		forged.Provider = forger
+		require.ErrorIs(t, call(input, &forged, nil), ErrInvalidEdgeCallSig)
+
+		// the signature is valid, but not made by the provider of the peer
+		forged = *attestation
+		forged.RespFrom, forged.Provider = forger, forger
+		require.ErrorIs(t, call(input, &forged, nil), ErrUnregisteredEdgeProvider)
+	})
+	t.Run("Provider change", func(t *testing.T) {
+		// registrations signed for another chain are rejected
+		require.ErrorIs(t, register(registration(chainID+1, forger, 2), forger, host), ErrInvalidProviderRegistration)
+
+		require.NoError(t, register(registration(chainID, forger, 2), forger, host))
+		require.ErrorIs(t, call(input, attestation, nil), ErrUnregisteredEdgeProvider)
+
+		// former registrations of the peer cannot be replayed
+		require.ErrorIs(t, register(registration(chainID, provider, 1), provider, host), ErrStaleProviderRegistration)
+		require.ErrorIs(t, register(registration(chainID, provider, 2), provider, host), ErrStaleProviderRegistration)
+
+		// the registry account is kept by the state clean up once written
+		require.Equal(t, uint64(1), host.nonce)
+	})
+}
@@ -0,0 +1,144 @@
+package precompiled
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"github.com/emc-protocol/edge-matrix/chain"
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/crypto"
+	"github.com/emc-protocol/edge-matrix/helper/hex"
+	"github.com/emc-protocol/edge-matrix/state/runtime"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	providerRegistrationDomain = "edge-matrix provider registration"
+	providerPeerKeyPrefix      = "provider/peer/"
+	providerSeqKeyPrefix       = "provider/seq/"
+
+	// edgeProviderRegistryGas is the cost of the verification of the peer signature
+	// and of the SSTOREs setting the provider and the sequence of the peer
+	edgeProviderRegistryGas = 3000 + 2*20000
+)
+
+var (
+	ErrInvalidProviderRegistration = errors.New("invalid provider registration")
+	ErrStaleProviderRegistration   = errors.New("provider registration sequence not above the registered one")
+)
+
+// the registry storage is charged by the gas of the precompile, the forks only select its refund rules
+var registryForks = chain.AllForksEnabled.At(0)
+
+// ProviderRegistration is the input of the provider registry precompile. It binds the
+// app peer to the provider sending it, the signature being made by the peer key over
+// ProviderRegistrationHash. The sequence has to be above the one of the registration
+// in place, so that former registrations of the peer cannot be replayed.
+type ProviderRegistration struct {
+	PeerId    string `json:"peerId"`
+	Sequence  uint64 `json:"sequence"`
+	Signature string `json:"signature"`
+}
+
+// ProviderRegistrationHash returns the hash the app peer signs to register its provider on the chain
+func ProviderRegistrationHash(chainID uint64, peerId string, provider types.Address, sequence uint64) types.Hash {
+	return crypto.Keccak256Hash(
+		[]byte(providerRegistrationDomain),
+		binary.BigEndian.AppendUint64(nil, chainID),
+		binary.BigEndian.AppendUint64(nil, sequence),
+		provider.Bytes(),
+		[]byte(peerId),
+	)
+}
+
+// edgeProviderRegistry registers the provider of the app peers, the address signing
+// their edge responses, so that the edge call attestations can be checked on chain
+type edgeProviderRegistry struct{}
+
+func (c *edgeProviderRegistry) gas(input []byte, _ *chain.ForksInTime) uint64 {
+	return edgeProviderRegistryGas
+}
+
+func (c *edgeProviderRegistry) run(input []byte, caller types.Address, host runtime.Host) ([]byte, error) {
+	registration := &ProviderRegistration{}
+	if err := json.Unmarshal(input, registration); err != nil {
+		return abiBoolFalse, runtime.ErrInvalidInputData
+	}
+
+	if err := registration.verify(uint64(host.GetTxContext().ChainID), caller); err != nil {
+		return abiBoolFalse, err
+	}
+
+	if registration.Sequence <= registeredSequence(host, registration.PeerId) {
+		return abiBoolFalse, ErrStaleProviderRegistration
+	}
+
+	// the registry has no code, its nonce keeps it from being deleted as an empty account
+	if host.GetNonce(contracts.EdgeProviderRegistryPrecompile) == 0 {
+		host.IncrNonce(contracts.EdgeProviderRegistryPrecompile)
+	}
+
+	host.SetStorage(
+		contracts.EdgeProviderRegistryPrecompile,
+		providerPeerKey(registration.PeerId),
+		types.BytesToHash(caller.Bytes()),
+		&registryForks,
+	)
+	host.SetStorage(
+		contracts.EdgeProviderRegistryPrecompile,
+		providerSeqKey(registration.PeerId),
+		types.BytesToHash(binary.BigEndian.AppendUint64(nil, registration.Sequence)),
+		&registryForks,
+	)
+
+	return abiBoolTrue, nil
+}
+
+// verify checks the registration is signed by the key of its peer for the provider on the chain
+func (r *ProviderRegistration) verify(chainID uint64, provider types.Address) error {
+	id, err := peer.Decode(r.PeerId)
+	if err != nil {
+		return ErrInvalidProviderRegistration
+	}
+
+	pubKey, err := id.ExtractPublicKey()
+	if err != nil {
+		return ErrInvalidProviderRegistration
+	}
+
+	sig, err := hex.DecodeHex(r.Signature)
+	if err != nil {
+		return ErrInvalidProviderRegistration
+	}
+
+	hash := ProviderRegistrationHash(chainID, r.PeerId, provider, r.Sequence)
+	if ok, err := pubKey.Verify(hash.Bytes(), sig); err != nil || !ok {
+		return ErrInvalidProviderRegistration
+	}
+
+	return nil
+}
+
+// registeredProvider returns the provider registered for the app peer, zero if it has none
+func registeredProvider(host runtime.Host, peerId string) types.Address {
+	value := host.GetStorage(contracts.EdgeProviderRegistryPrecompile, providerPeerKey(peerId))
+
+	return types.BytesToAddress(value.Bytes())
+}
+
+// registeredSequence returns the sequence of the registration of the app peer, zero if it has none
+func registeredSequence(host runtime.Host, peerId string) uint64 {
+	value := host.GetStorage(contracts.EdgeProviderRegistryPrecompile, providerSeqKey(peerId))
+
+	return binary.BigEndian.Uint64(value[types.HashLength-8:])
+}
+
+func providerPeerKey(peerId string) types.Hash {
+	return crypto.Keccak256Hash([]byte(providerPeerKeyPrefix), []byte(peerId))
+}
+
+func providerSeqKey(peerId string) types.Hash {
+	return crypto.Keccak256Hash([]byte(providerSeqKeyPrefix), []byte(peerId))
+}
@@ -132,6 +132,10 @@ func (d dummyHost) GetNonce(addr types.Address) uint64 {
 	panic("not implemented")
 }
 
+func (d dummyHost) IncrNonce(addr types.Address) {
+	panic("not implemented")
+}
+
 func (d dummyHost) Transfer(from types.Address, to types.Address, amount *big.Int) error {
 	if d.balances == nil {
 		d.balances = map[types.Address]*big.Int{}
@@ -2,6 +2,7 @@ package precompiled
 
 import (
 	"encoding/binary"
+	"math/big"
 
 	"github.com/emc-protocol/edge-matrix/chain"
 	"github.com/emc-protocol/edge-matrix/contracts"
@@ -25,6 +26,11 @@ type contract interface {
 	run(input []byte, caller types.Address, host runtime.Host) ([]byte, error)
 }
 
+// payableContract is a contract holding the value of its calls
+type payableContract interface {
+	runWithValue(input []byte, caller types.Address, value *big.Int, host runtime.Host) ([]byte, error)
+}
+
 // Precompiled is the runtime for the precompiled contracts
 type Precompiled struct {
 	buf       []byte
@@ -66,6 +72,9 @@ func (p *Precompiled) setupContracts() {
 	// EdgeCall precompile
 	p.register(contracts.EdgeCallPrecompile.String(), &edgeCall{})
 
+	// edgeProviderRegistry precompile
+	p.register(contracts.EdgeProviderRegistryPrecompile.String(), &edgeProviderRegistry{})
+
 	// edgeRtcSubject precompile
 	p.register(contracts.EdgeRtcSubjectPrecompile.String(), &edgeRtcSubject{})
 
@@ -114,6 +123,12 @@ func (p *Precompiled) CanRun(c *runtime.Contract, _ runtime.Host, config *chain.
 		return config.Istanbul
 	}
 
+	// edge call attestation precompiles
+	switch c.CodeAddress {
+	case contracts.EdgeProviderRegistryPrecompile:
+		return config.EdgeCallAttestation
+	}
+
 	return true
 }
 
@@ -136,7 +151,18 @@ func (p *Precompiled) Run(c *runtime.Contract, host runtime.Host, config *chain.
 	}
 
 	c.Gas = c.Gas - gasCost
-	returnValue, err := contract.run(c.Input, c.Caller, host)
+
+	var (
+		returnValue []byte
+		err         error
+	)
+
+	// the edge call precompile holds the value of its calls since the EdgeCallAttestation fork
+	if payable, ok := contract.(payableContract); ok && config.EdgeCallAttestation {
+		returnValue, err = payable.runWithValue(c.Input, c.Caller, c.Value, host)
+	} else {
+		returnValue, err = contract.run(c.Input, c.Caller, host)
+	}
 
 	result := &runtime.ExecutionResult{
 		ReturnValue: returnValue,
@@ -20,6 +20,27 @@ type TxContext struct {
 	ChainID    int64
 	Difficulty types.Hash
 	Tracer     tracer.Tracer
+	// EdgeCall is the provider attestation of the telegram, nil if it has none
+	EdgeCall *EdgeCallAttestation
+}
+
+// EdgeCallAttestation is the provider attestation of an edge call telegram,
+// the provider being recovered from the response signature by the state transition
+type EdgeCallAttestation struct {
+	// sender and nonce of the telegram
+	Caller types.Address
+	Nonce  uint64
+	// response binding carried by the telegram, the EdgeCall precompile
+	// recomputes RespHash from it
+	RespDigest    types.Hash
+	RespStatus    uint64
+	RespProvider  string
+	RespTimestamp uint64
+	RespHash      types.Hash
+	// provider claimed by the telegram
+	RespFrom types.Address
+	// provider recovered from the response signature, zero if it is invalid
+	Provider types.Address
 }
 
 // StorageStatus is the status of the storage access
@@ -71,6 +92,7 @@ type Host interface {
 	Callx(*Contract, Host) *ExecutionResult
 	Empty(addr types.Address) bool
 	GetNonce(addr types.Address) uint64
+	IncrNonce(addr types.Address)
 	Transfer(from types.Address, to types.Address, amount *big.Int) error
 	GetTracer() VMTracer
 	GetRefund() uint64
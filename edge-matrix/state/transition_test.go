@@ -92,3 +92,35 @@ func TestTransfer(t *testing.T) {
 		})
 	}
 }
+
+func TestDynamicFeeChecks(t *testing.T) {
+	t.Parallel()
+
+	transition := newTestTransition(map[types.Address]*PreState{
+		addr1: {Nonce: 0, Balance: 1000},
+	})
+	transition.config.EIP1559 = true
+	transition.baseFee = big.NewInt(5)
+
+	msg := &types.Telegram{
+		Type:      types.DynamicFeeTx,
+		From:      addr1,
+		Gas:       50,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(10),
+		Value:     big.NewInt(600),
+	}
+
+	// the balance covers the fee cap but not the value on top of it
+	assert.ErrorIs(t, transition.subGasLimitPrice(msg), ErrNotEnoughFundsForGas)
+
+	msg.Value = big.NewInt(500)
+	assert.NoError(t, transition.subGasLimitPrice(msg))
+	assert.Equal(t, big.NewInt(700), transition.state.GetBalance(addr1))
+
+	// dynamic fee telegrams are only priced by their fee caps
+	msg.GasPrice = big.NewInt(1)
+
+	err := transition.checkDynamicFee(msg)
+	assert.Equal(t, NewTransitionApplicationError(ErrGasPriceNotAllowed, false), err)
+}
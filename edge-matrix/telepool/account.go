@@ -1,6 +1,8 @@
 package telepool
 
 import (
+	"container/heap"
+	"math/big"
 	"sync"
 	"sync/atomic"
 
@@ -158,6 +160,25 @@ type account struct {
 	maxEnqueued uint64
 }
 
+// pendingValue returns the total value of the promoted and enqueued transactions.
+func (a *account) pendingValue() *big.Int {
+	total := big.NewInt(0)
+
+	for _, queue := range []*accountQueue{a.promoted, a.enqueued} {
+		queue.lock(false)
+
+		for _, tele := range queue.queue {
+			if tele.Value != nil {
+				total.Add(total, tele.Value)
+			}
+		}
+
+		queue.unlock()
+	}
+
+	return total
+}
+
 // getNonce returns the next expected nonce for this account.
 func (a *account) getNonce() uint64 {
 	return atomic.LoadUint64(&a.nextNonce)
@@ -249,6 +270,122 @@ func (a *account) enqueue(tele *types.Telegram) error {
 	return nil
 }
 
+// replace swaps the promoted or enqueued transaction with the same nonce for the given one,
+// if its fee cap and tip are bumped by at least priceBump percent. It returns the replaced
+// transaction, nil if the given one does not replace any.
+func (a *account) replace(tele *types.Telegram, priceBump uint64) (*types.Telegram, error) {
+	a.promoted.lock(true)
+	a.enqueued.lock(true)
+
+	defer func() {
+		a.enqueued.unlock()
+		a.promoted.unlock()
+	}()
+
+	for _, queue := range []*accountQueue{a.promoted, a.enqueued} {
+		for i, old := range queue.queue {
+			if old.Nonce != tele.Nonce {
+				continue
+			}
+
+			if err := checkReplace(old, tele, priceBump); err != nil {
+				return nil, err
+			}
+
+			queue.queue[i] = tele
+			heap.Fix(&queue.queue, i)
+
+			return old, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// get returns the promoted or enqueued transaction with the given nonce, if any.
+func (a *account) get(nonce uint64) *types.Telegram {
+	a.promoted.lock(false)
+	a.enqueued.lock(false)
+
+	defer func() {
+		a.enqueued.unlock()
+		a.promoted.unlock()
+	}()
+
+	for _, queue := range []*accountQueue{a.promoted, a.enqueued} {
+		for _, tele := range queue.queue {
+			if tele.Nonce == nonce {
+				return tele
+			}
+		}
+	}
+
+	return nil
+}
+
+// tail returns the transaction with the highest nonce, the first to be evicted
+// from the account. Enqueued transactions are evicted before promoted ones.
+func (a *account) tail() *types.Telegram {
+	a.promoted.lock(false)
+	a.enqueued.lock(false)
+
+	defer func() {
+		a.enqueued.unlock()
+		a.promoted.unlock()
+	}()
+
+	if tail := a.enqueued.tail(); tail != nil {
+		return tail
+	}
+
+	return a.promoted.tail()
+}
+
+// evict removes the given transaction if it is still the tail of the account,
+// rolling back the next nonce if it was promoted.
+func (a *account) evict(tele *types.Telegram) (evicted bool, promoted bool) {
+	a.promoted.lock(true)
+	a.enqueued.lock(true)
+
+	defer func() {
+		a.enqueued.unlock()
+		a.promoted.unlock()
+	}()
+
+	if tail := a.enqueued.tail(); tail != nil {
+		return tail == tele && a.enqueued.remove(tele), false
+	}
+
+	if a.promoted.tail() != tele || !a.promoted.remove(tele) {
+		return false, false
+	}
+
+	a.setNonce(tele.Nonce)
+
+	return true, true
+}
+
+// expire removes the enqueued transactions for which expired returns true.
+func (a *account) expire(expired func(tele *types.Telegram) bool) (removed []*types.Telegram) {
+	a.enqueued.lock(true)
+	defer a.enqueued.unlock()
+
+	kept := a.enqueued.queue[:0]
+
+	for _, tele := range a.enqueued.queue {
+		if expired(tele) {
+			removed = append(removed, tele)
+		} else {
+			kept = append(kept, tele)
+		}
+	}
+
+	a.enqueued.queue = kept
+	heap.Init(&a.enqueued.queue)
+
+	return
+}
+
 // Promote moves eligible transactions from enqueued to promoted.
 //
 // Eligible transactions are all sequential in order of nonce
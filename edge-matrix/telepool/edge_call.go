@@ -0,0 +1,486 @@
+package telepool
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/armon/go-metrics"
+	"github.com/emc-protocol/edge-matrix/application"
+	"github.com/emc-protocol/edge-matrix/application/proof"
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/libp2p/go-libp2p/core/host"
+	"math/big"
+	"time"
+)
+
+const (
+	// maxEdgeCallAttempts is the max number of app peers tried for an edge call without a fixed PeerId
+	maxEdgeCallAttempts = 3
+)
+
+var (
+	ErrNotEdgeCall         = errors.New("telegram is not an edge call")
+	ErrNoResponseSigner    = errors.New("no edge response signer set")
+	ErrInvalidEdgeResponse = errors.New("invalid edge response")
+	ErrNoAppPeer           = errors.New("no app peer available for the edge call")
+	ErrSealedCallNoPeer    = errors.New("sealed edge call requires a PeerId")
+	ErrEdgeCallFailed      = errors.New("edge call failed on all app peers")
+)
+
+// routeEdgeCall sends the edge call of the telegram to its app peer, and returns
+// the edge response once verified to answer this exact call of the telegram sender
+func (p *TelegramPool) routeEdgeCall(tele *types.Telegram) (*application.EdgeResponse, error) {
+	from, err := p.signer.Sender(tele)
+	if err != nil {
+		return nil, ErrExtractSignature
+	}
+
+	call := &application.EdgeCall{}
+	if err := json.Unmarshal(tele.Input, &call); err != nil {
+		return nil, err
+	}
+
+	if err := p.checkEdgeCallFunds(from, tele); err != nil {
+		return nil, err
+	}
+
+	return p.callWithFailover(call, func(call *application.EdgeCall) (*application.EdgeResponse, error) {
+		return p.sendEdgeCall(call, from, tele)
+	}, nil)
+}
+
+// sendEdgeCall sends the edge call to its app peer
+func (p *TelegramPool) sendEdgeCall(call *application.EdgeCall, from types.Address, tele *types.Telegram) (*application.EdgeResponse, error) {
+	if err := p.checkEdgeCallPrice(call, tele); err != nil {
+		return nil, err
+	}
+
+	host, release, err := p.edgeCallHost(call)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	defer metrics.MeasureSince([]string{txPoolMetrics, "edge_call_duration"}, time.Now())
+
+	respBuf, err := application.CallWithFrom(host, application.ProtoTagEcApp, call, from, tele)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &application.EdgeResponse{}
+	if err := resp.UnmarshalRLP(respBuf); err != nil {
+		return nil, err
+	}
+
+	if err := p.verifyEdgeResponse(resp, application.NewRequestBinding(call, from, tele.Nonce)); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// checkEdgeCallFunds returns an error if the sender cannot lock the value of the edge call
+// telegram, the values of its telegrams pending in the pool being locked already
+func (p *TelegramPool) checkEdgeCallFunds(from types.Address, tele *types.Telegram) error {
+	if tele.Value == nil || tele.Value.Sign() <= 0 {
+		return nil
+	}
+
+	balance, err := p.store.GetBalance(p.store.Header().StateRoot, from)
+	if err != nil {
+		return err
+	}
+
+	locked := new(big.Int).Set(tele.Value)
+	if account := p.accounts.get(from); account != nil {
+		locked.Add(locked, account.pendingValue())
+	}
+
+	if balance.Cmp(locked) < 0 {
+		return ErrInsufficientFunds
+	}
+
+	return nil
+}
+
+// checkEdgeCallPrice returns an error if the value of the telegram does not pay
+// the price published by the app peer of the call
+func (p *TelegramPool) checkEdgeCallPrice(call *application.EdgeCall, tele *types.Telegram) error {
+	if p.appSyncer == nil {
+		return nil
+	}
+
+	appPeer := p.appSyncer.GetApp(call.PeerId, call.App)
+	if appPeer == nil {
+		return nil
+	}
+
+	return appPeer.CheckPrice(tele.Value)
+}
+
+// callWithFailover sends the edge call to its PeerId, or, if it has none, to the best app peer
+// matching its target. While the call fails and retryable allows it (nil allows any failure),
+// the next best app peer is tried. Responses with a server error status count as failures,
+// ErrEdgeCallFailed being returned if no app peer answered successfully.
+func (p *TelegramPool) callWithFailover(
+	call *application.EdgeCall,
+	send func(call *application.EdgeCall) (*application.EdgeResponse, error),
+	retryable func(err error) bool,
+) (*application.EdgeResponse, error) {
+	if call.PeerId != "" {
+		resp, err := send(call)
+		if err == nil && resp != nil && resp.StatusCode >= 500 {
+			return nil, edgeCallFailure(resp)
+		}
+
+		return resp, err
+	}
+
+	// sealed inputs can only be opened by the provider they are encrypted to
+	if application.IsSealedInput(call.Input) {
+		return nil, ErrSealedCallNoPeer
+	}
+
+	if p.appSyncer == nil {
+		return nil, ErrNoAppPeer
+	}
+
+	var (
+		skipMap  = make(map[string]bool)
+		lastResp *application.EdgeResponse
+		lastErr  = ErrNoAppPeer
+	)
+
+	for attempt := 0; attempt < maxEdgeCallAttempts; attempt++ {
+		appPeer := p.appSyncer.BestAppPeer(skipMap, call.Target, p.selectionPolicy)
+		if appPeer == nil {
+			break
+		}
+
+		peerCall := call.Copy()
+		peerCall.PeerId = appPeer.ID
+		peerCall.App = appPeer.Name
+
+		resp, err := send(peerCall)
+		if err == nil && resp != nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+
+		if err != nil {
+			if retryable != nil && !retryable(err) {
+				return nil, err
+			}
+
+			lastErr = err
+		} else {
+			lastResp = resp
+		}
+
+		p.logger.Debug("edge call failed, trying next app peer", "PeerId", appPeer.ID, "err", err)
+		metrics.IncrCounter([]string{txPoolMetrics, "edge_call_failovers"}, 1)
+
+		skipMap[appPeer.ID] = true
+	}
+
+	if lastResp != nil {
+		return nil, edgeCallFailure(lastResp)
+	}
+
+	return nil, lastErr
+}
+
+// edgeCallFailure returns the error of an edge call answered with a server error status
+func edgeCallFailure(resp *application.EdgeResponse) error {
+	return fmt.Errorf("%w: status %d", ErrEdgeCallFailed, resp.StatusCode)
+}
+
+// checkEdgeCall returns an error if the edge call telegram could not be added to the pool
+// once answered, so that no edge call is routed without being recorded
+func (p *TelegramPool) checkEdgeCall(tele *types.Telegram) error {
+	if err := p.validateTele(tele); err != nil {
+		return err
+	}
+
+	if account := p.accounts.get(tele.From); account != nil {
+		if p.gauge.highPressure() && tele.Nonce > account.getNonce() {
+			return ErrRejectFutureTx
+		}
+
+		if old := account.get(tele.Nonce); old != nil {
+			if err := checkReplace(old, tele, p.priceBump); err != nil {
+				return err
+			}
+		}
+	}
+
+	if p.gauge.read()+slotsRequired(tele) > p.gauge.max {
+		if victim := p.cheapestTail(tele.From); victim == nil || p.tip(victim).Cmp(p.tip(tele)) >= 0 {
+			return ErrTxPoolOverflow
+		}
+	}
+
+	return nil
+}
+
+// recordEdgeCall adds the answered edge call telegram to the pool and broadcasts it, so that
+// its record is sealed and its value paid to the provider. Calls answered with an app error
+// are not recorded, their value staying with the caller.
+func (p *TelegramPool) recordEdgeCall(tele *types.Telegram, resp *application.EdgeResponse) error {
+	if resp.IsAppError() {
+		return nil
+	}
+
+	if _, err := p.addTele(local, tele); err != nil {
+		p.logger.Error("failed to add edge call telegram", "hash", tele.Hash, "err", err)
+
+		return err
+	}
+
+	p.publish(tele)
+
+	return nil
+}
+
+// verifyEdgeResponse checks that the edge response is signed by its provider and bound to the request
+func (p *TelegramPool) verifyEdgeResponse(resp *application.EdgeResponse, binding *application.RequestBinding) error {
+	if p.respSigner == nil {
+		return ErrNoResponseSigner
+	}
+
+	if err := application.VerifyResponseBinding(
+		p.respSigner,
+		resp,
+		binding,
+		application.DefaultResponseMaxAge,
+		time.Now(),
+	); err != nil {
+		p.logger.Debug("rejected edge response", "provider", resp.From, "err", err)
+
+		return fmt.Errorf("%w: %s", ErrInvalidEdgeResponse, err.Error())
+	}
+
+	return nil
+}
+
+// AddTeleStream routes a streaming edge call telegram to its app peer.
+// Response chunks are passed to onChunk as soon as they arrive, the returned
+// string is the stream digest signed by the provider. The answered call is
+// added to the pool to be sealed, as the non streamed calls.
+func (p *TelegramPool) AddTeleStream(tele *types.Telegram, onChunk func(chunk []byte) error) (string, error) {
+	if tele.To == nil || *tele.To != contracts.EdgeCallPrecompile {
+		return "", ErrNotEdgeCall
+	}
+
+	from, err := p.signer.Sender(tele)
+	if err != nil {
+		return "", ErrExtractSignature
+	}
+
+	call := &application.EdgeCall{}
+	if err := json.Unmarshal(tele.Input, &call); err != nil {
+		return "", err
+	}
+
+	streamCall, err := application.NewStreamCall(call)
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.checkEdgeCall(tele); err != nil {
+		return "", err
+	}
+
+	if err := p.checkEdgeCallFunds(from, tele); err != nil {
+		return "", err
+	}
+
+	// the stream is bound to the call of the telegram, without the stream flag
+	requestHash := call.Hash()
+
+	// failing over is only possible until the first chunk reached the caller
+	streaming := false
+	streamChunk := func(chunk []byte) error {
+		streaming = true
+
+		return onChunk(chunk)
+	}
+
+	resp, err := p.callWithFailover(streamCall, func(call *application.EdgeCall) (*application.EdgeResponse, error) {
+		if err := p.checkEdgeCallPrice(call, tele); err != nil {
+			return nil, err
+		}
+
+		host, release, err := p.edgeCallHost(call)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		resp, err := application.CallStream(host, application.ProtoTagEcApp, call, from, tele, streamChunk)
+		if err != nil || resp == nil {
+			return nil, err
+		}
+
+		binding := application.NewRequestBinding(call, from, tele.Nonce)
+		binding.RequestHash = requestHash
+
+		if err := p.verifyEdgeResponse(resp, binding); err != nil {
+			return nil, err
+		}
+
+		return resp, nil
+	}, func(err error) bool {
+		return !streaming
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if resp == nil {
+		return "", nil
+	}
+
+	setEdgeResponse(tele, resp)
+
+	if err := p.recordEdgeCall(tele, resp); err != nil {
+		return "", err
+	}
+
+	return resp.RespString, nil
+}
+
+// GetAppSchema fetches the idl of an app and returns its parsed schema.
+// If peerId is empty, the idl is fetched from the best app peer serving the app.
+func (p *TelegramPool) GetAppSchema(peerId string, app string) (*application.AppSchema, error) {
+	call := &application.EdgeCall{PeerId: peerId, Endpoint: "/idl", App: app, Input: json.RawMessage("{}")}
+
+	if call.PeerId == "" {
+		if p.appSyncer == nil {
+			return nil, ErrNoAppPeer
+		}
+
+		appPeer := p.appSyncer.BestAppPeer(nil, &application.EdgeCallTarget{AppName: app}, p.selectionPolicy)
+		if appPeer == nil {
+			return nil, ErrNoAppPeer
+		}
+
+		call.PeerId, call.App = appPeer.ID, appPeer.Name
+	}
+
+	host, release, err := p.edgeCallHost(call)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	respBuf, err := application.Call(host, application.ProtoTagEcApp, call)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &application.EdgeResponse{}
+	if err := resp.UnmarshalRLP(respBuf); err != nil {
+		return nil, err
+	}
+
+	idl, err := base64.StdEncoding.DecodeString(resp.RespString)
+	if err != nil {
+		return nil, err
+	}
+
+	return application.ParseAppSchema(idl)
+}
+
+// GetAppPeers returns the app peers known to the node
+func (p *TelegramPool) GetAppPeers() []*application.AppPeer {
+	if p.appSyncer == nil {
+		return nil
+	}
+
+	return p.appSyncer.GetAppPeers()
+}
+
+// GetBlob reads length bytes of a blob of the app peer from offset,
+// large edge call responses being referenced by their blob hash
+func (p *TelegramPool) GetBlob(peerId string, hash types.Hash, offset uint64, length uint64) ([]byte, error) {
+	host, release, err := p.edgeCallHost(&application.EdgeCall{PeerId: peerId})
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return application.ReadBlob(host, peerId, hash, offset, length)
+}
+
+// PutBlob uploads a chunk of a blob to the app peer and returns the size uploaded so far,
+// so that large edge call inputs can be referenced by their blob hash
+func (p *TelegramPool) PutBlob(peerId string, hash types.Hash, size uint64, offset uint64, chunk []byte) (uint64, error) {
+	host, release, err := p.edgeCallHost(&application.EdgeCall{PeerId: peerId})
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	return application.WriteBlobChunk(host, peerId, hash, size, offset, chunk)
+}
+
+// ChallengeMatMul sends a gpu challenge to the app peer and returns its answer
+func (p *TelegramPool) ChallengeMatMul(peerId string, challenge *proof.MatMulChallenge, timeout time.Duration) (types.Hash, error) {
+	host, release, err := p.edgeCallHost(&application.EdgeCall{PeerId: peerId})
+	if err != nil {
+		return types.ZeroHash, err
+	}
+	defer release()
+
+	return application.ChallengeMatMul(host, peerId, challenge, timeout)
+}
+
+// edgeCallHost returns the host used to reach the app peer of the given call,
+// and a release func that must be called once the call is done
+func (p *TelegramPool) edgeCallHost(call *application.EdgeCall) (host.Host, func(), error) {
+	relayAddr, addr := p.getAppPeerAddr(call.PeerId)
+	p.logger.Debug("edge call", "PeerId", call.PeerId, "Endpoint", call.Endpoint, "addr", addr, "Relay", relayAddr)
+
+	return p.dialer.acquire(call.PeerId, addr, relayAddr, p.edgeNetwork.GetHost())
+}
+
+// SetPeerSelectionPolicy sets the policy selecting the app peer of edge calls without a fixed PeerId
+func (p *TelegramPool) SetPeerSelectionPolicy(policy application.PeerSelectionPolicy) {
+	p.selectionPolicy = policy
+}
+
+// EdgeCallLatency returns the observed edge call latency of the app peer, 0 if unknown
+func (p *TelegramPool) EdgeCallLatency(peerId string) time.Duration {
+	return p.dialer.latency(peerId)
+}
+
+// setEdgeResponse attaches the provider attestation to the telegram
+func setEdgeResponse(tele *types.Telegram, resp *application.EdgeResponse) {
+	tele.RespFrom = resp.From
+	tele.RespR = resp.R
+	tele.RespV = resp.V
+	tele.RespS = resp.S
+	tele.RespHash = resp.Hash
+
+	if resp.IsBound() {
+		tele.RespDigest = resp.Binding().RespDigest
+		tele.RespStatus = resp.StatusCode
+		tele.RespProvider = resp.ProviderID
+		tele.RespTimestamp = resp.Timestamp
+	}
+}
+
+func (p *TelegramPool) getAppPeerAddr(peerId string) (relayAddr string, addr string) {
+	if p.appSyncer != nil {
+		appPeer := p.appSyncer.GetAppPeer(peerId)
+		if appPeer != nil {
+			relayAddr = appPeer.Relay
+			addr = appPeer.Addr
+			return
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,60 @@
+package telepool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/emc-protocol/edge-matrix/application"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTelegramPool_CallWithFailover(t *testing.T) {
+	t.Parallel()
+
+	pool := newEvictionPool(16)
+	call := &application.EdgeCall{PeerId: "16Uiu2HAm", Endpoint: "/api"}
+
+	answer := func(status uint64) func(*application.EdgeCall) (*application.EdgeResponse, error) {
+		return func(*application.EdgeCall) (*application.EdgeResponse, error) {
+			return &application.EdgeResponse{StatusCode: status}, nil
+		}
+	}
+
+	resp, err := pool.callWithFailover(call, answer(200), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200), resp.StatusCode)
+
+	// app errors are answers of the app
+	resp, err = pool.callWithFailover(call, answer(404), nil)
+	assert.NoError(t, err)
+	assert.True(t, resp.IsAppError())
+
+	// server errors fail the call, which is not answered
+	resp, err = pool.callWithFailover(call, answer(503), nil)
+	assert.ErrorIs(t, err, ErrEdgeCallFailed)
+	assert.Nil(t, resp)
+
+	// calls by target fail when no app peer answers
+	sendErr := errors.New("dial failed")
+	_, err = pool.callWithFailover(&application.EdgeCall{Endpoint: "/api"}, answer(200), nil)
+	assert.ErrorIs(t, err, ErrNoAppPeer)
+
+	_, err = pool.callWithFailover(call, func(*application.EdgeCall) (*application.EdgeResponse, error) {
+		return nil, sendErr
+	}, nil)
+	assert.ErrorIs(t, err, sendErr)
+}
+
+func TestTelegramPool_RecordEdgeCall(t *testing.T) {
+	t.Parallel()
+
+	pool := newEvictionPool(16)
+	tele := newEvictionTele(types.Address{0x1}, 0, 100)
+
+	// calls answered with an app error are not added to the pool
+	assert.NoError(t, pool.recordEdgeCall(tele, &application.EdgeResponse{StatusCode: 500}))
+
+	_, ok := pool.index.get(tele.Hash)
+	assert.False(t, ok)
+}
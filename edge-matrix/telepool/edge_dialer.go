@@ -0,0 +1,325 @@
+package telepool
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/emc-protocol/edge-matrix/application"
+	"github.com/hashicorp/go-hclog"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/p2p/security/noise"
+)
+
+const (
+	// DefaultMaxEdgeCallsPerPeer is the default max number of in-flight edge calls per app peer
+	DefaultMaxEdgeCallsPerPeer = 16
+
+	// edgeCallQueueTimeout is how long an edge call waits for a free slot of its app peer
+	edgeCallQueueTimeout = 10 * time.Second
+
+	// edgeCallSlotTimeout is how long an edge call may hold the slot of its app peer,
+	// longer than the client timeouts bounding the calls, so that a hung call does
+	// not keep its slot forever
+	edgeCallSlotTimeout = application.EdgeStreamTimeout + time.Minute
+
+	// edgePeerIdleTimeout is how long the connection to an idle app peer is kept open
+	edgePeerIdleTimeout   = 5 * time.Minute
+	edgePeerPruneInterval = time.Minute
+
+	// weight of the last call in the observed latency of an app peer
+	edgeLatencyWeight = 0.2
+)
+
+var (
+	ErrEdgeCallBusy   = errors.New("too many in-flight edge calls to app peer")
+	ErrEdgeDialerDone = errors.New("edge dialer is closed")
+)
+
+// edgePeer is the dialer state of an app peer
+type edgePeer struct {
+	// slots of in-flight edge calls
+	slots chan struct{}
+
+	addr      string
+	relayAddr string
+	lastUsed  time.Time
+	// moving average of the call latency
+	latency time.Duration
+}
+
+// edgeDialer routes edge calls to app peers over a long-lived client host,
+// so that connections and relay circuits are reused across calls.
+// The number of in-flight calls is capped per app peer.
+type edgeDialer struct {
+	logger hclog.Logger
+
+	sync.Mutex
+	// client host, created on the first call to a relayed or addressed app peer
+	host  host.Host
+	peers map[string]*edgePeer
+
+	maxCallsPerPeer int
+	slotTimeout     time.Duration
+	closeCh         chan struct{}
+	closed          bool
+}
+
+func newEdgeDialer(logger hclog.Logger, maxCallsPerPeer uint64) *edgeDialer {
+	if maxCallsPerPeer == 0 {
+		maxCallsPerPeer = DefaultMaxEdgeCallsPerPeer
+	}
+
+	return &edgeDialer{
+		logger:          logger.Named("edge_dialer"),
+		peers:           make(map[string]*edgePeer),
+		maxCallsPerPeer: int(maxCallsPerPeer),
+		slotTimeout:     edgeCallSlotTimeout,
+		closeCh:         make(chan struct{}),
+	}
+}
+
+// acquire waits for a free slot of the app peer and returns the client host to use.
+// Peers without known addresses are reached through the fallback host.
+// The returned release func must be called once the call is done, the slot being
+// released anyway once held for the slot timeout of the dialer.
+func (d *edgeDialer) acquire(peerId, addr, relayAddr string, fallback host.Host) (host.Host, func(), error) {
+	p, err := d.getPeer(peerId, addr, relayAddr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timer := time.NewTimer(edgeCallQueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case p.slots <- struct{}{}:
+	case <-timer.C:
+		metrics.IncrCounter([]string{txPoolMetrics, "edge_calls_busy"}, 1)
+
+		return nil, nil, ErrEdgeCallBusy
+	case <-d.closeCh:
+		return nil, nil, ErrEdgeDialerDone
+	}
+
+	metrics.IncrCounter([]string{txPoolMetrics, "edge_calls"}, 1)
+	d.updateInflight()
+
+	var (
+		start = time.Now()
+		once  sync.Once
+	)
+
+	free := func() {
+		once.Do(func() {
+			d.Lock()
+			p.lastUsed = time.Now()
+			p.observeLatency(p.lastUsed.Sub(start))
+			d.Unlock()
+
+			<-p.slots
+			d.updateInflight()
+		})
+	}
+
+	expiry := time.AfterFunc(d.slotTimeout, func() {
+		d.logger.Debug("edge call slot expired", "PeerId", peerId)
+		metrics.IncrCounter([]string{txPoolMetrics, "edge_calls_expired"}, 1)
+
+		free()
+	})
+
+	release := func() {
+		expiry.Stop()
+		free()
+	}
+
+	if addr == "" && relayAddr == "" {
+		return fallback, release, nil
+	}
+
+	d.Lock()
+	clientHost := d.host
+	d.Unlock()
+
+	return clientHost, release, nil
+}
+
+// getPeer returns the dialer state of the app peer, registering
+// its addresses with the client host when they are new or changed
+func (d *edgeDialer) getPeer(peerId, addr, relayAddr string) (*edgePeer, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.closed {
+		return nil, ErrEdgeDialerDone
+	}
+
+	p, ok := d.peers[peerId]
+	if !ok {
+		p = &edgePeer{slots: make(chan struct{}, d.maxCallsPerPeer)}
+		d.peers[peerId] = p
+
+		metrics.SetGauge([]string{txPoolMetrics, "edge_dialer_peers"}, float32(len(d.peers)))
+	}
+
+	p.lastUsed = time.Now()
+
+	if addr == "" && relayAddr == "" {
+		return p, nil
+	}
+
+	if d.host == nil {
+		clientHost, err := newClientHost()
+		if err != nil {
+			return nil, err
+		}
+
+		d.host = clientHost
+
+		go d.pruneLoop()
+	}
+
+	if p.addr != addr || p.relayAddr != relayAddr {
+		if err := addAddrToHost(peerId, d.host, addr, relayAddr); err != nil {
+			return nil, err
+		}
+
+		p.addr = addr
+		p.relayAddr = relayAddr
+	}
+
+	return p, nil
+}
+
+// latency returns the observed call latency of the app peer, 0 if unknown
+func (d *edgeDialer) latency(peerId string) time.Duration {
+	d.Lock()
+	defer d.Unlock()
+
+	if p, ok := d.peers[peerId]; ok {
+		return p.latency
+	}
+
+	return 0
+}
+
+func (p *edgePeer) observeLatency(latency time.Duration) {
+	if p.latency == 0 {
+		p.latency = latency
+
+		return
+	}
+
+	p.latency = time.Duration(edgeLatencyWeight*float64(latency) + (1-edgeLatencyWeight)*float64(p.latency))
+}
+
+// updateInflight publishes the number of in-flight edge calls
+func (d *edgeDialer) updateInflight() {
+	d.Lock()
+	defer d.Unlock()
+
+	inflight := 0
+	for _, p := range d.peers {
+		inflight += len(p.slots)
+	}
+
+	metrics.SetGauge([]string{txPoolMetrics, "edge_calls_inflight"}, float32(inflight))
+}
+
+// pruneLoop periodically closes the connections to idle app peers
+func (d *edgeDialer) pruneLoop() {
+	ticker := time.NewTicker(edgePeerPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.closeCh:
+			return
+		case <-ticker.C:
+			d.prune(time.Now())
+		}
+	}
+}
+
+// prune drops the app peers without in-flight calls that were not used since edgePeerIdleTimeout
+func (d *edgeDialer) prune(now time.Time) {
+	d.Lock()
+	defer d.Unlock()
+
+	for peerId, p := range d.peers {
+		if len(p.slots) > 0 || now.Sub(p.lastUsed) < edgePeerIdleTimeout {
+			continue
+		}
+
+		delete(d.peers, peerId)
+
+		if d.host == nil || p.addr == "" && p.relayAddr == "" {
+			continue
+		}
+
+		if id, err := peer.Decode(peerId); err == nil {
+			_ = d.host.Network().ClosePeer(id)
+			d.host.Peerstore().ClearAddrs(id)
+		}
+	}
+
+	metrics.SetGauge([]string{txPoolMetrics, "edge_dialer_peers"}, float32(len(d.peers)))
+}
+
+// Close closes the client host and all its connections
+func (d *edgeDialer) Close() error {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.closed {
+		return nil
+	}
+
+	d.closed = true
+	close(d.closeCh)
+
+	if d.host != nil {
+		return d.host.Close()
+	}
+
+	return nil
+}
+
+func addAddrToHost(peerId string, host host.Host, addr string, relayAddr string) error {
+	if relayAddr != "" {
+		targetRelayInfo, err := peer.AddrInfoFromString(fmt.Sprintf("%s/p2p-circuit/p2p/%s", relayAddr, peerId))
+		if err != nil {
+			return err
+		}
+		host.Peerstore().AddAddrs(targetRelayInfo.ID, targetRelayInfo.Addrs, peerstore.AddressTTL)
+	} else if addr != "" {
+		addrInfo, err := peer.AddrInfoFromString(fmt.Sprintf("%s/p2p/%s", addr, peerId))
+		if err != nil {
+			return err
+		}
+		host.Peerstore().AddAddrs(addrInfo.ID, addrInfo.Addrs, peerstore.RecentlyConnectedAddrTTL)
+	}
+	return nil
+}
+
+// newClientHost returns a libp2p host that only dials out
+func newClientHost() (host.Host, error) {
+	prvKey, _, err := crypto.GenerateKeyPairWithReader(crypto.RSA, 2048, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return libp2p.New(
+		libp2p.NoListenAddrs,
+		libp2p.Security(noise.ID, noise.New),
+		libp2p.Identity(prvKey),
+	)
+}
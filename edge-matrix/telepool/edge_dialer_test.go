@@ -0,0 +1,37 @@
+package telepool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdgeDialer_SlotTimeout(t *testing.T) {
+	t.Parallel()
+
+	dialer := newEdgeDialer(hclog.NewNullLogger(), 1)
+	dialer.slotTimeout = 50 * time.Millisecond
+
+	defer dialer.Close()
+
+	// the call never returns, its slot is released by the slot timeout
+	_, hung, err := dialer.acquire("16Uiu2HAm", "", "", nil)
+	assert.NoError(t, err)
+
+	_, release, err := dialer.acquire("16Uiu2HAm", "", "", nil)
+	assert.NoError(t, err)
+
+	// the late release of the hung call does not free the slot of the next one
+	hung()
+	assert.Len(t, dialer.peers["16Uiu2HAm"].slots, 1)
+
+	release()
+	release()
+	assert.Len(t, dialer.peers["16Uiu2HAm"].slots, 0)
+
+	// the idle peer is then pruned
+	dialer.prune(time.Now().Add(edgePeerIdleTimeout))
+	assert.Empty(t, dialer.peers)
+}
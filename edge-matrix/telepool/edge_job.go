@@ -0,0 +1,434 @@
+package telepool
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/helper/hex"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// EdgeJobStatus is the status of an async edge call
+type EdgeJobStatus string
+
+const (
+	EdgeJobPending EdgeJobStatus = "pending"
+	EdgeJobRunning EdgeJobStatus = "running"
+	EdgeJobDone    EdgeJobStatus = "done"
+	EdgeJobFailed  EdgeJobStatus = "failed"
+)
+
+const (
+	// DefaultEdgeJobTTL is how long finished jobs are kept
+	DefaultEdgeJobTTL = 24 * time.Hour
+
+	edgeJobPruneInterval = 10 * time.Minute
+	edgeJobKeyPrefix     = "job:"
+)
+
+var (
+	ErrEdgeJobNotFound    = errors.New("edge job not found")
+	ErrEdgeJobInterrupted = errors.New("edge job interrupted by router restart")
+)
+
+// EdgeJob is an edge call routed in the background. Its ID is the telegram hash,
+// so that submitting the same telegram again returns the existing job
+type EdgeJob struct {
+	ID        string        `json:"job_id"`
+	Status    EdgeJobStatus `json:"status"`
+	CreatedAt int64         `json:"created_at"`
+	UpdatedAt int64         `json:"updated_at"`
+
+	// result of a done job
+	Response   string   `json:"response,omitempty"`
+	StatusCode uint64   `json:"status_code,omitempty"`
+	Headers    []string `json:"headers,omitempty"`
+	ProviderID string   `json:"provider_id,omitempty"`
+	Provider   string   `json:"provider,omitempty"`
+	// hex encoded RLP of the signed EdgeResponse
+	SignedResponse string `json:"signed_response,omitempty"`
+
+	// error of a failed job
+	Error string `json:"error,omitempty"`
+}
+
+// IsFinished returns true if the job will not change anymore
+func (j *EdgeJob) IsFinished() bool {
+	return j.Status == EdgeJobDone || j.Status == EdgeJobFailed
+}
+
+func (j *EdgeJob) Copy() *EdgeJob {
+	tt := new(EdgeJob)
+	*tt = *j
+
+	if j.Headers != nil {
+		tt.Headers = make([]string, len(j.Headers))
+		copy(tt.Headers, j.Headers)
+	}
+
+	return tt
+}
+
+// edgeJobs keeps the async edge calls of the router, persisted in a leveldb
+type edgeJobs struct {
+	logger hclog.Logger
+
+	sync.Mutex
+	db          *leveldb.DB
+	subscribers map[string][]chan *EdgeJob
+	closeCh     chan struct{}
+}
+
+// newEdgeJobs opens the job store at path, in memory if path is empty.
+// Jobs left unfinished by a previous run are marked as failed.
+func newEdgeJobs(logger hclog.Logger, path string) (*edgeJobs, error) {
+	var (
+		db  *leveldb.DB
+		err error
+	)
+
+	if path == "" {
+		db, err = leveldb.Open(storage.NewMemStorage(), nil)
+	} else {
+		db, err = leveldb.OpenFile(path, nil)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := &edgeJobs{
+		logger:      logger.Named("edge_jobs"),
+		db:          db,
+		subscribers: make(map[string][]chan *EdgeJob),
+		closeCh:     make(chan struct{}),
+	}
+
+	if err := jobs.recover(); err != nil {
+		db.Close()
+
+		return nil, err
+	}
+
+	go jobs.pruneLoop()
+
+	return jobs, nil
+}
+
+// recover fails the jobs that were pending or running when the router stopped
+func (j *edgeJobs) recover() error {
+	return j.forEach(func(job *EdgeJob) error {
+		if job.IsFinished() {
+			return nil
+		}
+
+		job.Status = EdgeJobFailed
+		job.Error = ErrEdgeJobInterrupted.Error()
+		job.UpdatedAt = time.Now().Unix()
+
+		return j.put(job)
+	})
+}
+
+// forEach calls fn for all stored jobs
+func (j *edgeJobs) forEach(fn func(job *EdgeJob) error) error {
+	iter := j.db.NewIterator(util.BytesPrefix([]byte(edgeJobKeyPrefix)), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		job := &EdgeJob{}
+		if err := json.Unmarshal(iter.Value(), job); err != nil {
+			return err
+		}
+
+		if err := fn(job); err != nil {
+			return err
+		}
+	}
+
+	return iter.Error()
+}
+
+func (j *edgeJobs) put(job *EdgeJob) error {
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return j.db.Put([]byte(edgeJobKeyPrefix+job.ID), raw, nil)
+}
+
+func (j *edgeJobs) get(id string) (*EdgeJob, error) {
+	raw, err := j.db.Get([]byte(edgeJobKeyPrefix+id), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, ErrEdgeJobNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	job := &EdgeJob{}
+	if err := json.Unmarshal(raw, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// create stores a new pending job, or returns the existing job with the same ID
+func (j *edgeJobs) create(id string) (*EdgeJob, bool, error) {
+	j.Lock()
+	defer j.Unlock()
+
+	if job, err := j.get(id); err == nil {
+		return job, false, nil
+	} else if !errors.Is(err, ErrEdgeJobNotFound) {
+		return nil, false, err
+	}
+
+	now := time.Now().Unix()
+	job := &EdgeJob{
+		ID:        id,
+		Status:    EdgeJobPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := j.put(job); err != nil {
+		return nil, false, err
+	}
+
+	return job, true, nil
+}
+
+// update applies fn to the job, stores it and notifies its subscribers
+func (j *edgeJobs) update(id string, fn func(job *EdgeJob)) {
+	j.Lock()
+	defer j.Unlock()
+
+	job, err := j.get(id)
+	if err != nil {
+		j.logger.Error("failed to load edge job", "id", id, "err", err)
+
+		return
+	}
+
+	fn(job)
+	job.UpdatedAt = time.Now().Unix()
+
+	if err := j.put(job); err != nil {
+		j.logger.Error("failed to store edge job", "id", id, "err", err)
+	}
+
+	for _, ch := range j.subscribers[id] {
+		// subscribers only need the latest state, replace
+		// the previous update if it was not consumed yet
+		select {
+		case <-ch:
+		default:
+		}
+
+		ch <- job.Copy()
+	}
+
+	if job.IsFinished() {
+		for _, ch := range j.subscribers[id] {
+			close(ch)
+		}
+
+		delete(j.subscribers, id)
+	}
+}
+
+// subscribe returns the current state of the job, and a channel receiving its
+// updates until it is finished. The cancel func must be called to stop receiving.
+func (j *edgeJobs) subscribe(id string) (*EdgeJob, <-chan *EdgeJob, func(), error) {
+	j.Lock()
+	defer j.Unlock()
+
+	job, err := j.get(id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ch := make(chan *EdgeJob, 1)
+	if job.IsFinished() {
+		close(ch)
+
+		return job, ch, func() {}, nil
+	}
+
+	j.subscribers[id] = append(j.subscribers[id], ch)
+
+	cancel := func() {
+		j.Lock()
+		defer j.Unlock()
+
+		subscribers := j.subscribers[id]
+		for i, sub := range subscribers {
+			if sub == ch {
+				j.subscribers[id] = append(subscribers[:i], subscribers[i+1:]...)
+				close(ch)
+
+				break
+			}
+		}
+
+		if len(j.subscribers[id]) == 0 {
+			delete(j.subscribers, id)
+		}
+	}
+
+	return job, ch, cancel, nil
+}
+
+// pruneLoop periodically deletes the finished jobs older than DefaultEdgeJobTTL
+func (j *edgeJobs) pruneLoop() {
+	ticker := time.NewTicker(edgeJobPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.closeCh:
+			return
+		case <-ticker.C:
+			j.prune(time.Now().Add(-DefaultEdgeJobTTL).Unix())
+		}
+	}
+}
+
+func (j *edgeJobs) prune(before int64) {
+	j.Lock()
+	defer j.Unlock()
+
+	batch := new(leveldb.Batch)
+
+	if err := j.forEach(func(job *EdgeJob) error {
+		if job.IsFinished() && job.UpdatedAt < before {
+			batch.Delete([]byte(edgeJobKeyPrefix + job.ID))
+		}
+
+		return nil
+	}); err != nil {
+		j.logger.Error("failed to prune edge jobs", "err", err)
+
+		return
+	}
+
+	if err := j.db.Write(batch, nil); err != nil {
+		j.logger.Error("failed to prune edge jobs", "err", err)
+	}
+}
+
+func (j *edgeJobs) Close() error {
+	close(j.closeCh)
+
+	return j.db.Close()
+}
+
+// SetEdgeJobStore opens the store of async edge calls at path, in memory if path is empty
+func (p *TelegramPool) SetEdgeJobStore(path string) error {
+	jobs, err := newEdgeJobs(p.logger, path)
+	if err != nil {
+		return err
+	}
+
+	p.jobs = jobs
+
+	return nil
+}
+
+// SubmitEdgeJob routes the edge call telegram in the background and returns its job
+func (p *TelegramPool) SubmitEdgeJob(tele *types.Telegram) (*EdgeJob, error) {
+	if tele.To == nil || *tele.To != contracts.EdgeCallPrecompile {
+		return nil, ErrNotEdgeCall
+	}
+
+	if p.jobs == nil {
+		return nil, ErrEdgeJobNotFound
+	}
+
+	// reject telegrams that could not be routed or recorded before creating their job
+	if err := p.checkEdgeCall(tele); err != nil {
+		return nil, err
+	}
+
+	if err := p.checkEdgeCallFunds(tele.From, tele); err != nil {
+		return nil, err
+	}
+
+	job, created, err := p.jobs.create(tele.Hash.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		go p.runEdgeJob(job.ID, tele)
+	}
+
+	return job, nil
+}
+
+// runEdgeJob routes the edge call of the job and stores its result
+func (p *TelegramPool) runEdgeJob(id string, tele *types.Telegram) {
+	p.jobs.update(id, func(job *EdgeJob) {
+		job.Status = EdgeJobRunning
+	})
+
+	fail := func(err error) {
+		p.jobs.update(id, func(job *EdgeJob) {
+			job.Status = EdgeJobFailed
+			job.Error = err.Error()
+		})
+	}
+
+	resp, err := p.routeEdgeCall(tele)
+	if err != nil {
+		fail(err)
+
+		return
+	}
+
+	setEdgeResponse(tele, resp)
+
+	if err := p.recordEdgeCall(tele, resp); err != nil {
+		fail(err)
+
+		return
+	}
+
+	p.jobs.update(id, func(job *EdgeJob) {
+		job.Status = EdgeJobDone
+		job.Response = resp.RespString
+		job.StatusCode = resp.StatusCode
+		job.Headers = resp.Headers
+		job.ProviderID = resp.ProviderID
+		job.Provider = resp.From.String()
+		job.SignedResponse = hex.EncodeToHex(resp.MarshalRLP())
+	})
+}
+
+// GetEdgeJob returns the async edge call with the given job ID
+func (p *TelegramPool) GetEdgeJob(id string) (*EdgeJob, error) {
+	if p.jobs == nil {
+		return nil, ErrEdgeJobNotFound
+	}
+
+	return p.jobs.get(id)
+}
+
+// SubscribeEdgeJob returns the current state of the job, and a channel receiving its
+// updates until it is finished. The cancel func must be called to stop receiving.
+func (p *TelegramPool) SubscribeEdgeJob(id string) (*EdgeJob, <-chan *EdgeJob, func(), error) {
+	if p.jobs == nil {
+		return nil, nil, nil, ErrEdgeJobNotFound
+	}
+
+	return p.jobs.subscribe(id)
+}
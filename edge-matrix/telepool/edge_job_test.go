@@ -0,0 +1,90 @@
+package telepool
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdgeJobs_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	jobs, err := newEdgeJobs(hclog.NewNullLogger(), "")
+	assert.NoError(t, err)
+
+	defer jobs.Close()
+
+	job, created, err := jobs.create("0x1")
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, EdgeJobPending, job.Status)
+
+	// the same telegram returns the existing job
+	_, created, err = jobs.create("0x1")
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	current, updates, cancel, err := jobs.subscribe("0x1")
+	assert.NoError(t, err)
+
+	defer cancel()
+
+	assert.Equal(t, EdgeJobPending, current.Status)
+
+	jobs.update("0x1", func(job *EdgeJob) {
+		job.Status = EdgeJobDone
+		job.Response = "b2s="
+	})
+
+	update, ok := <-updates
+	assert.True(t, ok)
+	assert.Equal(t, EdgeJobDone, update.Status)
+	assert.Equal(t, "b2s=", update.Response)
+
+	// finished jobs close the subscription
+	_, ok = <-updates
+	assert.False(t, ok)
+
+	_, _, _, err = jobs.subscribe("0x2")
+	assert.ErrorIs(t, err, ErrEdgeJobNotFound)
+}
+
+func TestEdgeJobs_Recover(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "edgejobs")
+
+	jobs, err := newEdgeJobs(hclog.NewNullLogger(), path)
+	assert.NoError(t, err)
+
+	_, _, err = jobs.create("0x1")
+	assert.NoError(t, err)
+	_, _, err = jobs.create("0x2")
+	assert.NoError(t, err)
+	jobs.update("0x2", func(job *EdgeJob) {
+		job.Status = EdgeJobDone
+	})
+	assert.NoError(t, jobs.Close())
+
+	// jobs survive restarts, unfinished ones are failed
+	jobs, err = newEdgeJobs(hclog.NewNullLogger(), path)
+	assert.NoError(t, err)
+
+	defer jobs.Close()
+
+	job, err := jobs.get("0x1")
+	assert.NoError(t, err)
+	assert.Equal(t, EdgeJobFailed, job.Status)
+	assert.Equal(t, ErrEdgeJobInterrupted.Error(), job.Error)
+
+	job, err = jobs.get("0x2")
+	assert.NoError(t, err)
+	assert.Equal(t, EdgeJobDone, job.Status)
+
+	jobs.prune(job.UpdatedAt + 1)
+
+	_, err = jobs.get("0x2")
+	assert.ErrorIs(t, err, ErrEdgeJobNotFound)
+}
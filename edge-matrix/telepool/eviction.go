@@ -0,0 +1,192 @@
+package telepool
+
+import (
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/types"
+)
+
+const (
+	// DefaultPriceBump is the minimum gas price bump in percent of a replacement telegram,
+	// applied to both the fee cap and the tip of dynamic fee telegrams
+	DefaultPriceBump uint64 = 10
+
+	// DefaultLifetime is how long a telegram stays enqueued before it expires
+	DefaultLifetime = 3 * time.Hour
+
+	expireInterval = time.Minute
+)
+
+// EvictionStats is the replacement and eviction policy of the pool, with the number
+// of telegrams it removed since the pool started
+type EvictionStats struct {
+	PriceBump uint64
+	Lifetime  time.Duration
+
+	Replaced uint64
+	Evicted  uint64
+	Expired  uint64
+}
+
+// isPriceBumped returns true if price is higher than old by at least bump percent
+func isPriceBumped(old, price *big.Int, bump uint64) bool {
+	if old == nil {
+		old = big.NewInt(0)
+	}
+
+	if price == nil || price.Cmp(old) <= 0 {
+		return false
+	}
+
+	threshold := new(big.Int).Mul(old, new(big.Int).SetUint64(100+bump))
+	threshold.Div(threshold, big.NewInt(100))
+
+	return price.Cmp(threshold) >= 0
+}
+
+// isAnsweredEdgeCall returns true if the telegram is an edge call answered by its provider,
+// which is paid once the telegram is sealed
+func isAnsweredEdgeCall(tele *types.Telegram) bool {
+	return tele.To != nil && *tele.To == contracts.EdgeCallPrecompile && tele.RespFrom != types.ZeroAddress
+}
+
+// checkReplace returns an error if the telegram cannot replace the old one with the same nonce.
+// Answered edge calls are never replaced.
+func checkReplace(old, tele *types.Telegram, priceBump uint64) error {
+	if isAnsweredEdgeCall(old) {
+		return ErrReplaceAnsweredEdgeCall
+	}
+
+	if !isPriceBumped(old.GetGasFeeCap(), tele.GetGasFeeCap(), priceBump) ||
+		!isPriceBumped(old.GetGasTipCap(), tele.GetGasTipCap(), priceBump) {
+		return ErrReplaceUnderpriced
+	}
+
+	return nil
+}
+
+// tip returns the tip per gas of the telegram at the base fee of the latest block
+func (p *TelegramPool) tip(tele *types.Telegram) *big.Int {
+	return tele.EffectiveTip(new(big.Int).SetUint64(atomic.LoadUint64(&p.baseFee)))
+}
+
+// replaceTele replaces the telegram of the sender with the same nonce by the given one.
+// It returns false if the telegram does not replace any.
+func (p *TelegramPool) replaceTele(tele *types.Telegram) (bool, error) {
+	account := p.accounts.get(tele.From)
+	if account == nil {
+		return false, nil
+	}
+
+	old, err := account.replace(tele, p.priceBump)
+	if err != nil || old == nil {
+		return false, err
+	}
+
+	p.index.remove(old)
+	p.gauge.decrease(slotsRequired(old))
+	p.gauge.increase(slotsRequired(tele))
+
+	atomic.AddUint64(&p.replaced, 1)
+
+	p.logger.Debug("replaced telegram", "old", old.Hash, "new", tele.Hash, "nonce", tele.Nonce)
+
+	return true, nil
+}
+
+// evictFor evicts the cheapest telegrams of the other senders, the oldest first among the
+// same tip, until the given telegram fits in the pool. Only the tail of the accounts
+// is evicted, so that no nonce hole is left. It returns false if the telegram does not
+// pay more than the telegrams it would evict.
+func (p *TelegramPool) evictFor(tele *types.Telegram) bool {
+	slots := slotsRequired(tele)
+
+	for p.gauge.read()+slots > p.gauge.max {
+		victim := p.cheapestTail(tele.From)
+		if victim == nil || p.tip(victim).Cmp(p.tip(tele)) >= 0 {
+			return false
+		}
+
+		evicted, promoted := p.accounts.get(victim.From).evict(victim)
+		if !evicted {
+			// the account changed since its tail was selected
+			continue
+		}
+
+		p.index.remove(victim)
+		p.gauge.decrease(slotsRequired(victim))
+
+		if promoted {
+			p.updatePending(-1)
+		}
+
+		atomic.AddUint64(&p.evicted, 1)
+
+		p.logger.Debug("evicted telegram", "hash", victim.Hash, "from", victim.From)
+	}
+
+	return true
+}
+
+// cheapestTail returns the account tail with the lowest tip, the oldest first
+// among the same tip, skipping the given sender
+func (p *TelegramPool) cheapestTail(skip types.Address) (cheapest *types.Telegram) {
+	var arrival time.Time
+
+	p.accounts.Range(func(key, value interface{}) bool {
+		addr, _ := key.(types.Address)
+		account, _ := value.(*account)
+
+		if addr == skip {
+			return true
+		}
+
+		// answered edge calls are not evicted, nor the telegrams before them
+		tail := account.tail()
+		if tail == nil || isAnsweredEdgeCall(tail) {
+			return true
+		}
+
+		tailArrival := p.index.arrival(tail.Hash)
+
+		if cheapest == nil {
+			cheapest, arrival = tail, tailArrival
+
+			return true
+		}
+
+		if cmp := p.tip(tail).Cmp(p.tip(cheapest)); cmp < 0 || (cmp == 0 && tailArrival.Before(arrival)) {
+			cheapest, arrival = tail, tailArrival
+		}
+
+		return true
+	})
+
+	return
+}
+
+// expireEnqueued removes the telegrams enqueued for longer than the pool lifetime
+func (p *TelegramPool) expireEnqueued() {
+	deadline := time.Now().Add(-p.lifetime)
+
+	p.accounts.Range(func(_, value interface{}) bool {
+		account, _ := value.(*account)
+
+		expired := account.expire(func(tele *types.Telegram) bool {
+			return p.index.arrival(tele.Hash).Before(deadline)
+		})
+		if len(expired) == 0 {
+			return true
+		}
+
+		p.index.remove(expired...)
+		p.gauge.decrease(slotsRequired(expired...))
+
+		atomic.AddUint64(&p.expired, uint64(len(expired)))
+
+		return true
+	})
+}
@@ -0,0 +1,163 @@
+package telepool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEvictionTele(from types.Address, nonce uint64, price int64) *types.Telegram {
+	tele := &types.Telegram{
+		From:     from,
+		Nonce:    nonce,
+		GasPrice: big.NewInt(price),
+		Value:    big.NewInt(0),
+		V:        big.NewInt(27),
+		R:        big.NewInt(1),
+		S:        big.NewInt(2),
+	}
+	tele.ComputeHash()
+
+	return tele
+}
+
+func newEvictionPool(maxSlots uint64) *TelegramPool {
+	return &TelegramPool{
+		logger:   hclog.NewNullLogger(),
+		accounts: accountsMap{maxEnqueuedLimit: 16},
+		index: lookupMap{
+			all:      make(map[types.Hash]*types.Telegram),
+			arrivals: make(map[types.Hash]time.Time),
+		},
+		gauge:     slotGauge{max: maxSlots},
+		priceBump: DefaultPriceBump,
+		lifetime:  DefaultLifetime,
+	}
+}
+
+// enqueueForTest adds the telegram to the pool, as the enqueue handler would
+func (p *TelegramPool) enqueueForTest(tele *types.Telegram) {
+	p.index.add(tele)
+	p.accounts.initOnce(tele.From, 0)
+	_ = p.accounts.get(tele.From).enqueue(tele)
+	p.gauge.increase(slotsRequired(tele))
+}
+
+func TestIsPriceBumped(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isPriceBumped(big.NewInt(100), big.NewInt(110), 10))
+	assert.False(t, isPriceBumped(big.NewInt(100), big.NewInt(109), 10))
+	assert.False(t, isPriceBumped(big.NewInt(100), big.NewInt(100), 0))
+	assert.True(t, isPriceBumped(big.NewInt(0), big.NewInt(1), 10))
+}
+
+func TestTelegramPool_ReplaceTele(t *testing.T) {
+	t.Parallel()
+
+	from := types.Address{0x1}
+	pool := newEvictionPool(16)
+	old := newEvictionTele(from, 0, 100)
+	pool.enqueueForTest(old)
+
+	// the replacement has to bump the price
+	replaced, err := pool.replaceTele(newEvictionTele(from, 0, 105))
+	assert.ErrorIs(t, err, ErrReplaceUnderpriced)
+	assert.False(t, replaced)
+
+	// other nonces are not replacements
+	replaced, err = pool.replaceTele(newEvictionTele(from, 1, 100))
+	assert.NoError(t, err)
+	assert.False(t, replaced)
+
+	tele := newEvictionTele(from, 0, 110)
+	pool.index.add(tele)
+
+	replaced, err = pool.replaceTele(tele)
+	assert.NoError(t, err)
+	assert.True(t, replaced)
+
+	_, ok := pool.index.get(old.Hash)
+	assert.False(t, ok)
+	assert.Equal(t, tele, pool.accounts.get(from).getLowestTx())
+	assert.Equal(t, uint64(1), pool.GetEvictionStats().Replaced)
+}
+
+func TestTelegramPool_EvictFor(t *testing.T) {
+	t.Parallel()
+
+	var (
+		cheap  = types.Address{0x1}
+		costly = types.Address{0x2}
+		sender = types.Address{0x3}
+	)
+
+	pool := newEvictionPool(3)
+	pool.enqueueForTest(newEvictionTele(cheap, 0, 1))
+	pool.enqueueForTest(newEvictionTele(cheap, 1, 1))
+	pool.enqueueForTest(newEvictionTele(costly, 0, 10))
+
+	// telegrams paying less than the cheapest are rejected
+	assert.False(t, pool.evictFor(newEvictionTele(sender, 0, 1)))
+
+	// the tail of the cheapest account is evicted first
+	assert.True(t, pool.evictFor(newEvictionTele(sender, 0, 5)))
+	assert.Equal(t, uint64(2), pool.gauge.read())
+	assert.Equal(t, uint64(0), pool.accounts.get(cheap).tail().Nonce)
+	assert.Equal(t, uint64(1), pool.GetEvictionStats().Evicted)
+}
+
+func TestTelegramPool_AnsweredEdgeCall(t *testing.T) {
+	t.Parallel()
+
+	var (
+		caller = types.Address{0x1}
+		sender = types.Address{0x2}
+	)
+
+	answered := newEvictionTele(caller, 0, 0)
+	answered.To = &contracts.EdgeCallPrecompile
+	answered.RespFrom = types.Address{0x9}
+	answered.ComputeHash()
+
+	pool := newEvictionPool(1)
+	pool.enqueueForTest(answered)
+
+	// answered edge calls are not replaced, whatever the price bump
+	replaced, err := pool.replaceTele(newEvictionTele(caller, 0, 100))
+	assert.ErrorIs(t, err, ErrReplaceAnsweredEdgeCall)
+	assert.False(t, replaced)
+
+	// nor evicted for telegrams paying more
+	assert.False(t, pool.evictFor(newEvictionTele(sender, 0, 100)))
+
+	_, ok := pool.index.get(answered.Hash)
+	assert.True(t, ok)
+}
+
+func TestTelegramPool_ExpireEnqueued(t *testing.T) {
+	t.Parallel()
+
+	from := types.Address{0x1}
+	pool := newEvictionPool(16)
+	stale := newEvictionTele(from, 1, 1)
+	fresh := newEvictionTele(from, 2, 1)
+
+	pool.enqueueForTest(stale)
+	pool.enqueueForTest(fresh)
+
+	pool.index.arrivals[stale.Hash] = time.Now().Add(-2 * DefaultLifetime)
+
+	pool.expireEnqueued()
+
+	_, ok := pool.index.get(stale.Hash)
+	assert.False(t, ok)
+	assert.Equal(t, fresh, pool.accounts.get(from).getLowestTx())
+	assert.Equal(t, uint64(1), pool.gauge.read())
+	assert.Equal(t, uint64(1), pool.GetEvictionStats().Expired)
+}
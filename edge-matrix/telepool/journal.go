@@ -0,0 +1,261 @@
+package telepool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// DefaultJournalRejournal is how often the journal is compacted
+	DefaultJournalRejournal = time.Hour
+
+	// journalRecordPrefix is the size of the length prefix of the journal records
+	journalRecordPrefix = 4
+)
+
+var ErrJournalClosed = errors.New("telepool journal closed")
+
+// teleJournal is an append-only file of the locally submitted telegrams, replayed
+// into the pool at startup. Each record is the RLP of a telegram prefixed by its
+// big endian length, so that the answered edge calls keep their response.
+type teleJournal struct {
+	logger hclog.Logger
+	path   string
+
+	sync.Mutex
+	writer *os.File
+	// hashes of the journaled telegrams
+	hashes  map[types.Hash]struct{}
+	closeCh chan struct{}
+}
+
+// newTeleJournal returns the journal at path, the file being created on first insert
+func newTeleJournal(logger hclog.Logger, path string) *teleJournal {
+	return &teleJournal{
+		logger:  logger.Named("journal"),
+		path:    path,
+		hashes:  make(map[types.Hash]struct{}),
+		closeCh: make(chan struct{}),
+	}
+}
+
+// load reads the journaled telegrams and calls add for each of them. A truncated
+// last record, left by a crash while writing it, ends the journal.
+func (j *teleJournal) load(add func(tele *types.Telegram) error) (int, int, error) {
+	file, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	var (
+		reader = bufio.NewReader(file)
+		prefix = make([]byte, journalRecordPrefix)
+
+		total, dropped int
+	)
+
+	for {
+		if _, err := io.ReadFull(reader, prefix); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return total, dropped, nil
+			}
+
+			return total, dropped, err
+		}
+
+		raw := make([]byte, binary.BigEndian.Uint32(prefix))
+		if _, err := io.ReadFull(reader, raw); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return total, dropped, nil
+			}
+
+			return total, dropped, err
+		}
+
+		tele := new(types.Telegram)
+		if err := tele.UnmarshalRLP(raw); err != nil {
+			return total, dropped, err
+		}
+
+		total++
+
+		if err := add(tele); err != nil {
+			j.logger.Debug("dropped journaled telegram", "hash", tele.Hash, "err", err)
+
+			dropped++
+
+			continue
+		}
+
+		j.Lock()
+		j.hashes[tele.Hash] = struct{}{}
+		j.Unlock()
+	}
+}
+
+// insert appends the telegram to the journal
+func (j *teleJournal) insert(tele *types.Telegram) error {
+	j.Lock()
+	defer j.Unlock()
+
+	if j.writer == nil {
+		select {
+		case <-j.closeCh:
+			return ErrJournalClosed
+		default:
+		}
+
+		writer, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return err
+		}
+
+		j.writer = writer
+	}
+
+	if _, err := j.writer.Write(encodeJournalRecord(tele)); err != nil {
+		return err
+	}
+
+	j.hashes[tele.Hash] = struct{}{}
+
+	return nil
+}
+
+// rotate rewrites the journal with the journaled telegrams lookup still returns,
+// dropping the ones that left the pool since they were journaled
+func (j *teleJournal) rotate(lookup func(hash types.Hash) (*types.Telegram, bool)) error {
+	j.Lock()
+	defer j.Unlock()
+
+	select {
+	case <-j.closeCh:
+		return ErrJournalClosed
+	default:
+	}
+
+	teles := make([]*types.Telegram, 0, len(j.hashes))
+	for hash := range j.hashes {
+		if tele, ok := lookup(hash); ok {
+			teles = append(teles, tele)
+		} else {
+			delete(j.hashes, hash)
+		}
+	}
+
+	// replay the telegrams of each sender in nonce order
+	sort.Slice(teles, func(i, k int) bool {
+		return teles[i].Nonce < teles[k].Nonce
+	})
+
+	if j.writer != nil {
+		if err := j.writer.Close(); err != nil {
+			return err
+		}
+
+		j.writer = nil
+	}
+
+	replacement, err := os.OpenFile(j.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	for _, tele := range teles {
+		if _, err := replacement.Write(encodeJournalRecord(tele)); err != nil {
+			replacement.Close()
+
+			return err
+		}
+	}
+
+	if err := replacement.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(j.path+".new", j.path)
+}
+
+func (j *teleJournal) Close() error {
+	j.Lock()
+	defer j.Unlock()
+
+	close(j.closeCh)
+
+	if j.writer == nil {
+		return nil
+	}
+
+	err := j.writer.Close()
+	j.writer = nil
+
+	return err
+}
+
+func encodeJournalRecord(tele *types.Telegram) []byte {
+	raw := tele.MarshalRLP()
+
+	record := make([]byte, journalRecordPrefix, journalRecordPrefix+len(raw))
+	binary.BigEndian.PutUint32(record, uint32(len(raw)))
+
+	return append(record, raw...)
+}
+
+// SetJournal journals the locally submitted telegrams at path, compacting the
+// journal every rejournal. The journaled telegrams are replayed on Start.
+func (p *TelegramPool) SetJournal(path string, rejournal time.Duration) {
+	if rejournal <= 0 {
+		rejournal = DefaultJournalRejournal
+	}
+
+	p.journal = newTeleJournal(p.logger, path)
+	p.rejournal = rejournal
+}
+
+// loadJournal replays the journaled telegrams into the pool, and compacts the
+// journal to the ones it accepted
+func (p *TelegramPool) loadJournal() {
+	total, dropped, err := p.journal.load(func(tele *types.Telegram) error {
+		_, err := p.addTele(journal, tele)
+
+		return err
+	})
+	if err != nil {
+		p.logger.Error("failed to load telegram journal", "err", err)
+	}
+
+	p.logger.Info("loaded telegram journal", "telegrams", total, "dropped", dropped)
+
+	if err := p.journal.rotate(p.index.get); err != nil {
+		p.logger.Error("failed to rotate telegram journal", "err", err)
+	}
+}
+
+// journalLoop periodically compacts the journal
+func (p *TelegramPool) journalLoop() {
+	ticker := time.NewTicker(p.rejournal)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.journal.closeCh:
+			return
+		case <-ticker.C:
+			if err := p.journal.rotate(p.index.get); err != nil {
+				p.logger.Error("failed to rotate telegram journal", "err", err)
+			}
+		}
+	}
+}
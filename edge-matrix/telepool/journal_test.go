@@ -0,0 +1,107 @@
+package telepool
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/emc-protocol/edge-matrix/contracts"
+	"github.com/emc-protocol/edge-matrix/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newJournalTele(nonce uint64) *types.Telegram {
+	to := contracts.EdgeCallPrecompile
+	tele := &types.Telegram{
+		Nonce:    nonce,
+		GasPrice: big.NewInt(0),
+		To:       &to,
+		Value:    big.NewInt(1),
+		Input:    []byte(`{"peerId":"16Uiu2"}`),
+		V:        big.NewInt(27),
+		R:        big.NewInt(1),
+		S:        big.NewInt(2),
+		RespV:    big.NewInt(27),
+		RespR:    big.NewInt(3),
+		RespS:    big.NewInt(4),
+		RespHash: types.StringToHash("0x0b"),
+		RespFrom: types.StringToAddress("0x2"),
+	}
+	tele.ComputeHash()
+
+	return tele
+}
+
+func TestTeleJournal(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "telepool.journal")
+	pool := map[types.Hash]*types.Telegram{}
+
+	lookup := func(hash types.Hash) (*types.Telegram, bool) {
+		tele, ok := pool[hash]
+
+		return tele, ok
+	}
+
+	journal := newTeleJournal(hclog.NewNullLogger(), path)
+
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		tele := newJournalTele(nonce)
+		pool[tele.Hash] = tele
+
+		assert.NoError(t, journal.insert(tele))
+	}
+
+	assert.NoError(t, journal.Close())
+
+	// the journal is replayed with the edge responses
+	loaded := []*types.Telegram{}
+	journal = newTeleJournal(hclog.NewNullLogger(), path)
+
+	total, dropped, err := journal.load(func(tele *types.Telegram) error {
+		loaded = append(loaded, tele)
+
+		if tele.Nonce == 1 {
+			return ErrNonceTooLow
+		}
+
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, 1, dropped)
+	assert.Len(t, loaded, 3)
+	assert.Equal(t, newJournalTele(2).Hash, loaded[2].Hash)
+	assert.Equal(t, types.StringToAddress("0x2"), loaded[2].RespFrom)
+	assert.Equal(t, types.StringToHash("0x0b"), loaded[2].RespHash)
+
+	// compaction keeps the telegrams still in the pool
+	delete(pool, newJournalTele(0).Hash)
+	assert.NoError(t, journal.rotate(lookup))
+
+	total, _, err = journal.load(func(tele *types.Telegram) error {
+		assert.Equal(t, uint64(2), tele.Nonce)
+
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	// a truncated last record ends the journal
+	tele := newJournalTele(3)
+	assert.NoError(t, journal.insert(tele))
+	assert.NoError(t, journal.Close())
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Truncate(path, info.Size()-1))
+
+	total, _, err = newTeleJournal(hclog.NewNullLogger(), path).load(func(*types.Telegram) error {
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+}
@@ -2,6 +2,7 @@ package telepool
 
 import (
 	"sync"
+	"time"
 
 	"github.com/emc-protocol/edge-matrix/types"
 )
@@ -10,6 +11,8 @@ import (
 type lookupMap struct {
 	sync.RWMutex
 	all map[types.Hash]*types.Telegram
+	// arrival time of the transactions, used to expire and evict the oldest ones
+	arrivals map[types.Hash]time.Time
 }
 
 // add inserts the given transaction into the map. Returns false
@@ -23,6 +26,7 @@ func (m *lookupMap) add(msg *types.Telegram) bool {
 	}
 
 	m.all[msg.Hash] = msg
+	m.arrivals[msg.Hash] = time.Now()
 
 	return true
 }
@@ -34,6 +38,7 @@ func (m *lookupMap) remove(msgs ...*types.Telegram) {
 
 	for _, msg := range msgs {
 		delete(m.all, msg.Hash)
+		delete(m.arrivals, msg.Hash)
 	}
 }
 
@@ -49,3 +54,11 @@ func (m *lookupMap) get(hash types.Hash) (*types.Telegram, bool) {
 
 	return tx, true
 }
+
+// arrival returns the time the given transaction entered the map. [thread-safe]
+func (m *lookupMap) arrival(hash types.Hash) time.Time {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.arrivals[hash]
+}
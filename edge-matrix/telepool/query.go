@@ -1,6 +1,10 @@
 package telepool
 
-import "github.com/emc-protocol/edge-matrix/types"
+import (
+	"sync/atomic"
+
+	"github.com/emc-protocol/edge-matrix/types"
+)
 
 /* QUERY methods */
 // Used to query the pool for specific state info.
@@ -46,3 +50,15 @@ func (p *TelegramPool) GetTeles(inclQueued bool) (
 
 	return
 }
+
+// GetEvictionStats returns the replacement and eviction policy of the pool,
+// with the number of telegrams it removed
+func (p *TelegramPool) GetEvictionStats() EvictionStats {
+	return EvictionStats{
+		PriceBump: p.priceBump,
+		Lifetime:  p.lifetime,
+		Replaced:  atomic.LoadUint64(&p.replaced),
+		Evicted:   atomic.LoadUint64(&p.evicted),
+		Expired:   atomic.LoadUint64(&p.expired),
+	}
+}
@@ -3,6 +3,7 @@ package telepool
 import (
 	"container/heap"
 	"github.com/emc-protocol/edge-matrix/types"
+	"math/big"
 	"sync"
 	"sync/atomic"
 )
@@ -71,6 +72,31 @@ func (q *accountQueue) clear() (removed []*types.Telegram) {
 	return
 }
 
+// remove removes the given transaction from the queue,
+// returning false if it is not in the queue.
+func (q *accountQueue) remove(tx *types.Telegram) bool {
+	for i, queued := range q.queue {
+		if queued == tx {
+			heap.Remove(&q.queue, i)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// tail returns the transaction with the highest nonce without removing it.
+func (q *accountQueue) tail() (tail *types.Telegram) {
+	for _, tx := range q.queue {
+		if tail == nil || tx.Nonce > tail.Nonce {
+			tail = tx
+		}
+	}
+
+	return
+}
+
 // push pushes the given transactions onto the queue.
 func (q *accountQueue) push(tx *types.Telegram) {
 	heap.Push(&q.queue, tx)
@@ -128,7 +154,7 @@ func (q *minNonceQueue) Swap(i, j int) {
 func (q *minNonceQueue) Less(i, j int) bool {
 	// The higher gas price Tx comes first if the nonces are same
 	if (*q)[i].Nonce == (*q)[j].Nonce {
-		return (*q)[i].GasPrice.Cmp((*q)[j].GasPrice) > 0
+		return (*q)[i].GetGasFeeCap().Cmp((*q)[j].GetGasFeeCap()) > 0
 	}
 
 	return (*q)[i].Nonce < (*q)[j].Nonce
@@ -158,7 +184,10 @@ type pricedQueue struct {
 
 func newPricedQueue() *pricedQueue {
 	q := pricedQueue{
-		queue: make(maxPriceQueue, 0),
+		queue: maxPriceQueue{
+			baseFee: big.NewInt(0),
+			teles:   make([]*types.Telegram, 0),
+		},
 	}
 
 	heap.Init(&q.queue)
@@ -166,9 +195,11 @@ func newPricedQueue() *pricedQueue {
 	return &q
 }
 
-// clear empties the underlying queue.
-func (q *pricedQueue) clear() {
-	q.queue = q.queue[:0]
+// clear empties the underlying queue, ordering the next
+// transactions by their tip at the given base fee.
+func (q *pricedQueue) clear(baseFee uint64) {
+	q.queue.baseFee = new(big.Int).SetUint64(baseFee)
+	q.queue.teles = q.queue.teles[:0]
 }
 
 // Pushes the given transactions onto the queue.
@@ -196,8 +227,11 @@ func (q *pricedQueue) length() uint64 {
 	return uint64(q.queue.Len())
 }
 
-// transactions sorted by gas price (descending)
-type maxPriceQueue []*types.Telegram
+// transactions sorted by effective tip at the base fee (descending)
+type maxPriceQueue struct {
+	baseFee *big.Int
+	teles   []*types.Telegram
+}
 
 /* Queue methods required by the heap interface */
 
@@ -206,19 +240,19 @@ func (q *maxPriceQueue) Peek() *types.Telegram {
 		return nil
 	}
 
-	return (*q)[0]
+	return q.teles[0]
 }
 
 func (q *maxPriceQueue) Len() int {
-	return len(*q)
+	return len(q.teles)
 }
 
 func (q *maxPriceQueue) Swap(i, j int) {
-	(*q)[i], (*q)[j] = (*q)[j], (*q)[i]
+	q.teles[i], q.teles[j] = q.teles[j], q.teles[i]
 }
 
 func (q *maxPriceQueue) Less(i, j int) bool {
-	return (*q)[i].GasPrice.Uint64() > (*q)[j].GasPrice.Uint64()
+	return q.teles[i].EffectiveTip(q.baseFee).Cmp(q.teles[j].EffectiveTip(q.baseFee)) > 0
 }
 
 func (q *maxPriceQueue) Push(x interface{}) {
@@ -227,14 +261,13 @@ func (q *maxPriceQueue) Push(x interface{}) {
 		return
 	}
 
-	*q = append(*q, transaction)
+	q.teles = append(q.teles, transaction)
 }
 
 func (q *maxPriceQueue) Pop() interface{} {
-	old := q
-	n := len(*old)
-	x := (*old)[n-1]
-	*q = (*old)[0 : n-1]
+	n := len(q.teles)
+	x := q.teles[n-1]
+	q.teles = q.teles[0 : n-1]
 
 	return x
 }
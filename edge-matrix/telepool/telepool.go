@@ -1,8 +1,6 @@
 package telepool
 
 import (
-	"crypto/rand"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/armon/go-metrics"
@@ -14,15 +12,8 @@ import (
 	"github.com/emc-protocol/edge-matrix/types"
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/hashicorp/go-hclog"
-	"github.com/libp2p/go-libp2p"
-	"github.com/libp2p/go-libp2p/core/crypto"
-	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
-	"github.com/libp2p/go-libp2p/core/peerstore"
-	"github.com/libp2p/go-libp2p/p2p/security/noise"
-	ma "github.com/multiformats/go-multiaddr"
 	"github.com/umbracle/fastrlp"
-	"io"
 	"math/big"
 	"sync/atomic"
 	"time"
@@ -32,8 +23,9 @@ import (
 type teleOrigin int
 
 const (
-	local  teleOrigin = iota // json-RPC/gRPC endpoints
-	gossip                   // gossip protocol
+	local   teleOrigin = iota // json-RPC/gRPC endpoints
+	gossip                    // gossip protocol
+	journal                   // journal replayed at startup
 )
 
 // errors
@@ -46,6 +38,8 @@ var (
 	ErrInvalidProvider         = errors.New("invalid provider")
 	ErrTxPoolOverflow          = errors.New("txpool is full")
 	ErrUnderpriced             = errors.New("transaction underpriced")
+	ErrReplaceUnderpriced      = errors.New("replacement transaction underpriced")
+	ErrReplaceAnsweredEdgeCall = errors.New("answered edge call cannot be replaced")
 	ErrNonceTooLow             = errors.New("nonce too low")
 	ErrNonceTooHigh            = errors.New("nonce too high")
 	ErrInsufficientFunds       = errors.New("insufficient funds for gas * price + value")
@@ -55,6 +49,9 @@ var (
 	ErrMaxEnqueuedLimitReached = errors.New("maximum number of enqueued transactions reached")
 	ErrRejectFutureTx          = errors.New("rejected future tx due to low slots")
 	ErrSmartContractRestricted = errors.New("smart contract deployment restricted")
+	ErrTxTypeNotSupported      = errors.New("transaction type not supported")
+	ErrTipAboveFeeCap          = errors.New("max priority fee per gas higher than max fee per gas")
+	ErrGasPriceNotAllowed      = errors.New("gas price set on a dynamic fee telegram")
 )
 
 func (o teleOrigin) String() (s string) {
@@ -63,6 +60,8 @@ func (o teleOrigin) String() (s string) {
 		s = "local"
 	case gossip:
 		s = "gossip"
+	case journal:
+		s = "journal"
 	}
 
 	return
@@ -126,13 +125,21 @@ type promoteRequest struct {
 type Config struct {
 	MaxSlots           uint64
 	MaxAccountEnqueued uint64
+	// MaxEdgeCallsPerPeer is the max number of in-flight edge calls per app peer
+	MaxEdgeCallsPerPeer uint64
+	// PriceBump is the minimum gas price bump in percent of a replacement telegram
+	PriceBump uint64
+	// Lifetime is how long a telegram stays enqueued, zero to never expire
+	Lifetime time.Duration
 }
 
 type TelegramPool struct {
 	logger         hclog.Logger
 	signer         signer
 	providerSigner providerSigner
-	store          store
+	// respSigner verifies the edge responses of routed edge calls
+	respSigner application.Signer
+	store      store
 	// map of all accounts registered by the pool
 	accounts accountsMap
 
@@ -149,10 +156,27 @@ type TelegramPool struct {
 	edgeNetwork *network.Server
 
 	appSyncer application.Syncer
+	// dialer routes edge calls to app peers
+	dialer *edgeDialer
+	// selectionPolicy picks the app peer of edge calls without a fixed PeerId
+	selectionPolicy application.PeerSelectionPolicy
+	// jobs are the edge calls routed in the background
+	jobs *edgeJobs
+	// journal keeps the locally submitted telegrams across restarts
+	journal   *teleJournal
+	rejournal time.Duration
 
 	// gauge for measuring pool capacity
 	gauge slotGauge
 
+	// replacement and eviction policy
+	priceBump uint64
+	lifetime  time.Duration
+	// number of replaced, evicted and expired telegrams, accessed with atomics
+	replaced, evicted, expired uint64
+	// base fee of the latest block, accessed with atomics
+	baseFee uint64
+
 	// channels on which the pool's event loop
 	// does dispatching/handling requests.
 	enqueueReqCh chan enqueueRequest
@@ -191,8 +215,13 @@ func NewTelegramPool(
 		store:       store,
 		executables: newPricedQueue(),
 		accounts:    accountsMap{maxEnqueuedLimit: config.MaxAccountEnqueued},
-		index:       lookupMap{all: make(map[types.Hash]*types.Telegram)},
-		gauge:       slotGauge{height: 0, max: config.MaxSlots},
+		index: lookupMap{
+			all:      make(map[types.Hash]*types.Telegram),
+			arrivals: make(map[types.Hash]time.Time),
+		},
+		gauge:     slotGauge{height: 0, max: config.MaxSlots},
+		priceBump: config.PriceBump,
+		lifetime:  config.Lifetime,
 		//	main loop channels
 		enqueueReqCh: make(chan enqueueRequest),
 		promoteReqCh: make(chan promoteRequest),
@@ -204,6 +233,8 @@ func NewTelegramPool(
 
 	// Attach the event manager
 	pool.eventManager = newEventManager(pool.logger)
+	pool.dialer = newEdgeDialer(pool.logger, config.MaxEdgeCallsPerPeer)
+	pool.selectionPolicy = application.LeastLoadedPolicy{}
 
 	if network != nil {
 		// subscribe to the gossip protocol
@@ -279,53 +310,27 @@ func (p *TelegramPool) SetAppSyncer(appSyncer application.Syncer) {
 
 // AddTele adds a new telegram to the pool (sent from json-RPC/gRPC endpoints)
 // and broadcasts it to the network (if enabled).
-func (p *TelegramPool) AddTele(tele *types.Telegram) (string, error) {
-	resp := &application.EdgeResponse{}
+// Edge calls are answered directly with the response of the app,
+// the successful ones being added to the pool to be sealed.
+func (p *TelegramPool) AddTele(tele *types.Telegram) (*application.EdgeResponse, error) {
 	if tele.To != nil && *tele.To == contracts.EdgeCallPrecompile {
-		input := tele.Input
-		call := &application.EdgeCall{}
-		if err := json.Unmarshal(input, &call); err != nil {
-			return "", err
-		}
 		//if call.Endpoint != "/api" {
-		// do not gossip tele
-		host := p.edgeNetwork.GetHost()
-
-		relayAddr, addr := p.getAppPeerAddr(call.PeerId)
-		p.logger.Debug("edge call", "PeerId", call.PeerId, "Endpoint", call.Endpoint, "addr", addr, "Relay", relayAddr)
-		if relayAddr != "" || addr != "" {
-			clientHost, err2 := p.newTempHost()
-			if err2 != nil {
-				return "", err2
-			}
-			defer clientHost.Close()
-
-			host = clientHost
-			err := p.addAddrToHost(call.PeerId, host, addr, relayAddr)
-			if err != nil {
-				return "", err
-			}
-		}
-
-		respBuf, callErr := application.Call(host, application.ProtoTagEcApp, call)
-		if callErr != nil {
-			return "", callErr
+		if err := p.checkEdgeCall(tele); err != nil {
+			return nil, err
 		}
 
-		err := resp.UnmarshalRLP(respBuf)
+		resp, err := p.routeEdgeCall(tele)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		tele.RespFrom = resp.From
-		tele.RespR = resp.R
-		tele.RespV = resp.V
-		tele.RespS = resp.S
-		tele.RespHash = resp.Hash
-		if len(resp.RespString) > 0 {
-			return resp.RespString, nil
-		} else {
-			return "", nil
+
+		setEdgeResponse(tele, resp)
+
+		if err := p.recordEdgeCall(tele, resp); err != nil {
+			return nil, err
 		}
+
+		return resp, nil
 		//}
 	}
 
@@ -340,77 +345,39 @@ func (p *TelegramPool) AddTele(tele *types.Telegram) (string, error) {
 	}
 	tele.RespHash = types.ZeroHash
 	tele.RespFrom = types.ZeroAddress
+	tele.RespDigest = types.ZeroHash
+	tele.RespStatus = 0
+	tele.RespProvider = ""
+	tele.RespTimestamp = 0
 
 	respString, err := p.addTele(local, tele)
 	if err != nil {
 		p.logger.Error("failed to add telegram", "err", err)
 
-		return "", err
+		return nil, err
 	}
 
-	// broadcast the transaction only if a topic
-	// subscription is present
-	if p.topic != nil {
-		tx := &proto.Txn{
-			Raw: &any.Any{
-				Value: tele.MarshalRLP(),
-			},
-		}
-
-		if err := p.topic.Publish(tx); err != nil {
-			p.logger.Error("failed to topic tx", "err", err)
-		}
-	}
+	p.publish(tele)
 
-	return respString, nil
+	return &application.EdgeResponse{RespString: respString}, nil
 }
 
-func (p *TelegramPool) addAddrToHost(peerId string, host host.Host, addr string, relayAddr string) error {
-	if relayAddr != "" {
-		targetRelayInfo, err := peer.AddrInfoFromString(fmt.Sprintf("%s/p2p-circuit/p2p/%s", relayAddr, peerId))
-		if err != nil {
-			return err
-		}
-		host.Peerstore().AddAddrs(targetRelayInfo.ID, targetRelayInfo.Addrs, peerstore.AddressTTL)
-	} else if addr != "" {
-		addrInfo, err := peer.AddrInfoFromString(fmt.Sprintf("%s/p2p/%s", addr, peerId))
-		if err != nil {
-			return err
-		}
-		host.Peerstore().AddAddrs(addrInfo.ID, addrInfo.Addrs, peerstore.RecentlyConnectedAddrTTL)
+// publish broadcasts the telegram to the network
+// only if a topic subscription is present
+func (p *TelegramPool) publish(tele *types.Telegram) {
+	if p.topic == nil {
+		return
 	}
-	return nil
-}
 
-func (p *TelegramPool) newTempHost() (host.Host, error) {
-	var r io.Reader
-	r = rand.Reader
-	prvKey, _, err := crypto.GenerateKeyPairWithReader(crypto.RSA, 2048, r)
-	if err != nil {
-		return nil, err
-	}
-	listen, _ := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/10001")
-	clientHost, err := libp2p.New(
-		libp2p.ListenAddrs(listen),
-		libp2p.Security(noise.ID, noise.New),
-		libp2p.Identity(prvKey),
-	)
-	if err != nil {
-		return nil, err
+	tx := &proto.Txn{
+		Raw: &any.Any{
+			Value: tele.MarshalRLP(),
+		},
 	}
-	return clientHost, nil
-}
 
-func (p *TelegramPool) getAppPeerAddr(peerId string) (relayAddr string, addr string) {
-	if p.appSyncer != nil {
-		appPeer := p.appSyncer.GetAppPeer(peerId)
-		if appPeer != nil {
-			relayAddr = appPeer.Relay
-			addr = appPeer.Addr
-			return
-		}
+	if err := p.topic.Publish(tx); err != nil {
+		p.logger.Error("failed to topic tx", "err", err)
 	}
-	return "", ""
 }
 
 // addTele is the main entry point to the pool
@@ -433,66 +400,46 @@ func (p *TelegramPool) addTele(origin teleOrigin, tele *types.Telegram) (string,
 		}
 	}
 
-	// check for overflow
-	if p.gauge.read()+slotsRequired(tele) > p.gauge.max {
+	// check for overflow, evicting cheaper telegrams to make room
+	if p.gauge.read()+slotsRequired(tele) > p.gauge.max && !p.evictFor(tele) {
 		return "", ErrTxPoolOverflow
 	}
 
 	respString := ""
-	// telegram for edge call
-	if origin == local {
-		resp := &application.EdgeResponse{}
-		if tele.To != nil && *tele.To == contracts.EdgeCallPrecompile {
-			input := tele.Input
-			call := &application.EdgeCall{}
-			if err := json.Unmarshal(input, &call); err != nil {
-				return "", err
-			}
+	// telegram for edge call, not answered yet
+	if origin == local && tele.To != nil && *tele.To == contracts.EdgeCallPrecompile &&
+		tele.RespFrom == types.ZeroAddress {
+		resp, err := p.routeEdgeCall(tele)
+		if err != nil {
+			return "", err
+		}
 
-			host := p.edgeNetwork.GetHost()
+		setEdgeResponse(tele, resp)
+		respString = resp.RespString
+	}
+	tele.ComputeHash()
 
-			relayAddr, addr := p.getAppPeerAddr(call.PeerId)
-			p.logger.Info("edge call", "PeerId", call.PeerId, "Endpoint", call.Endpoint, "addr", addr, "Relay", relayAddr)
-			if relayAddr != "" || addr != "" {
-				clientHost, err2 := p.newTempHost()
-				if err2 != nil {
-					return "", err2
-				}
-				defer clientHost.Close()
+	// add to index
+	if ok := p.index.add(tele); !ok {
+		return "", ErrAlreadyKnown
+	}
 
-				host = clientHost
-				err := p.addAddrToHost(call.PeerId, host, addr, relayAddr)
-				if err != nil {
-					return "", err
-				}
-			}
+	// replace the pending telegram with the same nonce
+	replaced, err := p.replaceTele(tele)
+	if err != nil {
+		p.index.remove(tele)
 
-			// TODO relpace Call to CallWithFrom
-			//respBuf, callErr := application.CallWithFrom(p.edgeNetwork.GetHost(), application.ProtoTagEcApp, call, tele.From)
-			respBuf, callErr := application.Call(host, application.ProtoTagEcApp, call)
-			if callErr != nil {
-				return "", callErr
-			}
+		return "", err
+	}
 
-			err := resp.UnmarshalRLP(respBuf)
-			if err != nil {
-				return "", err
-			}
-			tele.RespFrom = resp.From
-			tele.RespR = resp.R
-			tele.RespV = resp.V
-			tele.RespS = resp.S
-			tele.RespHash = resp.Hash
-			if len(resp.RespString) > 0 {
-				respString = resp.RespString
-			}
+	if origin == local && p.journal != nil {
+		if err := p.journal.insert(tele); err != nil {
+			p.logger.Error("failed to journal telegram", "hash", tele.Hash, "err", err)
 		}
 	}
-	tele.ComputeHash()
 
-	// add to index
-	if ok := p.index.add(tele); !ok {
-		return "", ErrAlreadyKnown
+	if replaced {
+		return respString, nil
 	}
 
 	// initialize account for this address once
@@ -513,6 +460,26 @@ func (p *TelegramPool) validateTele(tele *types.Telegram) error {
 		return ErrOversizedData
 	}
 
+	// Dynamic fee telegrams are accepted once the latest block has a base fee
+	if tele.Type == types.DynamicFeeTx {
+		if p.store.Header().BaseFee == 0 {
+			return ErrTxTypeNotSupported
+		}
+
+		if tele.GasFeeCap == nil || tele.GasTipCap == nil {
+			return ErrUnderpriced
+		}
+
+		if tele.GasTipCap.Cmp(tele.GasFeeCap) > 0 {
+			return ErrTipAboveFeeCap
+		}
+
+		// the fee caps are the only prices of dynamic fee telegrams
+		if tele.GasPrice != nil && tele.GasPrice.Sign() != 0 {
+			return ErrGasPriceNotAllowed
+		}
+	}
+
 	// Check if the transaction is signed properly
 
 	// Extract the sender
@@ -607,6 +574,8 @@ func (p *TelegramPool) Start() {
 	// set default value of txpool pending transactions gauge
 	p.updatePending(0)
 
+	atomic.StoreUint64(&p.baseFee, p.store.Header().BaseFee)
+
 	//	run the handler for high gauge level pruning
 	go func() {
 		for {
@@ -625,10 +594,22 @@ func (p *TelegramPool) Start() {
 
 	//	run the handler for the tx pipeline
 	go func() {
+		// expire the enqueued txs periodically, if they have a lifetime
+		var expireCh <-chan time.Time
+
+		if p.lifetime > 0 {
+			ticker := time.NewTicker(expireInterval)
+			defer ticker.Stop()
+
+			expireCh = ticker.C
+		}
+
 		for {
 			select {
 			case <-p.shutdownCh:
 				return
+			case <-expireCh:
+				go p.expireEnqueued()
 			case req := <-p.enqueueReqCh:
 				go p.handleEnqueueRequest(req)
 			case req := <-p.promoteReqCh:
@@ -636,11 +617,34 @@ func (p *TelegramPool) Start() {
 			}
 		}
 	}()
+
+	if p.journal != nil {
+		p.loadJournal()
+
+		go p.journalLoop()
+	}
 }
 
 // Close shuts down the pool's main loop.
 func (p *TelegramPool) Close() {
 	p.eventManager.Close()
+
+	if err := p.dialer.Close(); err != nil {
+		p.logger.Error("failed to close edge dialer", "err", err)
+	}
+
+	if p.jobs != nil {
+		if err := p.jobs.Close(); err != nil {
+			p.logger.Error("failed to close edge jobs", "err", err)
+		}
+	}
+
+	if p.journal != nil {
+		if err := p.journal.Close(); err != nil {
+			p.logger.Error("failed to close telegram journal", "err", err)
+		}
+	}
+
 	p.shutdownCh <- struct{}{}
 }
 
@@ -650,6 +654,12 @@ func (p *TelegramPool) SetSigner(s signer) {
 	p.signer = s
 }
 
+// SetResponseSigner sets the signer the pool will use
+// to verify the edge responses of routed edge calls.
+func (p *TelegramPool) SetResponseSigner(s application.Signer) {
+	p.respSigner = s
+}
+
 // SetSealing sets the sealing flag
 func (p *TelegramPool) SetSealing(sealing bool) {
 	newValue := uint32(0)
@@ -750,12 +760,11 @@ func (p *TelegramPool) handlePromoteRequest(req promoteRequest) {
 }
 
 // Prepare generates all the transactions
-// ready for execution. (primaries)
-func (p *TelegramPool) Prepare() {
+// ready for execution (primaries), ordered
+// by their tip at the given block base fee.
+func (p *TelegramPool) Prepare(baseFee uint64) {
 	// clear from previous round
-	if p.executables.length() != 0 {
-		p.executables.clear()
-	}
+	p.executables.clear(baseFee)
 
 	// fetch primary from each account
 	primaries := p.accounts.getPrimaries()
@@ -789,8 +798,10 @@ func (p *TelegramPool) Pop(tx *types.Telegram) {
 	account.promoted.lock(true)
 	defer account.promoted.unlock()
 
-	// pop the top most promoted tx
-	account.promoted.pop()
+	// pop the top most promoted tx, unless it was evicted
+	if popped := account.promoted.pop(); popped == nil {
+		return
+	}
 
 	// successfully popping an account resets its demotions count to 0
 	account.resetDemotions()
@@ -894,9 +905,13 @@ func (p *TelegramPool) ResetWithHeaders(headers ...*types.Header) {
 // in the received event. Resets all known accounts with the new nonce.
 func (p *TelegramPool) processEvent(event *blockchain.Event) {
 	// Grab the latest state root now that the block has been inserted
-	stateRoot := p.store.Header().StateRoot
+	latest := p.store.Header()
+	stateRoot := latest.StateRoot
 	stateNonces := make(map[types.Address]uint64)
 
+	// eviction compares the tips at the latest base fee
+	atomic.StoreUint64(&p.baseFee, latest.BaseFee)
+
 	// discover latest (next) nonces for all accounts
 	for _, header := range event.NewChain {
 		block, ok := p.store.GetBlockByHash(header.Hash, true)
@@ -25,6 +25,9 @@ type Header struct {
 
 	GasLimit uint64
 	GasUsed  uint64
+
+	// BaseFee is the base fee per gas of the dynamic fee telegrams, zero before the EIP1559 fork
+	BaseFee uint64
 }
 
 func (h *Header) Equal(hh *Header) bool {
@@ -71,6 +74,7 @@ func (h *Header) Copy() *Header {
 		Timestamp:    h.Timestamp,
 		GasLimit:     h.GasLimit,
 		GasUsed:      h.GasUsed,
+		BaseFee:      h.BaseFee,
 	}
 
 	newHeader.Miner = make([]byte, len(h.Miner))
@@ -330,3 +330,91 @@ func testRLPData(arena *fastrlp.Arena, ommitValues map[string]bool) []byte {
 
 	return testData
 }
+
+func TestRLPMarshall_And_Unmarshall_DynamicFeeTelegram(t *testing.T) {
+	addrTo := StringToAddress("11")
+	originalTx := &Telegram{
+		Type:      DynamicFeeTx,
+		Nonce:     1,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(12),
+		Gas:       11,
+		To:        &addrTo,
+		Value:     big.NewInt(1),
+		Input:     []byte{1, 2},
+		V:         big.NewInt(25),
+		S:         big.NewInt(26),
+		R:         big.NewInt(27),
+	}
+	originalTx.ComputeHash()
+
+	unmarshalledTx := new(Telegram)
+	assert.NoError(t, unmarshalledTx.UnmarshalRLP(originalTx.MarshalRLP()))
+
+	unmarshalledTx.ComputeHash()
+	assert.Equal(t, DynamicFeeTx, unmarshalledTx.Type)
+	assert.Equal(t, originalTx.GasTipCap, unmarshalledTx.GasTipCap)
+	assert.Equal(t, originalTx.GasFeeCap, unmarshalledTx.GasFeeCap)
+	assert.Equal(t, originalTx.Hash, unmarshalledTx.Hash)
+
+	// the price paid is the base fee plus the tip, up to the fee cap
+	assert.Equal(t, big.NewInt(7), originalTx.EffectiveGasPrice(big.NewInt(5)))
+	assert.Equal(t, big.NewInt(12), originalTx.EffectiveGasPrice(big.NewInt(11)))
+	assert.Equal(t, big.NewInt(1), originalTx.EffectiveTip(big.NewInt(11)))
+}
+
+func TestRLPUnmarshal_Header_BaseFee(t *testing.T) {
+	h := &Header{Number: 1, BaseFee: 1000}
+	h.ComputeHash()
+
+	h2 := new(Header)
+	assert.NoError(t, h2.UnmarshalRLP(h.MarshalRLP()))
+	assert.Equal(t, uint64(1000), h2.BaseFee)
+	assert.Equal(t, h.Hash, h2.Hash)
+
+	// headers without base fee keep their encoding
+	withBaseFee := h.Hash
+	h.BaseFee = 0
+	h.ComputeHash()
+
+	assert.NotEqual(t, withBaseFee, h.Hash)
+	assert.Equal(t, h.Hash, (&Header{Number: 1}).ComputeHash().Hash)
+}
+
+func TestRLPMarshall_And_Unmarshall_TelegramRespBinding(t *testing.T) {
+	addrTo := StringToAddress("11")
+
+	for _, teleType := range []TeleType{LegacyTx, StateTx, DynamicFeeTx} {
+		originalTx := &Telegram{
+			Type:          teleType,
+			Nonce:         1,
+			GasPrice:      big.NewInt(0),
+			GasTipCap:     big.NewInt(2),
+			GasFeeCap:     big.NewInt(12),
+			Gas:           11,
+			To:            &addrTo,
+			Value:         big.NewInt(1),
+			Input:         []byte{1, 2},
+			V:             big.NewInt(25),
+			S:             big.NewInt(26),
+			R:             big.NewInt(27),
+			RespFrom:      StringToAddress("22"),
+			RespHash:      StringToHash("33"),
+			RespDigest:    StringToHash("44"),
+			RespStatus:    200,
+			RespProvider:  "16Uiu2HAm",
+			RespTimestamp: 1700000000,
+		}
+		originalTx.ComputeHash()
+
+		unmarshalledTx := new(Telegram)
+		assert.NoError(t, unmarshalledTx.UnmarshalRLP(originalTx.MarshalRLP()))
+
+		assert.True(t, unmarshalledTx.HasRespBinding())
+		assert.Equal(t, originalTx.RespDigest, unmarshalledTx.RespDigest)
+		assert.Equal(t, originalTx.RespStatus, unmarshalledTx.RespStatus)
+		assert.Equal(t, originalTx.RespProvider, unmarshalledTx.RespProvider)
+		assert.Equal(t, originalTx.RespTimestamp, unmarshalledTx.RespTimestamp)
+		assert.Equal(t, originalTx.Hash, unmarshalledTx.ComputeHash().Hash)
+	}
+}
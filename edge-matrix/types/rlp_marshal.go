@@ -91,6 +91,11 @@ func (h *Header) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 	//vv.Set(arena.NewBytes(h.MixHash.Bytes()))
 	vv.Set(arena.NewCopyBytes(h.Nonce[:]))
 
+	// the base fee is only part of the headers of the EIP1559 fork
+	if h.BaseFee != 0 {
+		vv.Set(arena.NewUint(h.BaseFee))
+	}
+
 	return vv
 }
 
@@ -221,5 +226,18 @@ func (t *Telegram) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 		vv.Set(arena.NewBytes((t.From).Bytes()))
 	}
 
+	if t.Type == DynamicFeeTx {
+		vv.Set(arena.NewBigInt(t.GasTipCap))
+		vv.Set(arena.NewBigInt(t.GasFeeCap))
+	}
+
+	// edge call response binding, only set for bound responses
+	if t.HasRespBinding() {
+		vv.Set(arena.NewBytes((t.RespDigest).Bytes()))
+		vv.Set(arena.NewUint(t.RespStatus))
+		vv.Set(arena.NewBytes([]byte(t.RespProvider)))
+		vv.Set(arena.NewUint(t.RespTimestamp))
+	}
+
 	return vv
 }
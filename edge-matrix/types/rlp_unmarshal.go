@@ -218,6 +218,13 @@ func (h *Header) unmarshalRLPFrom(_ *fastrlp.Parser, v *fastrlp.Value) error {
 
 	h.SetNonce(nonce)
 
+	// baseFee
+	if len(elems) > 12 {
+		if h.BaseFee, err = elems[12].GetUint64(); err != nil {
+			return err
+		}
+	}
+
 	// compute the hash after the decoding
 	h.ComputeHash()
 
@@ -489,5 +496,52 @@ func (t *Telegram) unmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 		}
 	}
 
+	if t.Type == DynamicFeeTx {
+		if len(elems) < 16 {
+			return fmt.Errorf("incorrect number of elements to decode dynamic fee telegram, expected 16 but found %d", len(elems))
+		}
+
+		t.GasTipCap = new(big.Int)
+		if err = elems[14].GetBigInt(t.GasTipCap); err != nil {
+			return err
+		}
+
+		t.GasFeeCap = new(big.Int)
+		if err = elems[15].GetBigInt(t.GasFeeCap); err != nil {
+			return err
+		}
+	}
+
+	// edge call response binding, following the fields of the telegram type
+	offset := 14
+
+	switch t.Type {
+	case StateTx:
+		offset = 15
+	case DynamicFeeTx:
+		offset = 16
+	}
+
+	if len(elems) >= offset+4 {
+		if vv, _ := v.Get(offset).Bytes(); len(vv) == HashLength {
+			t.RespDigest = BytesToHash(vv)
+		}
+
+		if t.RespStatus, err = elems[offset+1].GetUint64(); err != nil {
+			return err
+		}
+
+		provider, err := elems[offset+2].GetBytes(nil)
+		if err != nil {
+			return err
+		}
+
+		t.RespProvider = string(provider)
+
+		if t.RespTimestamp, err = elems[offset+3].GetUint64(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -11,8 +11,9 @@ import (
 type TeleType byte
 
 const (
-	LegacyTx TeleType = 0x0
-	StateTx  TeleType = 0x7f
+	LegacyTx     TeleType = 0x0
+	DynamicFeeTx TeleType = 0x2
+	StateTx      TeleType = 0x7f
 
 	StateTransactionGasLimit = 1000000 // some arbitrary default gas limit for state transactions
 )
@@ -21,7 +22,7 @@ func txTypeFromByte(b byte) (TeleType, error) {
 	tt := TeleType(b)
 
 	switch tt {
-	case LegacyTx, StateTx:
+	case LegacyTx, DynamicFeeTx, StateTx:
 		return tt, nil
 	default:
 		return tt, fmt.Errorf("unknown transaction type: %d", b)
@@ -32,6 +33,8 @@ func (t TeleType) String() (s string) {
 	switch t {
 	case LegacyTx:
 		return "LegacyTx"
+	case DynamicFeeTx:
+		return "DynamicFeeTx"
 	case StateTx:
 		return "StateTx"
 	}
@@ -59,12 +62,28 @@ type Telegram struct {
 	RespHash Hash
 	RespFrom Address
 
+	// binding of the edge call response, RespHash being recomputed from it on chain
+	// along with the request hash, the sender and the nonce of the telegram
+	RespDigest    Hash
+	RespStatus    uint64
+	RespProvider  string
+	RespTimestamp uint64
+
 	Type TeleType
 
+	// fee caps of the dynamic fee telegrams, which leave GasPrice zero
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+
 	// Cache
 	size atomic.Value
 }
 
+// HasRespBinding returns true if the edge call response of the telegram is bound to its request
+func (t *Telegram) HasRespBinding() bool {
+	return t.RespTimestamp != 0
+}
+
 // IsContractCreation checks if tx is contract creation
 func (t *Telegram) IsContractCreation() bool {
 	return t.To == nil
@@ -98,6 +117,14 @@ func (t *Telegram) Copy() *Telegram {
 		tt.Value.Set(t.Value)
 	}
 
+	if t.GasTipCap != nil {
+		tt.GasTipCap = new(big.Int).Set(t.GasTipCap)
+	}
+
+	if t.GasFeeCap != nil {
+		tt.GasFeeCap = new(big.Int).Set(t.GasFeeCap)
+	}
+
 	if t.R != nil {
 		tt.R = new(big.Int)
 		tt.R = big.NewInt(0).SetBits(t.R.Bits())
@@ -114,14 +141,65 @@ func (t *Telegram) Copy() *Telegram {
 	return tt
 }
 
-// Cost returns gas * gasPrice + value
+// Cost returns gas * gasPrice + value, the gas price of dynamic fee telegrams being their fee cap
 func (t *Telegram) Cost() *big.Int {
-	total := new(big.Int).Mul(t.GasPrice, new(big.Int).SetUint64(t.Gas))
+	total := new(big.Int).Mul(t.GetGasFeeCap(), new(big.Int).SetUint64(t.Gas))
 	total.Add(total, t.Value)
 
 	return total
 }
 
+// GetGasTipCap returns the max tip per gas paid to the block creator, the gas price of legacy telegrams
+func (t *Telegram) GetGasTipCap() *big.Int {
+	if t.Type == DynamicFeeTx {
+		return bigOrZero(t.GasTipCap)
+	}
+
+	return bigOrZero(t.GasPrice)
+}
+
+// GetGasFeeCap returns the max fee per gas of the telegram, the gas price of legacy telegrams
+func (t *Telegram) GetGasFeeCap() *big.Int {
+	if t.Type == DynamicFeeTx {
+		return bigOrZero(t.GasFeeCap)
+	}
+
+	return bigOrZero(t.GasPrice)
+}
+
+// EffectiveGasPrice returns the gas price paid by the telegram at the given base fee:
+// the base fee plus the tip, up to the fee cap
+func (t *Telegram) EffectiveGasPrice(baseFee *big.Int) *big.Int {
+	if t.Type != DynamicFeeTx || baseFee == nil {
+		return new(big.Int).Set(t.GetGasFeeCap())
+	}
+
+	price := new(big.Int).Add(baseFee, t.GetGasTipCap())
+	if feeCap := t.GetGasFeeCap(); price.Cmp(feeCap) > 0 {
+		price.Set(feeCap)
+	}
+
+	return price
+}
+
+// EffectiveTip returns the tip per gas paid to the block creator at the given base fee,
+// negative if the fee cap is below the base fee
+func (t *Telegram) EffectiveTip(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return new(big.Int).Set(t.GetGasTipCap())
+	}
+
+	return new(big.Int).Sub(t.EffectiveGasPrice(baseFee), baseFee)
+}
+
+func bigOrZero(i *big.Int) *big.Int {
+	if i == nil {
+		return new(big.Int)
+	}
+
+	return i
+}
+
 func (t *Telegram) Size() uint64 {
 	if size := t.size.Load(); size != nil {
 		sizeVal, ok := size.(uint64)
@@ -143,5 +221,5 @@ func (t *Telegram) ExceedsBlockGasLimit(blockGasLimit uint64) bool {
 }
 
 func (t *Telegram) IsUnderpriced(priceLimit uint64) bool {
-	return t.GasPrice.Cmp(big.NewInt(0).SetUint64(priceLimit)) < 0
+	return t.GetGasTipCap().Cmp(big.NewInt(0).SetUint64(priceLimit)) < 0
 }